@@ -0,0 +1,85 @@
+package notion
+
+import "context"
+
+//go:generate go run ./internal/cmd/mockgen -source api.go -interface API -type MockClient -out mock_api.go
+
+// The interfaces below describe the Notion API methods of Client, per resource,
+// so code that uses the API can depend on the subset it needs, and be tested
+// with MockClient (or another implementation) instead of a real client.
+
+// DatabaseAPI is implemented by Client.
+// See: https://developers.notion.com/reference/database
+type DatabaseAPI interface {
+	FindDatabaseByID(ctx context.Context, id string) (Database, error)
+	QueryDatabase(ctx context.Context, id string, query *DatabaseQuery) (DatabaseQueryResponse, error)
+	CreateDatabase(ctx context.Context, params CreateDatabaseParams) (Database, error)
+	UpdateDatabase(ctx context.Context, databaseID string, params UpdateDatabaseParams) (Database, error)
+}
+
+// PageAPI is implemented by Client.
+// See: https://developers.notion.com/reference/page
+type PageAPI interface {
+	FindPageByID(ctx context.Context, id string) (Page, error)
+	CreatePage(ctx context.Context, params CreatePageParams) (Page, error)
+	UpdatePage(ctx context.Context, pageID string, params UpdatePageParams) (Page, error)
+	FindPagePropertyByID(ctx context.Context, pageID, propID string, query *PaginationQuery) (PagePropResponse, error)
+}
+
+// BlockAPI is implemented by Client.
+// See: https://developers.notion.com/reference/block
+type BlockAPI interface {
+	FindBlockChildrenByID(ctx context.Context, blockID string, query *PaginationQuery) (BlockChildrenResponse, error)
+	AppendBlockChildren(ctx context.Context, blockID string, children []Block) (BlockChildrenResponse, error)
+	FindBlockByID(ctx context.Context, blockID string) (Block, error)
+	UpdateBlock(ctx context.Context, blockID string, block Block) (Block, error)
+	DeleteBlock(ctx context.Context, blockID string) (Block, error)
+}
+
+// UserAPI is implemented by Client.
+// See: https://developers.notion.com/reference/user
+type UserAPI interface {
+	FindUserByID(ctx context.Context, id string) (User, error)
+	FindCurrentUser(ctx context.Context) (User, error)
+	ListUsers(ctx context.Context, query *PaginationQuery) (ListUsersResponse, error)
+}
+
+// SearchAPI is implemented by Client.
+// See: https://developers.notion.com/reference/post-search
+type SearchAPI interface {
+	Search(ctx context.Context, opts *SearchOpts) (SearchResponse, error)
+	SearchAll(ctx context.Context, opts *SearchAllOpts) (SearchResults, error)
+}
+
+// CommentAPI is implemented by Client.
+// See: https://developers.notion.com/reference/comment-object
+type CommentAPI interface {
+	CreateComment(ctx context.Context, params CreateCommentParams) (Comment, error)
+	FindCommentsByBlockID(ctx context.Context, query FindCommentsByBlockIDQuery) (FindCommentsResponse, error)
+}
+
+// FileUploadAPI is implemented by Client.
+// See: https://developers.notion.com/reference/file-upload
+type FileUploadAPI interface {
+	CreateFileUpload(ctx context.Context, params CreateFileUploadParams) (FileUpload, error)
+	SendFileUpload(ctx context.Context, fileUploadID string, params SendFileUploadParams) (FileUpload, error)
+	CompleteFileUpload(ctx context.Context, fileUploadID string) (FileUpload, error)
+	FindFileUploadByID(ctx context.Context, fileUploadID string) (FileUpload, error)
+	FindFileUploads(ctx context.Context, query *FindFileUploadsQuery) (FindFileUploadsResponse, error)
+}
+
+// API is the complete Notion API, as implemented by Client.
+type API interface {
+	DatabaseAPI
+	PageAPI
+	BlockAPI
+	UserAPI
+	SearchAPI
+	CommentAPI
+	FileUploadAPI
+}
+
+var (
+	_ API = (*Client)(nil)
+	_ API = (*MockClient)(nil)
+)
@@ -0,0 +1,62 @@
+package notion_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+)
+
+func TestMockClient(t *testing.T) {
+	t.Parallel()
+
+	mock := &notion.MockClient{
+		FindPageByIDFunc: func(ctx context.Context, id string) (notion.Page, error) {
+			if id == "missing" {
+				return notion.Page{}, notion.ErrObjectNotFound
+			}
+			return notion.Page{ID: id}, nil
+		},
+	}
+
+	var api notion.PageAPI = mock
+
+	page, err := api.FindPageByID(context.Background(), "cb261dc5-6c85-4767-8585-3852382fb466")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp, got := "cb261dc5-6c85-4767-8585-3852382fb466", page.ID; exp != got {
+		t.Fatalf("page ID not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	if _, err := api.FindPageByID(context.Background(), "missing"); !errors.Is(err, notion.ErrObjectNotFound) {
+		t.Fatalf("expected notion.ErrObjectNotFound, got: %v", err)
+	}
+
+	// Methods without func return zero values.
+	user, err := mock.FindCurrentUser(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(notion.User{}, user); diff != "" {
+		t.Fatalf("user not equal (-exp, +got):\n%v", diff)
+	}
+
+	calls := mock.CallsTo("FindPageByID")
+	if len(calls) != 2 {
+		t.Fatalf("calls not equal (expected: 2, got: %v)", len(calls))
+	}
+	if exp, got := "missing", calls[1].Args[1]; exp != got {
+		t.Fatalf("call argument not equal (expected: %v, got: %v)", exp, got)
+	}
+	if exp, got := 3, len(mock.Calls()); exp != got {
+		t.Fatalf("calls not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	mock.Reset()
+	if len(mock.Calls()) != 0 {
+		t.Fatal("expected no calls after reset")
+	}
+}
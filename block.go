@@ -24,6 +24,72 @@ type Block interface {
 	json.Marshaler
 }
 
+// TypedBlock is a block that reports its type. All block types of this package
+// implement it. It's separate from Block, so that block types defined outside
+// of this package don't need to implement it. See: BlockTypeOf.
+type TypedBlock interface {
+	Block
+	BlockType() BlockType
+}
+
+// BlockTypeOf returns the type of a block. For blocks that don't implement
+// TypedBlock, the type is read from the block's JSON encoding, which has the
+// block content as its only key. If that fails, an empty type is returned.
+func BlockTypeOf(b Block) BlockType {
+	if tb, ok := b.(TypedBlock); ok {
+		return tb.BlockType()
+	}
+
+	content, err := b.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(content, &obj); err != nil || len(obj) != 1 {
+		return ""
+	}
+	for key := range obj {
+		return BlockType(key)
+	}
+
+	return ""
+}
+
+// The interfaces below are implemented by (pointers to) block structs that
+// share a kind of content, so it can be read and replaced without a type
+// switch. Getters use value receivers and setters pointer receivers, so only
+// pointers satisfy the interfaces; blocks decoded from API responses are
+// pointers already. The `Get` prefix avoids clashing with the struct fields.
+
+// RichTexter is implemented by blocks with a rich text body.
+type RichTexter interface {
+	Block
+	GetRichText() []RichText
+	SetRichText(richText []RichText)
+}
+
+// Parenter is implemented by blocks that can have child blocks.
+type Parenter interface {
+	Block
+	GetChildren() []Block
+	SetChildren(children []Block) error
+}
+
+// Colorer is implemented by blocks with a color.
+type Colorer interface {
+	Block
+	GetColor() Color
+	SetColor(color Color)
+}
+
+// Captioner is implemented by blocks with a caption.
+type Captioner interface {
+	Block
+	GetCaption() []RichText
+	SetCaption(caption []RichText)
+}
+
 type blockDTO struct {
 	ID             string     `json:"id,omitempty"`
 	Parent         *Parent    `json:"parent,omitempty"`
@@ -143,6 +209,42 @@ func (b ParagraphBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b ParagraphBlock) BlockType() BlockType {
+	return BlockTypeParagraph
+}
+
+// GetRichText implements RichTexter.
+func (b ParagraphBlock) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *ParagraphBlock) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b ParagraphBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *ParagraphBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
+// GetColor implements Colorer.
+func (b ParagraphBlock) GetColor() Color {
+	return b.Color
+}
+
+// SetColor implements Colorer.
+func (b *ParagraphBlock) SetColor(color Color) {
+	b.Color = color
+}
+
 type BulletedListItemBlock struct {
 	BaseBlock
 
@@ -165,6 +267,42 @@ func (b BulletedListItemBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b BulletedListItemBlock) BlockType() BlockType {
+	return BlockTypeBulletedListItem
+}
+
+// GetRichText implements RichTexter.
+func (b BulletedListItemBlock) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *BulletedListItemBlock) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b BulletedListItemBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *BulletedListItemBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
+// GetColor implements Colorer.
+func (b BulletedListItemBlock) GetColor() Color {
+	return b.Color
+}
+
+// SetColor implements Colorer.
+func (b *BulletedListItemBlock) SetColor(color Color) {
+	b.Color = color
+}
+
 type NumberedListItemBlock struct {
 	BaseBlock
 
@@ -187,6 +325,42 @@ func (b NumberedListItemBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b NumberedListItemBlock) BlockType() BlockType {
+	return BlockTypeNumberedListItem
+}
+
+// GetRichText implements RichTexter.
+func (b NumberedListItemBlock) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *NumberedListItemBlock) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b NumberedListItemBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *NumberedListItemBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
+// GetColor implements Colorer.
+func (b NumberedListItemBlock) GetColor() Color {
+	return b.Color
+}
+
+// SetColor implements Colorer.
+func (b *NumberedListItemBlock) SetColor(color Color) {
+	b.Color = color
+}
+
 type QuoteBlock struct {
 	BaseBlock
 
@@ -209,6 +383,42 @@ func (b QuoteBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b QuoteBlock) BlockType() BlockType {
+	return BlockTypeQuote
+}
+
+// GetRichText implements RichTexter.
+func (b QuoteBlock) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *QuoteBlock) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b QuoteBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *QuoteBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
+// GetColor implements Colorer.
+func (b QuoteBlock) GetColor() Color {
+	return b.Color
+}
+
+// SetColor implements Colorer.
+func (b *QuoteBlock) SetColor(color Color) {
+	b.Color = color
+}
+
 type ToggleBlock struct {
 	BaseBlock
 
@@ -231,6 +441,42 @@ func (b ToggleBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b ToggleBlock) BlockType() BlockType {
+	return BlockTypeToggle
+}
+
+// GetRichText implements RichTexter.
+func (b ToggleBlock) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *ToggleBlock) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b ToggleBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *ToggleBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
+// GetColor implements Colorer.
+func (b ToggleBlock) GetColor() Color {
+	return b.Color
+}
+
+// SetColor implements Colorer.
+func (b *ToggleBlock) SetColor(color Color) {
+	b.Color = color
+}
+
 type TemplateBlock struct {
 	BaseBlock
 
@@ -252,6 +498,32 @@ func (b TemplateBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b TemplateBlock) BlockType() BlockType {
+	return BlockTypeTemplate
+}
+
+// GetRichText implements RichTexter.
+func (b TemplateBlock) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *TemplateBlock) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b TemplateBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *TemplateBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
 type Heading1Block struct {
 	BaseBlock
 
@@ -275,6 +547,42 @@ func (b Heading1Block) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b Heading1Block) BlockType() BlockType {
+	return BlockTypeHeading1
+}
+
+// GetRichText implements RichTexter.
+func (b Heading1Block) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *Heading1Block) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b Heading1Block) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *Heading1Block) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
+// GetColor implements Colorer.
+func (b Heading1Block) GetColor() Color {
+	return b.Color
+}
+
+// SetColor implements Colorer.
+func (b *Heading1Block) SetColor(color Color) {
+	b.Color = color
+}
+
 type Heading2Block struct {
 	BaseBlock
 
@@ -298,6 +606,42 @@ func (b Heading2Block) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b Heading2Block) BlockType() BlockType {
+	return BlockTypeHeading2
+}
+
+// GetRichText implements RichTexter.
+func (b Heading2Block) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *Heading2Block) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b Heading2Block) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *Heading2Block) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
+// GetColor implements Colorer.
+func (b Heading2Block) GetColor() Color {
+	return b.Color
+}
+
+// SetColor implements Colorer.
+func (b *Heading2Block) SetColor(color Color) {
+	b.Color = color
+}
+
 type Heading3Block struct {
 	BaseBlock
 
@@ -321,6 +665,42 @@ func (b Heading3Block) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b Heading3Block) BlockType() BlockType {
+	return BlockTypeHeading3
+}
+
+// GetRichText implements RichTexter.
+func (b Heading3Block) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *Heading3Block) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b Heading3Block) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *Heading3Block) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
+// GetColor implements Colorer.
+func (b Heading3Block) GetColor() Color {
+	return b.Color
+}
+
+// SetColor implements Colorer.
+func (b *Heading3Block) SetColor(color Color) {
+	b.Color = color
+}
+
 type ToDoBlock struct {
 	BaseBlock
 
@@ -344,6 +724,42 @@ func (b ToDoBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b ToDoBlock) BlockType() BlockType {
+	return BlockTypeToDo
+}
+
+// GetRichText implements RichTexter.
+func (b ToDoBlock) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *ToDoBlock) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b ToDoBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *ToDoBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
+// GetColor implements Colorer.
+func (b ToDoBlock) GetColor() Color {
+	return b.Color
+}
+
+// SetColor implements Colorer.
+func (b *ToDoBlock) SetColor(color Color) {
+	b.Color = color
+}
+
 type ChildPageBlock struct {
 	BaseBlock
 
@@ -364,6 +780,11 @@ func (b ChildPageBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b ChildPageBlock) BlockType() BlockType {
+	return BlockTypeChildPage
+}
+
 type ChildDatabaseBlock struct {
 	BaseBlock
 
@@ -384,6 +805,11 @@ func (b ChildDatabaseBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b ChildDatabaseBlock) BlockType() BlockType {
+	return BlockTypeChildDatabase
+}
+
 type CalloutBlock struct {
 	BaseBlock
 
@@ -407,6 +833,42 @@ func (b CalloutBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b CalloutBlock) BlockType() BlockType {
+	return BlockTypeCallout
+}
+
+// GetRichText implements RichTexter.
+func (b CalloutBlock) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *CalloutBlock) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b CalloutBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *CalloutBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
+// GetColor implements Colorer.
+func (b CalloutBlock) GetColor() Color {
+	return b.Color
+}
+
+// SetColor implements Colorer.
+func (b *CalloutBlock) SetColor(color Color) {
+	b.Color = color
+}
+
 type CodeBlock struct {
 	BaseBlock
 
@@ -430,6 +892,42 @@ func (b CodeBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b CodeBlock) BlockType() BlockType {
+	return BlockTypeCode
+}
+
+// GetRichText implements RichTexter.
+func (b CodeBlock) GetRichText() []RichText {
+	return b.RichText
+}
+
+// SetRichText implements RichTexter.
+func (b *CodeBlock) SetRichText(richText []RichText) {
+	b.RichText = richText
+}
+
+// GetChildren implements Parenter.
+func (b CodeBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *CodeBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
+// GetCaption implements Captioner.
+func (b CodeBlock) GetCaption() []RichText {
+	return b.Caption
+}
+
+// SetCaption implements Captioner.
+func (b *CodeBlock) SetCaption(caption []RichText) {
+	b.Caption = caption
+}
+
 type EmbedBlock struct {
 	BaseBlock
 
@@ -450,13 +948,19 @@ func (b EmbedBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b EmbedBlock) BlockType() BlockType {
+	return BlockTypeEmbed
+}
+
 type ImageBlock struct {
 	BaseBlock
 
-	Type     FileType      `json:"type"`
-	File     *FileFile     `json:"file,omitempty"`
-	External *FileExternal `json:"external,omitempty"`
-	Caption  []RichText    `json:"caption,omitempty"`
+	Type       FileType        `json:"type"`
+	File       *FileFile       `json:"file,omitempty"`
+	External   *FileExternal   `json:"external,omitempty"`
+	FileUpload *FileUploadFile `json:"file_upload,omitempty"`
+	Caption    []RichText      `json:"caption,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler.
@@ -473,13 +977,29 @@ func (b ImageBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b ImageBlock) BlockType() BlockType {
+	return BlockTypeImage
+}
+
+// GetCaption implements Captioner.
+func (b ImageBlock) GetCaption() []RichText {
+	return b.Caption
+}
+
+// SetCaption implements Captioner.
+func (b *ImageBlock) SetCaption(caption []RichText) {
+	b.Caption = caption
+}
+
 type AudioBlock struct {
 	BaseBlock
 
-	Type     FileType      `json:"type"`
-	File     *FileFile     `json:"file,omitempty"`
-	External *FileExternal `json:"external,omitempty"`
-	Caption  []RichText    `json:"caption,omitempty"`
+	Type       FileType        `json:"type"`
+	File       *FileFile       `json:"file,omitempty"`
+	External   *FileExternal   `json:"external,omitempty"`
+	FileUpload *FileUploadFile `json:"file_upload,omitempty"`
+	Caption    []RichText      `json:"caption,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler.
@@ -496,13 +1016,29 @@ func (b AudioBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b AudioBlock) BlockType() BlockType {
+	return BlockTypeAudio
+}
+
+// GetCaption implements Captioner.
+func (b AudioBlock) GetCaption() []RichText {
+	return b.Caption
+}
+
+// SetCaption implements Captioner.
+func (b *AudioBlock) SetCaption(caption []RichText) {
+	b.Caption = caption
+}
+
 type VideoBlock struct {
 	BaseBlock
 
-	Type     FileType      `json:"type"`
-	File     *FileFile     `json:"file,omitempty"`
-	External *FileExternal `json:"external,omitempty"`
-	Caption  []RichText    `json:"caption,omitempty"`
+	Type       FileType        `json:"type"`
+	File       *FileFile       `json:"file,omitempty"`
+	External   *FileExternal   `json:"external,omitempty"`
+	FileUpload *FileUploadFile `json:"file_upload,omitempty"`
+	Caption    []RichText      `json:"caption,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler.
@@ -519,13 +1055,29 @@ func (b VideoBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b VideoBlock) BlockType() BlockType {
+	return BlockTypeVideo
+}
+
+// GetCaption implements Captioner.
+func (b VideoBlock) GetCaption() []RichText {
+	return b.Caption
+}
+
+// SetCaption implements Captioner.
+func (b *VideoBlock) SetCaption(caption []RichText) {
+	b.Caption = caption
+}
+
 type FileBlock struct {
 	BaseBlock
 
-	Type     FileType      `json:"type"`
-	File     *FileFile     `json:"file,omitempty"`
-	External *FileExternal `json:"external,omitempty"`
-	Caption  []RichText    `json:"caption,omitempty"`
+	Type       FileType        `json:"type"`
+	File       *FileFile       `json:"file,omitempty"`
+	External   *FileExternal   `json:"external,omitempty"`
+	FileUpload *FileUploadFile `json:"file_upload,omitempty"`
+	Caption    []RichText      `json:"caption,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler.
@@ -542,13 +1094,29 @@ func (b FileBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b FileBlock) BlockType() BlockType {
+	return BlockTypeFile
+}
+
+// GetCaption implements Captioner.
+func (b FileBlock) GetCaption() []RichText {
+	return b.Caption
+}
+
+// SetCaption implements Captioner.
+func (b *FileBlock) SetCaption(caption []RichText) {
+	b.Caption = caption
+}
+
 type PDFBlock struct {
 	BaseBlock
 
-	Type     FileType      `json:"type"`
-	File     *FileFile     `json:"file,omitempty"`
-	External *FileExternal `json:"external,omitempty"`
-	Caption  []RichText    `json:"caption,omitempty"`
+	Type       FileType        `json:"type"`
+	File       *FileFile       `json:"file,omitempty"`
+	External   *FileExternal   `json:"external,omitempty"`
+	FileUpload *FileUploadFile `json:"file_upload,omitempty"`
+	Caption    []RichText      `json:"caption,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler.
@@ -565,6 +1133,21 @@ func (b PDFBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b PDFBlock) BlockType() BlockType {
+	return BlockTypePDF
+}
+
+// GetCaption implements Captioner.
+func (b PDFBlock) GetCaption() []RichText {
+	return b.Caption
+}
+
+// SetCaption implements Captioner.
+func (b *PDFBlock) SetCaption(caption []RichText) {
+	b.Caption = caption
+}
+
 type BookmarkBlock struct {
 	BaseBlock
 
@@ -586,6 +1169,21 @@ func (b BookmarkBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b BookmarkBlock) BlockType() BlockType {
+	return BlockTypeBookmark
+}
+
+// GetCaption implements Captioner.
+func (b BookmarkBlock) GetCaption() []RichText {
+	return b.Caption
+}
+
+// SetCaption implements Captioner.
+func (b *BookmarkBlock) SetCaption(caption []RichText) {
+	b.Caption = caption
+}
+
 type EquationBlock struct {
 	BaseBlock
 
@@ -606,6 +1204,11 @@ func (b EquationBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b EquationBlock) BlockType() BlockType {
+	return BlockTypeEquation
+}
+
 type ColumnListBlock struct {
 	BaseBlock
 
@@ -626,6 +1229,50 @@ func (b ColumnListBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b ColumnListBlock) BlockType() BlockType {
+	return BlockTypeColumnList
+}
+
+// GetChildren implements Parenter.
+func (b ColumnListBlock) GetChildren() []Block {
+	if b.Children == nil {
+		return nil
+	}
+
+	children := make([]Block, len(b.Children))
+	for i := range b.Children {
+		children[i] = b.Children[i]
+	}
+
+	return children
+}
+
+// SetChildren implements Parenter. Each child must be a ColumnBlock.
+func (b *ColumnListBlock) SetChildren(children []Block) error {
+	if children == nil {
+		b.Children = nil
+		return nil
+	}
+
+	columns := make([]ColumnBlock, len(children))
+
+	for i, child := range children {
+		switch v := child.(type) {
+		case ColumnBlock:
+			columns[i] = v
+		case *ColumnBlock:
+			columns[i] = *v
+		default:
+			return fmt.Errorf("column list child at index %v is not a column block", i)
+		}
+	}
+
+	b.Children = columns
+
+	return nil
+}
+
 type ColumnBlock struct {
 	BaseBlock
 
@@ -646,6 +1293,22 @@ func (b ColumnBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b ColumnBlock) BlockType() BlockType {
+	return BlockTypeColumn
+}
+
+// GetChildren implements Parenter.
+func (b ColumnBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *ColumnBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
 type TableBlock struct {
 	BaseBlock
 
@@ -669,6 +1332,22 @@ func (b TableBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b TableBlock) BlockType() BlockType {
+	return BlockTypeTable
+}
+
+// GetChildren implements Parenter.
+func (b TableBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *TableBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
 type TableRowBlock struct {
 	BaseBlock
 
@@ -689,6 +1368,11 @@ func (b TableRowBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b TableRowBlock) BlockType() BlockType {
+	return BlockTypeTableRow
+}
+
 type LinkPreviewBlock struct {
 	BaseBlock
 
@@ -709,6 +1393,11 @@ func (b LinkPreviewBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b LinkPreviewBlock) BlockType() BlockType {
+	return BlockTypeLinkPreview
+}
+
 type LinkToPageBlock struct {
 	BaseBlock
 
@@ -731,6 +1420,11 @@ func (b LinkToPageBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b LinkToPageBlock) BlockType() BlockType {
+	return BlockTypeLinkToPage
+}
+
 type LinkToPageType string
 
 const (
@@ -759,6 +1453,22 @@ func (b SyncedBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b SyncedBlock) BlockType() BlockType {
+	return BlockTypeSyncedBlock
+}
+
+// GetChildren implements Parenter.
+func (b SyncedBlock) GetChildren() []Block {
+	return b.Children
+}
+
+// SetChildren implements Parenter.
+func (b *SyncedBlock) SetChildren(children []Block) error {
+	b.Children = children
+	return nil
+}
+
 type SyncedFrom struct {
 	Type    SyncedFromType `json:"type"`
 	BlockID string         `json:"block_id"`
@@ -786,6 +1496,11 @@ func (b DividerBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b DividerBlock) BlockType() BlockType {
+	return BlockTypeDivider
+}
+
 type TableOfContentsBlock struct {
 	BaseBlock
 
@@ -806,6 +1521,21 @@ func (b TableOfContentsBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b TableOfContentsBlock) BlockType() BlockType {
+	return BlockTypeTableOfContents
+}
+
+// GetColor implements Colorer.
+func (b TableOfContentsBlock) GetColor() Color {
+	return b.Color
+}
+
+// SetColor implements Colorer.
+func (b *TableOfContentsBlock) SetColor(color Color) {
+	b.Color = color
+}
+
 type BreadcrumbBlock struct {
 	BaseBlock
 }
@@ -824,6 +1554,11 @@ func (b BreadcrumbBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b BreadcrumbBlock) BlockType() BlockType {
+	return BlockTypeBreadCrumb
+}
+
 type UnsupportedBlock struct {
 	BaseBlock
 }
@@ -842,6 +1577,34 @@ func (b UnsupportedBlock) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// BlockType implements TypedBlock.
+func (b UnsupportedBlock) BlockType() BlockType {
+	return BlockTypeUnsupported
+}
+
+// RawBlock is a block with a type that is unknown to this library, e.g. when
+// Notion introduces a new block type. The original JSON object is retained and
+// returned as is by MarshalJSON.
+type RawBlock struct {
+	BaseBlock
+
+	Type BlockType       `json:"-"`
+	Raw  json.RawMessage `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b RawBlock) MarshalJSON() ([]byte, error) {
+	if b.Raw == nil {
+		return []byte("null"), nil
+	}
+	return b.Raw, nil
+}
+
+// BlockType implements TypedBlock.
+func (b RawBlock) BlockType() BlockType {
+	return b.Type
+}
+
 type BlockType string
 
 const (
@@ -892,11 +1655,13 @@ type BlockChildrenResponse struct {
 	NextCursor *string
 }
 
+// UnmarshalJSON implements json.Unmarshaler. Blocks of a type unknown to this
+// library are decoded as *RawBlock.
 func (resp *BlockChildrenResponse) UnmarshalJSON(b []byte) error {
 	type responseDTO struct {
-		Results    []blockDTO `json:"results"`
-		HasMore    bool       `json:"has_more"`
-		NextCursor *string    `json:"next_cursor"`
+		Results    []json.RawMessage `json:"results"`
+		HasMore    bool              `json:"has_more"`
+		NextCursor *string           `json:"next_cursor"`
 	}
 
 	var dto responseDTO
@@ -909,14 +1674,10 @@ func (resp *BlockChildrenResponse) UnmarshalJSON(b []byte) error {
 	resp.NextCursor = dto.NextCursor
 	resp.Results = make([]Block, len(dto.Results))
 
-	for i, blockDTO := range dto.Results {
-		block, err := blockDTO.Block()
+	for i, raw := range dto.Results {
+		block, err := parseBlock(raw)
 		if err != nil {
-			// Any error (even `ErrUnknownBlockType`) is explicitly returned.
-			// We don't silently drop blocks with an unknown/unmapped type,
-			// because this could lead to surprises/unexpected list behaviour
-			// for users.
-			return fmt.Errorf("notion: failed to parse block (id: %q, type: %q): %w", blockDTO.ID, blockDTO.Type, err)
+			return err
 		}
 		resp.Results[i] = block
 	}
@@ -924,36 +1685,114 @@ func (resp *BlockChildrenResponse) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func (dto blockDTO) Block() (Block, error) {
-	baseBlock := BaseBlock{
-		IdProperty:          dto.ID,
-		HasChildrenProperty: dto.HasChildren,
+// ParseBlock decodes a block object, as returned by the API or encoded with
+// MarshalBlock. Blocks with an unknown type are returned as *RawBlock.
+func ParseBlock(raw []byte) (Block, error) {
+	return parseBlock(raw)
+}
+
+// MarshalBlock encodes a block as block object, including its ID, parent,
+// timestamps and other metadata. Unlike the MarshalJSON method of blocks, which
+// only encodes content for use in requests, the result can be decoded with
+// ParseBlock without loss.
+func MarshalBlock(block Block) ([]byte, error) {
+	if rb, ok := block.(*RawBlock); ok {
+		return rb.MarshalJSON()
 	}
 
-	if dto.Parent != nil {
-		baseBlock.ParentProperty = *dto.Parent
+	content, err := block.MarshalJSON()
+	if err != nil {
+		return nil, err
 	}
 
-	if dto.CreatedTime != nil {
-		baseBlock.createdTime = *dto.CreatedTime
+	dto := blockDTO{
+		ID:          block.ID(),
+		Type:        BlockTypeOf(block),
+		HasChildren: block.HasChildren(),
+	}
+	if parent := block.Parent(); parent != (Parent{}) {
+		dto.Parent = &parent
+	}
+	if t := block.CreatedTime(); !t.IsZero() {
+		dto.CreatedTime = &t
+	}
+	if user := block.CreatedBy(); user != (BaseUser{}) {
+		dto.CreatedBy = &user
+	}
+	if t := block.LastEditedTime(); !t.IsZero() {
+		dto.LastEditedTime = &t
+	}
+	if user := block.LastEditedBy(); user != (BaseUser{}) {
+		dto.LastEditedBy = &user
+	}
+	if block.Archived() {
+		dto.Archived = BoolPtr(true)
 	}
 
-	if dto.CreatedBy != nil {
-		baseBlock.createdBy = *dto.CreatedBy
+	meta, err := json.Marshal(dto)
+	if err != nil {
+		return nil, err
 	}
 
-	if dto.LastEditedTime != nil {
-		baseBlock.lastEditedTime = *dto.LastEditedTime
+	// The content is a JSON object with the block type as its only key.
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(content, &obj); err != nil {
+		return nil, err
 	}
+	if err := json.Unmarshal(meta, &obj); err != nil {
+		return nil, err
+	}
+	obj["object"] = json.RawMessage(`"block"`)
 
-	if dto.LastEditedBy != nil {
-		baseBlock.lastEditedBy = *dto.LastEditedBy
+	return json.Marshal(obj)
+}
+
+// parseBlock decodes a block object. Blocks with an unknown type are returned
+// as *RawBlock, retaining the original JSON.
+func parseBlock(raw []byte) (Block, error) {
+	var dto blockDTO
+
+	if err := json.Unmarshal(raw, &dto); err != nil {
+		return nil, err
 	}
 
-	if dto.Archived != nil {
-		baseBlock.archived = *dto.Archived
+	block, err := dto.Block()
+	if errors.Is(err, ErrUnknownBlockType) {
+		return &RawBlock{
+			BaseBlock: dto.baseBlock(),
+			Type:      dto.Type,
+			Raw:       append(json.RawMessage(nil), raw...),
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("notion: failed to parse block (id: %q, type: %q): %w", dto.ID, dto.Type, err)
+	}
+
+	return block, nil
+}
+
+// unknownBlockError returns an error for the first *RawBlock found in blocks
+// and their children, or nil if there is none.
+func unknownBlockError(blocks []Block) error {
+	var rawBlock *RawBlock
+
+	Inspect(blocks, func(b Block, _ WalkInfo) bool {
+		if rb, ok := b.(*RawBlock); ok && rawBlock == nil {
+			rawBlock = rb
+		}
+		return rawBlock == nil
+	})
+
+	if rawBlock == nil {
+		return nil
 	}
 
+	return fmt.Errorf("notion: failed to parse block (id: %q, type: %q): %w", rawBlock.ID(), rawBlock.Type, ErrUnknownBlockType)
+}
+
+func (dto blockDTO) Block() (Block, error) {
+	baseBlock := dto.baseBlock()
+
 	switch dto.Type {
 	case BlockTypeParagraph:
 		dto.Paragraph.BaseBlock = baseBlock
@@ -1060,3 +1899,37 @@ func (dto blockDTO) Block() (Block, error) {
 		return nil, ErrUnknownBlockType
 	}
 }
+
+// baseBlock returns the block metadata shared by all block types.
+func (dto blockDTO) baseBlock() BaseBlock {
+	baseBlock := BaseBlock{
+		IdProperty:          dto.ID,
+		HasChildrenProperty: dto.HasChildren,
+	}
+
+	if dto.Parent != nil {
+		baseBlock.ParentProperty = *dto.Parent
+	}
+
+	if dto.CreatedTime != nil {
+		baseBlock.createdTime = *dto.CreatedTime
+	}
+
+	if dto.CreatedBy != nil {
+		baseBlock.createdBy = *dto.CreatedBy
+	}
+
+	if dto.LastEditedTime != nil {
+		baseBlock.lastEditedTime = *dto.LastEditedTime
+	}
+
+	if dto.LastEditedBy != nil {
+		baseBlock.lastEditedBy = *dto.LastEditedBy
+	}
+
+	if dto.Archived != nil {
+		baseBlock.archived = *dto.Archived
+	}
+
+	return baseBlock
+}
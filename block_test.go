@@ -0,0 +1,411 @@
+package notion_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/skedida/go-notion"
+)
+
+func TestBlockCapabilities(t *testing.T) {
+	t.Parallel()
+
+	var resp notion.BlockChildrenResponse
+
+	err := json.Unmarshal([]byte(`{
+		"results": [
+			{
+				"object": "block",
+				"id": "ae9c9a31-1c1e-4ae2-a5ee-c539a2d43113",
+				"type": "heading_2",
+				"heading_2": {
+					"rich_text": [{"type": "text", "text": {"content": "Foo"}, "plain_text": "Foo"}],
+					"color": "red"
+				}
+			},
+			{
+				"object": "block",
+				"id": "5e9c9a31-1c1e-4ae2-a5ee-c539a2d43113",
+				"type": "image",
+				"image": {
+					"type": "external",
+					"external": {"url": "https://example.com/image.png"}
+				}
+			}
+		]
+	}`), &resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	heading, image := resp.Results[0], resp.Results[1]
+
+	if exp, got := notion.BlockTypeHeading2, notion.BlockTypeOf(heading); exp != got {
+		t.Fatalf("block type not equal (expected: %v, got: %v)", exp, got)
+	}
+	if exp, got := notion.BlockTypeImage, notion.BlockTypeOf(image); exp != got {
+		t.Fatalf("block type not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	rt, ok := heading.(notion.RichTexter)
+	if !ok {
+		t.Fatal("expected heading to implement RichTexter")
+	}
+	rt.SetRichText(richText("Bar"))
+
+	colorer, ok := heading.(notion.Colorer)
+	if !ok {
+		t.Fatal("expected heading to implement Colorer")
+	}
+	if exp, got := notion.ColorRed, colorer.GetColor(); exp != got {
+		t.Fatalf("color not equal (expected: %v, got: %v)", exp, got)
+	}
+	colorer.SetColor(notion.ColorBlue)
+
+	got := heading.(*notion.Heading2Block)
+	if diff := cmp.Diff(richText("Bar"), got.RichText); diff != "" {
+		t.Fatalf("rich text not equal (-exp, +got):\n%v", diff)
+	}
+	if exp := notion.ColorBlue; exp != got.Color {
+		t.Fatalf("color not equal (expected: %v, got: %v)", exp, got.Color)
+	}
+
+	if _, ok := image.(notion.RichTexter); ok {
+		t.Fatal("expected image not to implement RichTexter")
+	}
+	captioner, ok := image.(notion.Captioner)
+	if !ok {
+		t.Fatal("expected image to implement Captioner")
+	}
+	captioner.SetCaption(richText("Caption"))
+	if diff := cmp.Diff(richText("Caption"), notion.BlockRichText(image)); diff != "" {
+		t.Fatalf("rich text not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+// customBlock is a block type defined outside of the notion package, which
+// doesn't implement notion.TypedBlock.
+type customBlock struct {
+	notion.BaseBlock
+}
+
+func (b customBlock) MarshalJSON() ([]byte, error) {
+	return []byte(`{"custom": {}}`), nil
+}
+
+func TestBlockTypeOf(t *testing.T) {
+	t.Parallel()
+
+	if exp, got := notion.BlockTypeDivider, notion.BlockTypeOf(&notion.DividerBlock{}); exp != got {
+		t.Fatalf("block type not equal (expected: %v, got: %v)", exp, got)
+	}
+	if exp, got := notion.BlockType("custom"), notion.BlockTypeOf(customBlock{}); exp != got {
+		t.Fatalf("block type not equal (expected: %v, got: %v)", exp, got)
+	}
+}
+
+func TestRawBlock(t *testing.T) {
+	t.Parallel()
+
+	rawBlock := `{"object":"block","id":"ae9c9a31-1c1e-4ae2-a5ee-c539a2d43113","type":"foobar","has_children":true,"foobar":{"baz":  [1, 2, 3]}}`
+
+	var resp notion.BlockChildrenResponse
+
+	err := json.Unmarshal([]byte(`{"results": [`+rawBlock+`]}`), &resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	block, ok := resp.Results[0].(*notion.RawBlock)
+	if !ok {
+		t.Fatalf("expected *notion.RawBlock, got %T", resp.Results[0])
+	}
+	if exp, got := "ae9c9a31-1c1e-4ae2-a5ee-c539a2d43113", block.ID(); exp != got {
+		t.Fatalf("id not equal (expected: %v, got: %v)", exp, got)
+	}
+	if exp, got := notion.BlockType("foobar"), block.BlockType(); exp != got {
+		t.Fatalf("block type not equal (expected: %v, got: %v)", exp, got)
+	}
+	if !block.HasChildren() {
+		t.Fatal("expected block to have children")
+	}
+
+	got, err := block.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(rawBlock, string(got)); diff != "" {
+		t.Fatalf("encoded JSON not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestMarshalBlock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		json string
+	}{
+		{
+			name: "paragraph",
+			json: `{"archived":false,"created_by":{"id":"71e95936-2737-4e11-b03d-f174f6f13087"},"created_time":"2021-05-14T09:15:00Z","has_children":true,"id":"ae9c9a31-1c1e-4ae2-a5ee-c539a2d43113","last_edited_by":{"id":"71e95936-2737-4e11-b03d-f174f6f13087"},"last_edited_time":"2021-05-14T09:15:00Z","object":"block","paragraph":{"rich_text":[{"type":"text","annotations":{"color":"default"},"plain_text":"Lorem ipsum","text":{"content":"Lorem ipsum"}}]},"parent":{"type":"page_id","page_id":"59833787-2cf9-4fdf-8782-e53db20768a5"},"type":"paragraph"}`,
+		},
+		{
+			name: "unknown block type",
+			json: `{"object":"block","id":"ae9c9a31-1c1e-4ae2-a5ee-c539a2d43113","type":"foobar","foobar":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			block, err := notion.ParseBlock([]byte(tt.json))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := notion.MarshalBlock(block)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Archived is only encoded when true.
+			exp := strings.Replace(tt.json, `"archived":false,`, "", 1)
+			if diff := cmp.Diff(exp, string(got)); diff != "" {
+				t.Fatalf("encoded JSON not equal (-exp, +got):\n%v", diff)
+			}
+
+			reparsed, err := notion.ParseBlock(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(block, reparsed, cmpopts.IgnoreUnexported(notion.BaseBlock{})); diff != "" {
+				t.Fatalf("parsed block not equal (-exp, +got):\n%v", diff)
+			}
+			if exp, got := block.LastEditedTime(), reparsed.LastEditedTime(); !exp.Equal(got) {
+				t.Fatalf("last edited time not equal (expected: %v, got: %v)", exp, got)
+			}
+		})
+	}
+}
+
+func TestUnknownPageProperty(t *testing.T) {
+	t.Parallel()
+
+	rawProp := `{"id":"a%3Ab","type":"foobar","foobar":{"prefix":"ID","number":42}}`
+
+	var page notion.Page
+
+	err := json.Unmarshal([]byte(`{
+		"object": "page",
+		"id": "8e6ef39e-0f48-4b3a-9c34-dd3a7a9b8b71",
+		"parent": {"type": "database_id", "database_id": "39ddfc9d-33c9-404c-89cf-79f01c42dd0c"},
+		"properties": {
+			"Name": {"id": "title", "type": "title", "title": []},
+			"Foobar": `+rawProp+`
+		}
+	}`), &page)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	props := page.Properties.(notion.DatabasePageProperties)
+
+	if props["Name"].Raw != nil {
+		t.Fatal("expected known property not to have raw JSON")
+	}
+
+	got, err := json.Marshal(props["Foobar"])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(rawProp, string(got)); diff != "" {
+		t.Fatalf("encoded JSON not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestValidateBlocks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		blocks    []notion.Block
+		expErrors []string
+	}{
+		{
+			name: "valid",
+			blocks: []notion.Block{
+				notion.ParagraphBlock{RichText: []notion.RichText{{Text: &notion.Text{Content: "Foobar"}}}},
+				&notion.CodeBlock{Language: notion.StringPtr("go")},
+				&notion.CodeBlock{Language: notion.StringPtr("toml")},
+				notion.TableBlock{
+					TableWidth: 2,
+					Children: []notion.Block{
+						notion.TableRowBlock{Cells: [][]notion.RichText{{}, {}}},
+					},
+				},
+				notion.LinkToPageBlock{Type: notion.LinkToPageTypePageID, PageID: "cb261dc5-6c85-4767-8585-3852382fb466"},
+				notion.FileBlock{Type: notion.FileTypeFileUpload, FileUpload: &notion.FileUploadFile{ID: "43833259-72ae-404e-8441-b6577f3159b4"}},
+			},
+		},
+		{
+			name: "invalid",
+			blocks: []notion.Block{
+				notion.ImageBlock{Type: notion.FileTypeExternal},
+				notion.ToggleBlock{
+					Children: []notion.Block{
+						notion.ParagraphBlock{RichText: []notion.RichText{{Text: &notion.Text{Content: strings.Repeat("a", 2001)}}}},
+						&notion.CodeBlock{Language: notion.StringPtr("golang")},
+					},
+				},
+				notion.TableBlock{
+					TableWidth: 2,
+					Children: []notion.Block{
+						notion.TableRowBlock{Cells: [][]notion.RichText{{}, {}}},
+						notion.TableRowBlock{Cells: [][]notion.RichText{{}}},
+					},
+				},
+				notion.LinkToPageBlock{Type: notion.LinkToPageTypeDatabaseID, PageID: "cb261dc5-6c85-4767-8585-3852382fb466"},
+				notion.PDFBlock{Type: notion.FileTypeFileUpload},
+			},
+			expErrors: []string{
+				"block [0] (image): external URL cannot be empty when type is external",
+				"block [1 0] (paragraph): rich text 0 has 2001 characters, maximum is 2000",
+				`block [1 1] (code): unknown code language "golang"`,
+				"block [2 1] (table_row): table row has 1 cells, expected table width 2",
+				"block [3] (link_to_page): database ID cannot be empty when type is database_id",
+				"block [4] (pdf): file upload ID cannot be empty when type is file_upload",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := notion.ValidateBlocks(tt.blocks)
+			if len(tt.expErrors) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var multiErr notion.MultiError
+			if !errors.As(err, &multiErr) {
+				t.Fatalf("expected notion.MultiError, got: %#v", err)
+			}
+
+			var msgs []string
+			for _, err := range multiErr {
+				msgs = append(msgs, err.Error())
+			}
+			if diff := cmp.Diff(tt.expErrors, msgs); diff != "" {
+				t.Fatalf("errors not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestBlockValidation(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(requests *int32, body string, opts ...notion.ClientOption) *notion.Client {
+		httpClient := &http.Client{
+			Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+				atomic.AddInt32(requests, 1)
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     http.StatusText(http.StatusOK),
+					Body:       ioutil.NopCloser(strings.NewReader(body)),
+				}, nil
+			}},
+		}
+
+		opts = append([]notion.ClientOption{notion.WithHTTPClient(httpClient)}, opts...)
+		return notion.NewClient("secret-api-key", opts...)
+	}
+
+	pageJSON := `{"object": "page", "id": "cb261dc5-6c85-4767-8585-3852382fb466", "parent": {"type": "page_id", "page_id": "b0668f48-8d66-4733-9bdb-2f82215707f7"}, "properties": {}}`
+	childrenJSON := `{"object": "list", "results": [], "has_more": false}`
+
+	valid := []notion.Block{
+		&notion.CodeBlock{
+			RichText: []notion.RichText{{Text: &notion.Text{Content: "key = 1"}}},
+			Language: notion.StringPtr("toml"),
+		},
+	}
+	invalid := []notion.Block{
+		notion.ImageBlock{Type: notion.FileTypeExternal},
+	}
+
+	createPage := func(client *notion.Client, children []notion.Block) error {
+		_, err := client.CreatePage(context.Background(), notion.CreatePageParams{
+			ParentType: notion.ParentTypePage,
+			ParentID:   "b0668f48-8d66-4733-9bdb-2f82215707f7",
+			Title:      []notion.RichText{{Text: &notion.Text{Content: "Foobar"}}},
+			Children:   children,
+		})
+		return err
+	}
+	appendBlockChildren := func(client *notion.Client, children []notion.Block) error {
+		_, err := client.AppendBlockChildren(context.Background(), "b0668f48-8d66-4733-9bdb-2f82215707f7", children)
+		return err
+	}
+
+	tests := []struct {
+		name string
+		body string
+		call func(client *notion.Client, children []notion.Block) error
+	}{
+		{name: "create page", body: pageJSON, call: createPage},
+		{name: "append block children", body: childrenJSON, call: appendBlockChildren},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests int32
+			client := newClient(&requests, tt.body, notion.WithBlockValidation())
+
+			if err := tt.call(client, valid); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err := tt.call(client, invalid)
+			var blockErr *notion.BlockError
+			if !errors.As(err, &blockErr) {
+				t.Fatalf("expected *notion.BlockError, got: %v", err)
+			}
+
+			// Invalid blocks aren't sent.
+			if requests != 1 {
+				t.Fatalf("requests not equal (expected: 1, got: %v)", requests)
+			}
+
+			// Without the option, blocks are sent as is.
+			requests = 0
+			client = newClient(&requests, tt.body)
+			if err := tt.call(client, invalid); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if requests != 1 {
+				t.Fatalf("requests not equal (expected: 1, got: %v)", requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Limits of rich text imposed by the Notion API.
+// See: https://developers.notion.com/reference/request-limits
+const (
+	MaxRichTextLength   = 2000
+	MaxRichTextElements = 100
+)
+
+// codeLanguages are the languages supported by code blocks.
+var codeLanguages = map[string]bool{
+	"abap": true, "agda": true, "arduino": true, "ascii art": true, "assembly": true,
+	"bash": true, "basic": true, "bnf": true, "c": true, "c#": true, "c++": true,
+	"clojure": true, "coffeescript": true, "coq": true, "css": true, "dart": true,
+	"dhall": true, "diff": true, "docker": true, "ebnf": true, "elixir": true,
+	"elm": true, "erlang": true, "f#": true, "flow": true, "fortran": true,
+	"gherkin": true, "glsl": true, "go": true, "graphql": true, "groovy": true,
+	"haskell": true, "hcl": true, "html": true, "idris": true, "java": true,
+	"javascript": true, "json": true, "julia": true, "kotlin": true, "latex": true,
+	"less": true, "lisp": true, "livescript": true, "llvm ir": true, "lua": true,
+	"makefile": true, "markdown": true, "markup": true, "matlab": true,
+	"mathematica": true, "mermaid": true, "nix": true, "notion formula": true,
+	"objective-c": true, "ocaml": true, "pascal": true, "perl": true, "php": true,
+	"plain text": true, "powershell": true, "prolog": true, "protobuf": true,
+	"purescript": true, "python": true, "r": true, "racket": true, "reason": true,
+	"ruby": true, "rust": true, "sass": true, "scala": true, "scheme": true,
+	"scss": true, "shell": true, "smalltalk": true, "solidity": true, "sql": true,
+	"swift": true, "toml": true, "typescript": true, "vb.net": true, "verilog": true,
+	"vhdl": true, "visual basic": true, "webassembly": true, "xml": true,
+	"yaml": true, "java/c/c++/c#": true,
+}
+
+// BlockError is an error for a block, as returned by ValidateBlocks.
+type BlockError struct {
+	// Path holds the index of the block and of each of its ancestors, see
+	// WalkInfo.Path.
+	Path []int
+	Type BlockType
+	Err  error
+}
+
+// Error implements `error`.
+func (err *BlockError) Error() string {
+	return fmt.Sprintf("block %v (%v): %v", err.Path, err.Type, err.Err)
+}
+
+func (err *BlockError) Unwrap() error {
+	return err.Err
+}
+
+// ValidateBlocks validates blocks and their (nested) children, using the
+// Validate method of each block, and checks that table rows have a cell for
+// each column of their table. All invalid blocks are returned as MultiError of
+// *BlockError values.
+func ValidateBlocks(blocks []Block) error {
+	var errs MultiError
+
+	Inspect(blocks, func(b Block, info WalkInfo) bool {
+		b = blockValue(b)
+		if b == nil {
+			return false
+		}
+
+		fail := func(err error) {
+			errs = append(errs, &BlockError{Path: info.Path, Type: BlockTypeOf(b), Err: err})
+		}
+
+		if v, ok := b.(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				fail(err)
+			}
+		}
+
+		if table, ok := blockValue(info.Parent).(TableBlock); ok {
+			row, ok := b.(TableRowBlock)
+			if !ok {
+				fail(errors.New("table children must be table rows"))
+			} else if len(row.Cells) != table.TableWidth {
+				fail(fmt.Errorf("table row has %v cells, expected table width %v", len(row.Cells), table.TableWidth))
+			}
+		}
+
+		return true
+	})
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func validateRichText(richText []RichText) error {
+	if len(richText) > MaxRichTextElements {
+		return fmt.Errorf("rich text has %v elements, maximum is %v", len(richText), MaxRichTextElements)
+	}
+
+	for i, rt := range richText {
+		if rt.Text != nil {
+			if n := utf8.RuneCountInString(rt.Text.Content); n > MaxRichTextLength {
+				return fmt.Errorf("rich text %v has %v characters, maximum is %v", i, n, MaxRichTextLength)
+			}
+			if rt.Text.Link != nil && rt.Text.Link.URL == "" {
+				return fmt.Errorf("rich text %v link URL cannot be empty", i)
+			}
+		}
+		if rt.Equation != nil && rt.Equation.Expression == "" {
+			return fmt.Errorf("rich text %v equation expression cannot be empty", i)
+		}
+	}
+
+	return nil
+}
+
+func validateRichTextAndCaption(richText, caption []RichText) error {
+	if err := validateRichText(richText); err != nil {
+		return err
+	}
+	if err := validateRichText(caption); err != nil {
+		return fmt.Errorf("caption: %w", err)
+	}
+
+	return nil
+}
+
+func validateFile(fileType FileType, file *FileFile, external *FileExternal, fileUpload *FileUploadFile, caption []RichText) error {
+	switch fileType {
+	case FileTypeFile:
+		if file == nil {
+			return errors.New("file cannot be empty when type is file")
+		}
+	case FileTypeExternal:
+		if external == nil || external.URL == "" {
+			return errors.New("external URL cannot be empty when type is external")
+		}
+	case FileTypeFileUpload:
+		if fileUpload == nil || fileUpload.ID == "" {
+			return errors.New("file upload ID cannot be empty when type is file_upload")
+		}
+	case "":
+		return errors.New("file type cannot be empty")
+	default:
+		return fmt.Errorf("unknown file type %q", fileType)
+	}
+
+	if err := validateRichText(caption); err != nil {
+		return fmt.Errorf("caption: %w", err)
+	}
+
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b ParagraphBlock) Validate() error {
+	return validateRichText(b.RichText)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b BulletedListItemBlock) Validate() error {
+	return validateRichText(b.RichText)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b NumberedListItemBlock) Validate() error {
+	return validateRichText(b.RichText)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b QuoteBlock) Validate() error {
+	return validateRichText(b.RichText)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b ToggleBlock) Validate() error {
+	return validateRichText(b.RichText)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b TemplateBlock) Validate() error {
+	return validateRichText(b.RichText)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b Heading1Block) Validate() error {
+	return validateRichText(b.RichText)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b Heading2Block) Validate() error {
+	return validateRichText(b.RichText)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b Heading3Block) Validate() error {
+	return validateRichText(b.RichText)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b ToDoBlock) Validate() error {
+	return validateRichText(b.RichText)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b ChildPageBlock) Validate() error {
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b ChildDatabaseBlock) Validate() error {
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b CalloutBlock) Validate() error {
+	if b.Icon != nil {
+		if err := b.Icon.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return validateRichText(b.RichText)
+}
+
+// Validate checks that the block is valid for sending to the Notion API. The
+// language, if set, must be supported by Notion, e.g. "go" or "plain text".
+func (b CodeBlock) Validate() error {
+	if b.Language != nil && !codeLanguages[*b.Language] {
+		return fmt.Errorf("unknown code language %q", *b.Language)
+	}
+
+	return validateRichTextAndCaption(b.RichText, b.Caption)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b EmbedBlock) Validate() error {
+	if b.URL == "" {
+		return errors.New("embed URL cannot be empty")
+	}
+
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b ImageBlock) Validate() error {
+	return validateFile(b.Type, b.File, b.External, b.FileUpload, b.Caption)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b AudioBlock) Validate() error {
+	return validateFile(b.Type, b.File, b.External, b.FileUpload, b.Caption)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b VideoBlock) Validate() error {
+	return validateFile(b.Type, b.File, b.External, b.FileUpload, b.Caption)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b FileBlock) Validate() error {
+	return validateFile(b.Type, b.File, b.External, b.FileUpload, b.Caption)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b PDFBlock) Validate() error {
+	return validateFile(b.Type, b.File, b.External, b.FileUpload, b.Caption)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b BookmarkBlock) Validate() error {
+	if b.URL == "" {
+		return errors.New("bookmark URL cannot be empty")
+	}
+
+	return validateRichText(b.Caption)
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b EquationBlock) Validate() error {
+	if b.Expression == "" {
+		return errors.New("equation expression cannot be empty")
+	}
+
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b ColumnListBlock) Validate() error {
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b ColumnBlock) Validate() error {
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API. The
+// width of its rows is checked by ValidateBlocks.
+func (b TableBlock) Validate() error {
+	if b.TableWidth < 1 {
+		return errors.New("table width must be at least 1")
+	}
+
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API. The
+// number of cells is checked against the table width by ValidateBlocks.
+func (b TableRowBlock) Validate() error {
+	for i, cell := range b.Cells {
+		if err := validateRichText(cell); err != nil {
+			return fmt.Errorf("cell %v: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b LinkPreviewBlock) Validate() error {
+	if b.URL == "" {
+		return errors.New("link preview URL cannot be empty")
+	}
+
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b LinkToPageBlock) Validate() error {
+	switch b.Type {
+	case LinkToPageTypePageID:
+		if b.PageID == "" {
+			return errors.New("page ID cannot be empty when type is page_id")
+		}
+	case LinkToPageTypeDatabaseID:
+		if b.DatabaseID == "" {
+			return errors.New("database ID cannot be empty when type is database_id")
+		}
+	case "":
+		return errors.New("link to page type cannot be empty")
+	default:
+		return fmt.Errorf("unknown link to page type %q", b.Type)
+	}
+
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API. An
+// original synced block has nil SyncedFrom; a duplicate references the original
+// block and cannot have children.
+func (b SyncedBlock) Validate() error {
+	if b.SyncedFrom == nil {
+		return nil
+	}
+	if b.SyncedFrom.BlockID == "" {
+		return errors.New("synced from block ID cannot be empty")
+	}
+	if len(b.Children) > 0 {
+		return errors.New("duplicate synced block cannot have children")
+	}
+
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b DividerBlock) Validate() error {
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b TableOfContentsBlock) Validate() error {
+	return nil
+}
+
+// Validate checks that the block is valid for sending to the Notion API.
+func (b BreadcrumbBlock) Validate() error {
+	return nil
+}
+
+// Validate always returns an error, as unsupported blocks can't be created.
+func (b UnsupportedBlock) Validate() error {
+	return errors.New("unsupported blocks cannot be created")
+}
+
+// Validate always returns nil, as blocks of unknown types are sent as is.
+func (b RawBlock) Validate() error {
+	return nil
+}
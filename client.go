@@ -1,15 +1,19 @@
 package notion
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -20,8 +24,12 @@ const (
 
 // Client is used for HTTP requests to the Notion API.
 type Client struct {
-	apiKey     string
-	httpClient *http.Client
+	apiKey         string
+	httpClient     *http.Client
+	strictDecoding bool
+	validateBlocks bool
+	schemaCache    *schemaCache
+	tokenSource    TokenSource
 }
 
 // ClientOption is used to override default client behavior.
@@ -48,13 +56,87 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithStrictDecoding makes the client return an error when a response contains
+// a block or page property of a type that is unknown to this library. By
+// default, these are decoded as *RawBlock and DatabasePageProperty values with
+// a Raw field, respectively.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strictDecoding = true
+	}
+}
+
+// WithBlockValidation makes the client validate blocks with ValidateBlocks
+// before sending them with CreatePage and AppendBlockChildren. Invalid blocks
+// are then returned as an error, without making a request. Note that Notion
+// may support values (e.g. code languages) that this library doesn't know of
+// yet.
+func WithBlockValidation() ClientOption {
+	return func(c *Client) {
+		c.validateBlocks = true
+	}
+}
+
+// checkBlocks returns an error for blocks of an unknown type, if strict
+// decoding is enabled.
+func (c *Client) checkBlocks(blocks ...Block) error {
+	if !c.strictDecoding {
+		return nil
+	}
+	if err := unknownBlockError(blocks); err != nil {
+		return fmt.Errorf("notion: failed to parse HTTP response: %w", err)
+	}
+	return nil
+}
+
+// checkSearchResults returns an error for results of an unknown object type,
+// or for pages with properties of an unknown type, if strict decoding is
+// enabled.
+func (c *Client) checkSearchResults(results SearchResults) error {
+	if !c.strictDecoding {
+		return nil
+	}
+	for _, result := range results {
+		if result.Raw != nil {
+			return fmt.Errorf("notion: failed to parse HTTP response: unsupported result object %q", result.Object())
+		}
+	}
+	return c.checkPages(results.Pages()...)
+}
+
+// checkPages returns an error for page properties of an unknown type, if strict
+// decoding is enabled.
+func (c *Client) checkPages(pages ...Page) error {
+	if !c.strictDecoding {
+		return nil
+	}
+	for _, page := range pages {
+		props, ok := page.Properties.(DatabasePageProperties)
+		if !ok {
+			continue
+		}
+		if err := props.unknownPropertyError(); err != nil {
+			return fmt.Errorf("notion: failed to parse HTTP response: %w", err)
+		}
+	}
+	return nil
+}
+
 func (c *Client) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, method, baseURL+url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", c.apiKey))
+	token := c.apiKey
+	if c.tokenSource != nil {
+		token, err = c.tokenSource.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token: %w", err)
+		}
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
 	req.Header.Set("Notion-Version", apiVersion)
 	req.Header.Set("User-Agent", "go-notion/"+clientVersion)
 
@@ -123,9 +205,29 @@ func (c *Client) QueryDatabase(ctx context.Context, id string, query *DatabaseQu
 		return DatabaseQueryResponse{}, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
 	}
 
+	if err := c.checkPages(result.Results...); err != nil {
+		return DatabaseQueryResponse{}, err
+	}
+
 	return result, nil
 }
 
+// QueryDatabaseIterator returns an iterator over the pages of a database
+// query, which follows pagination cursors as needed. Results are fetched lazily,
+// one result page at a time, so large databases don't need to fit in memory.
+func (c *Client) QueryDatabaseIterator(id string, query *DatabaseQuery) *DatabaseQueryIterator {
+	it := &DatabaseQueryIterator{
+		client:     c,
+		databaseID: id,
+	}
+
+	if query != nil {
+		it.query = *query
+	}
+
+	return it
+}
+
 // CreateDatabase creates a new database as a child of an existing page.
 // See: https://developers.notion.com/reference/create-a-database
 func (c *Client) CreateDatabase(ctx context.Context, params CreateDatabaseParams) (db Database, err error) {
@@ -197,6 +299,8 @@ func (c *Client) UpdateDatabase(ctx context.Context, databaseID string, params U
 		return Database{}, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
 	}
 
+	c.InvalidateSchema(databaseID)
+
 	return updatedDB, nil
 }
 
@@ -223,6 +327,10 @@ func (c *Client) FindPageByID(ctx context.Context, id string) (page Page, err er
 		return Page{}, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
 	}
 
+	if err := c.checkPages(page); err != nil {
+		return Page{}, err
+	}
+
 	return page, nil
 }
 
@@ -232,6 +340,14 @@ func (c *Client) CreatePage(ctx context.Context, params CreatePageParams) (page
 	if err := params.Validate(); err != nil {
 		return Page{}, fmt.Errorf("notion: invalid page params: %w", err)
 	}
+	if c.validateBlocks {
+		if err := ValidateBlocks(params.Children); err != nil {
+			return Page{}, fmt.Errorf("notion: invalid page children: %w", err)
+		}
+	}
+	if err := c.validateCreatePage(ctx, params); err != nil {
+		return Page{}, err
+	}
 
 	body := &bytes.Buffer{}
 
@@ -260,6 +376,10 @@ func (c *Client) CreatePage(ctx context.Context, params CreatePageParams) (page
 		return Page{}, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
 	}
 
+	if err := c.checkPages(page); err != nil {
+		return Page{}, err
+	}
+
 	return page, nil
 }
 
@@ -269,6 +389,9 @@ func (c *Client) UpdatePage(ctx context.Context, pageID string, params UpdatePag
 	if err := params.Validate(); err != nil {
 		return Page{}, fmt.Errorf("notion: invalid page params: %w", err)
 	}
+	if err := c.validateUpdatePage(ctx, pageID, params); err != nil {
+		return Page{}, err
+	}
 
 	body := &bytes.Buffer{}
 
@@ -297,6 +420,10 @@ func (c *Client) UpdatePage(ctx context.Context, pageID string, params UpdatePag
 		return Page{}, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
 	}
 
+	if err := c.checkPages(page); err != nil {
+		return Page{}, err
+	}
+
 	return page, nil
 }
 
@@ -334,6 +461,10 @@ func (c *Client) FindBlockChildrenByID(ctx context.Context, blockID string, quer
 		return BlockChildrenResponse{}, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
 	}
 
+	if err := c.checkBlocks(result.Results...); err != nil {
+		return BlockChildrenResponse{}, err
+	}
+
 	return result, nil
 }
 
@@ -377,6 +508,12 @@ func (c *Client) FindPagePropertyByID(ctx context.Context, pageID, propID string
 // AppendBlockChildren appends child content (blocks) to an existing block.
 // See: https://developers.notion.com/reference/patch-block-children
 func (c *Client) AppendBlockChildren(ctx context.Context, blockID string, children []Block) (result BlockChildrenResponse, err error) {
+	if c.validateBlocks {
+		if err := ValidateBlocks(children); err != nil {
+			return BlockChildrenResponse{}, fmt.Errorf("notion: invalid block children: %w", err)
+		}
+	}
+
 	type PostBody struct {
 		Children []Block `json:"children"`
 	}
@@ -409,6 +546,10 @@ func (c *Client) AppendBlockChildren(ctx context.Context, blockID string, childr
 		return BlockChildrenResponse{}, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
 	}
 
+	if err := c.checkBlocks(result.Results...); err != nil {
+		return BlockChildrenResponse{}, err
+	}
+
 	return result, nil
 }
 
@@ -430,14 +571,23 @@ func (c *Client) FindBlockByID(ctx context.Context, blockID string) (Block, erro
 		return nil, fmt.Errorf("notion: failed to find block: %w", parseErrorResponse(res))
 	}
 
-	var dto blockDTO
+	var raw json.RawMessage
 
-	err = json.NewDecoder(res.Body).Decode(&dto)
+	err = json.NewDecoder(res.Body).Decode(&raw)
 	if err != nil {
 		return nil, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
 	}
 
-	return dto.Block()
+	block, err := parseBlock(raw)
+	if err != nil {
+		return nil, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
+	}
+
+	if err := c.checkBlocks(block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
 }
 
 // UpdateBlock updates a block.
@@ -465,14 +615,23 @@ func (c *Client) UpdateBlock(ctx context.Context, blockID string, block Block) (
 		return nil, fmt.Errorf("notion: failed to update block: %w", parseErrorResponse(res))
 	}
 
-	var dto blockDTO
+	var raw json.RawMessage
+
+	err = json.NewDecoder(res.Body).Decode(&raw)
+	if err != nil {
+		return nil, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
+	}
 
-	err = json.NewDecoder(res.Body).Decode(&dto)
+	updatedBlock, err := parseBlock(raw)
 	if err != nil {
 		return nil, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
 	}
 
-	return dto.Block()
+	if err := c.checkBlocks(updatedBlock); err != nil {
+		return nil, err
+	}
+
+	return updatedBlock, nil
 }
 
 // DeleteBlock sets `archived: true` on a (page) block object.
@@ -494,14 +653,23 @@ func (c *Client) DeleteBlock(ctx context.Context, blockID string) (Block, error)
 		return nil, fmt.Errorf("notion: failed to delete block: %w", parseErrorResponse(res))
 	}
 
-	var dto blockDTO
+	var raw json.RawMessage
 
-	err = json.NewDecoder(res.Body).Decode(&dto)
+	err = json.NewDecoder(res.Body).Decode(&raw)
 	if err != nil {
 		return nil, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
 	}
 
-	return dto.Block()
+	block, err := parseBlock(raw)
+	if err != nil {
+		return nil, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
+	}
+
+	if err := c.checkBlocks(block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
 }
 
 // FindUserByID fetches a user by ID.
@@ -626,9 +794,62 @@ func (c *Client) Search(ctx context.Context, opts *SearchOpts) (result SearchRes
 		return SearchResponse{}, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
 	}
 
+	if err := c.checkSearchResults(result.Results); err != nil {
+		return SearchResponse{}, err
+	}
+
 	return result, nil
 }
 
+// SearchAll searches like Search, but follows pagination cursors until all
+// results are fetched. Results are de-duplicated by ID, and optionally filtered
+// by title prefix.
+func (c *Client) SearchAll(ctx context.Context, opts *SearchAllOpts) (results SearchResults, err error) {
+	if opts == nil {
+		opts = &SearchAllOpts{}
+	}
+
+	searchOpts := &SearchOpts{
+		Query:    opts.Query,
+		Sort:     opts.Sort,
+		Filter:   opts.Filter,
+		PageSize: opts.PageSize,
+	}
+	prefix := strings.ToLower(opts.TitlePrefix)
+	seen := make(map[string]bool)
+
+	for {
+		resp, err := c.Search(ctx, searchOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range resp.Results {
+			id := result.ID()
+			if id != "" && seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			if prefix != "" && !strings.HasPrefix(strings.ToLower(result.Title()), prefix) {
+				continue
+			}
+
+			results = append(results, result)
+
+			if opts.MaxResults > 0 && len(results) == opts.MaxResults {
+				return results, nil
+			}
+		}
+
+		if !resp.HasMore || resp.NextCursor == nil {
+			return results, nil
+		}
+
+		searchOpts.StartCursor = *resp.NextCursor
+	}
+}
+
 // CreateComment creates a comment in a page or existing discussion thread.
 // See: https://developers.notion.com/reference/create-a-comment
 func (c *Client) CreateComment(ctx context.Context, params CreateCommentParams) (comment Comment, err error) {
@@ -709,3 +930,242 @@ func (c *Client) FindCommentsByBlockID(
 
 	return result, nil
 }
+
+// CreateFileUpload creates a file upload, of which the content is sent with
+// SendFileUpload, or imported from an external URL.
+// See: https://developers.notion.com/reference/create-a-file-upload
+func (c *Client) CreateFileUpload(ctx context.Context, params CreateFileUploadParams) (upload FileUpload, err error) {
+	if err := params.Validate(); err != nil {
+		return FileUpload{}, fmt.Errorf("notion: invalid file upload params: %w", err)
+	}
+
+	body := &bytes.Buffer{}
+
+	err = json.NewEncoder(body).Encode(params)
+	if err != nil {
+		return FileUpload{}, fmt.Errorf("notion: failed to encode body params to JSON: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/file_uploads", body)
+	if err != nil {
+		return FileUpload{}, fmt.Errorf("notion: invalid request: %w", err)
+	}
+
+	return c.doFileUpload(req, "create file upload")
+}
+
+// SendFileUpload sends the content of a file upload, or one part of a
+// multi-part upload. The content is streamed as a multipart/form-data request
+// body, so it isn't buffered in memory.
+// See: https://developers.notion.com/reference/send-a-file-upload
+func (c *Client) SendFileUpload(ctx context.Context, fileUploadID string, params SendFileUploadParams) (upload FileUpload, err error) {
+	if err := params.Validate(); err != nil {
+		return FileUpload{}, fmt.Errorf("notion: invalid file upload params: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		pw.CloseWithError(writeFileUploadForm(mw, params))
+	}()
+
+	req, err := c.newRequest(ctx, http.MethodPost, fmt.Sprintf("/file_uploads/%v/send", fileUploadID), pr)
+	if err != nil {
+		pr.Close()
+		return FileUpload{}, fmt.Errorf("notion: invalid request: %w", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	upload, err = c.doFileUpload(req, "send file upload")
+	// Unblocks the writer, if the request failed before the body was read.
+	pr.Close()
+
+	return upload, err
+}
+
+func writeFileUploadForm(mw *multipart.Writer, params SendFileUploadParams) error {
+	if params.PartNumber > 0 {
+		if err := mw.WriteField("part_number", strconv.Itoa(params.PartNumber)); err != nil {
+			return err
+		}
+	}
+
+	filename := params.Filename
+	if filename == "" {
+		filename = "file"
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filename))
+	if params.ContentType != "" {
+		header.Set("Content-Type", params.ContentType)
+	} else {
+		header.Set("Content-Type", "application/octet-stream")
+	}
+
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, params.Content); err != nil {
+		return err
+	}
+
+	return mw.Close()
+}
+
+// CompleteFileUpload completes a multi-part upload, after all its parts were
+// sent.
+// See: https://developers.notion.com/reference/complete-a-file-upload
+func (c *Client) CompleteFileUpload(ctx context.Context, fileUploadID string) (upload FileUpload, err error) {
+	req, err := c.newRequest(ctx, http.MethodPost, fmt.Sprintf("/file_uploads/%v/complete", fileUploadID), nil)
+	if err != nil {
+		return FileUpload{}, fmt.Errorf("notion: invalid request: %w", err)
+	}
+
+	return c.doFileUpload(req, "complete file upload")
+}
+
+// FindFileUploadByID fetches a file upload by ID.
+// See: https://developers.notion.com/reference/retrieve-a-file-upload
+func (c *Client) FindFileUploadByID(ctx context.Context, fileUploadID string) (upload FileUpload, err error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/file_uploads/"+fileUploadID, nil)
+	if err != nil {
+		return FileUpload{}, fmt.Errorf("notion: invalid request: %w", err)
+	}
+
+	return c.doFileUpload(req, "find file upload")
+}
+
+// FindFileUploads lists the file uploads of the integration, optionally
+// filtered by status.
+// See: https://developers.notion.com/reference/list-file-uploads
+func (c *Client) FindFileUploads(ctx context.Context, query *FindFileUploadsQuery) (result FindFileUploadsResponse, err error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/file_uploads", nil)
+	if err != nil {
+		return FindFileUploadsResponse{}, fmt.Errorf("notion: invalid request: %w", err)
+	}
+
+	if query != nil {
+		q := url.Values{}
+		if query.Status != "" {
+			q.Set("status", string(query.Status))
+		}
+		if query.StartCursor != "" {
+			q.Set("start_cursor", query.StartCursor)
+		}
+		if query.PageSize != 0 {
+			q.Set("page_size", strconv.Itoa(query.PageSize))
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return FindFileUploadsResponse{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return FindFileUploadsResponse{}, fmt.Errorf("notion: failed to list file uploads: %w", parseErrorResponse(res))
+	}
+
+	err = json.NewDecoder(res.Body).Decode(&result)
+	if err != nil {
+		return FindFileUploadsResponse{}, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
+	}
+
+	return result, nil
+}
+
+// UploadFile uploads a file: it creates a file upload, sends the content in one
+// part, or in parts of UploadPartSize for files larger than
+// MaxSinglePartUploadSize, and completes multi-part uploads. The returned file
+// upload can be attached using a FileUploadFile with its ID.
+func (c *Client) UploadFile(ctx context.Context, params UploadFileParams) (FileUpload, error) {
+	if err := params.Validate(); err != nil {
+		return FileUpload{}, fmt.Errorf("notion: invalid upload file params: %w", err)
+	}
+
+	if params.Size <= MaxSinglePartUploadSize {
+		upload, err := c.CreateFileUpload(ctx, CreateFileUploadParams{
+			Filename:    params.Filename,
+			ContentType: params.ContentType,
+		})
+		if err != nil {
+			return FileUpload{}, err
+		}
+
+		return c.SendFileUpload(ctx, upload.ID, SendFileUploadParams{
+			Content:     params.Content,
+			Filename:    params.Filename,
+			ContentType: uploadContentType(params.ContentType, upload),
+		})
+	}
+
+	parts := int((params.Size + UploadPartSize - 1) / UploadPartSize)
+
+	upload, err := c.CreateFileUpload(ctx, CreateFileUploadParams{
+		Mode:          FileUploadModeMultiPart,
+		Filename:      params.Filename,
+		ContentType:   params.ContentType,
+		NumberOfParts: parts,
+	})
+	if err != nil {
+		return FileUpload{}, err
+	}
+
+	content := bufio.NewReader(params.Content)
+
+	for part := 1; part <= parts; part++ {
+		// Parts without content are rejected by the API, with an error that
+		// doesn't mention the cause.
+		if _, err := content.Peek(1); err == io.EOF {
+			return FileUpload{}, fmt.Errorf("notion: failed to upload file: content is shorter than size (part %v of %v is empty)", part, parts)
+		} else if err != nil {
+			return FileUpload{}, fmt.Errorf("notion: failed to read file content: %w", err)
+		}
+
+		_, err := c.SendFileUpload(ctx, upload.ID, SendFileUploadParams{
+			Content:     io.LimitReader(content, UploadPartSize),
+			Filename:    params.Filename,
+			ContentType: uploadContentType(params.ContentType, upload),
+			PartNumber:  part,
+		})
+		if err != nil {
+			return FileUpload{}, err
+		}
+	}
+
+	return c.CompleteFileUpload(ctx, upload.ID)
+}
+
+// uploadContentType returns the content type to send the content of a file
+// upload with: the given content type, or else the content type of the upload,
+// e.g. as inferred by Notion from its filename.
+func uploadContentType(contentType string, upload FileUpload) string {
+	if contentType == "" && upload.ContentType != nil {
+		return *upload.ContentType
+	}
+	return contentType
+}
+
+func (c *Client) doFileUpload(req *http.Request, action string) (upload FileUpload, err error) {
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return FileUpload{}, fmt.Errorf("notion: failed to make HTTP request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return FileUpload{}, fmt.Errorf("notion: failed to %v: %w", action, parseErrorResponse(res))
+	}
+
+	err = json.NewDecoder(res.Body).Decode(&upload)
+	if err != nil {
+		return FileUpload{}, fmt.Errorf("notion: failed to parse HTTP response: %w", err)
+	}
+
+	return upload, nil
+}
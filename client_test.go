@@ -979,6 +979,99 @@ func TestQueryDatabase(t *testing.T) {
 	}
 }
 
+func TestQueryDatabaseIterator(t *testing.T) {
+	t.Parallel()
+
+	pageJSON := func(id string) string {
+		return fmt.Sprintf(`{
+			"object": "page",
+			"id": %q,
+			"parent": {"type": "database_id", "database_id": "39ddfc9d-33c9-404c-89cf-79f01c42dd0c"},
+			"properties": {}
+		}`, id)
+	}
+	responses := []string{
+		`{"object": "list", "results": [` + pageJSON("1") + `,` + pageJSON("2") + `], "has_more": true, "next_cursor": "A"}`,
+		`{"object": "list", "results": [], "has_more": true, "next_cursor": "B"}`,
+		`{"object": "list", "results": [` + pageJSON("3") + `], "has_more": false, "next_cursor": null}`,
+	}
+
+	t.Run("iterates over all pages", func(t *testing.T) {
+		t.Parallel()
+
+		var cursors []string
+
+		httpClient := &http.Client{
+			Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+				var query notion.DatabaseQuery
+				if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+					t.Fatal(err)
+				}
+				if query.PageSize != 2 {
+					t.Fatalf("page size not equal (expected: 2, got: %v)", query.PageSize)
+				}
+				cursors = append(cursors, query.StartCursor)
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     http.StatusText(http.StatusOK),
+					Body:       ioutil.NopCloser(strings.NewReader(responses[len(cursors)-1])),
+				}, nil
+			}},
+		}
+		client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+
+		it := client.QueryDatabaseIterator("00000000-0000-0000-0000-000000000000", &notion.DatabaseQuery{PageSize: 2})
+
+		var ids []string
+		for it.Next(context.Background()) {
+			ids = append(ids, it.Page().ID)
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if diff := cmp.Diff([]string{"1", "2", "3"}, ids); diff != "" {
+			t.Fatalf("page IDs not equal (-exp, +got):\n%v", diff)
+		}
+		if diff := cmp.Diff([]string{"", "A", "B"}, cursors); diff != "" {
+			t.Fatalf("cursors not equal (-exp, +got):\n%v", diff)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		httpClient := &http.Client{
+			Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Status:     http.StatusText(http.StatusBadRequest),
+					Body: ioutil.NopCloser(strings.NewReader(
+						`{
+							"object": "error",
+							"status": 400,
+							"code": "validation_error",
+							"message": "foobar"
+						}`,
+					)),
+				}, nil
+			}},
+		}
+		client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+
+		it := client.QueryDatabaseIterator("00000000-0000-0000-0000-000000000000", nil)
+		if it.Next(context.Background()) {
+			t.Fatal("expected no pages")
+		}
+		if it.Next(context.Background()) {
+			t.Fatal("expected no pages")
+		}
+		if it.Err() == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
 func TestCreateDatabase(t *testing.T) {
 	t.Parallel()
 
@@ -3028,6 +3121,7 @@ func TestFindBlockChildrenById(t *testing.T) {
 
 	tests := []struct {
 		name           string
+		clientOpts     []notion.ClientOption
 		query          *notion.PaginationQuery
 		respBody       func(r *http.Request) io.Reader
 		respStatusCode int
@@ -3158,7 +3252,8 @@ func TestFindBlockChildrenById(t *testing.T) {
 			expError: nil,
 		},
 		{
-			name: "unknown block type",
+			name:       "unknown block type, strict decoding",
+			clientOpts: []notion.ClientOption{notion.WithStrictDecoding()},
 			respBody: func(_ *http.Request) io.Reader {
 				return strings.NewReader(
 					`{
@@ -3229,7 +3324,8 @@ func TestFindBlockChildrenById(t *testing.T) {
 					}, nil
 				}},
 			}
-			client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+			opts := append([]notion.ClientOption{notion.WithHTTPClient(httpClient)}, tt.clientOpts...)
+			client := notion.NewClient("secret-api-key", opts...)
 			resp, err := client.FindBlockChildrenByID(context.Background(), "00000000-0000-0000-0000-000000000000", tt.query)
 
 			if tt.expError == nil && err != nil {
@@ -3242,7 +3338,7 @@ func TestFindBlockChildrenById(t *testing.T) {
 				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
 			}
 
-			if diff := cmp.Diff(tt.expResponse, resp, cmpopts.IgnoreUnexported(notion.ParagraphBlock{}, notion.UnsupportedBlock{})); diff != "" {
+			if diff := cmp.Diff(tt.expResponse, resp, cmpopts.IgnoreTypes(notion.BaseBlock{})); diff != "" {
 				t.Fatalf("response not equal (-exp, +got):\n%v", diff)
 			}
 
@@ -3488,7 +3584,7 @@ func TestAppendBlockChildren(t *testing.T) {
 				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
 			}
 
-			if diff := cmp.Diff(tt.expResponse, resp, cmpopts.IgnoreUnexported(notion.ParagraphBlock{})); diff != "" {
+			if diff := cmp.Diff(tt.expResponse, resp, cmpopts.IgnoreTypes(notion.BaseBlock{})); diff != "" {
 				t.Fatalf("response not equal (-exp, +got):\n%v", diff)
 			}
 
@@ -4018,7 +4114,7 @@ func TestSearch(t *testing.T) {
 			},
 			expResponse: notion.SearchResponse{
 				Results: notion.SearchResults{
-					notion.Database{
+					notion.NewDatabaseSearchResult(notion.Database{
 						ID:             "668d797c-76fa-4934-9b05-ad288df2d136",
 						CreatedTime:    mustParseTime(time.RFC3339, "2020-03-17T19:10:04.968Z"),
 						LastEditedTime: mustParseTime(time.RFC3339, "2020-03-17T21:49:37.913Z"),
@@ -4042,8 +4138,8 @@ func TestSearch(t *testing.T) {
 								Title: &notion.EmptyMetadata{},
 							},
 						},
-					},
-					notion.Page{
+					}),
+					notion.NewPageSearchResult(notion.Page{
 						ID:             "276ee233-e426-4ed0-9986-6b22af8550df",
 						CreatedTime:    mustParseTime(time.RFC3339Nano, "2021-05-19T19:34:05.068Z"),
 						LastEditedTime: mustParseTime(time.RFC3339Nano, "2021-05-19T19:34:05.069Z"),
@@ -4067,7 +4163,7 @@ func TestSearch(t *testing.T) {
 								},
 							},
 						},
-					},
+					}),
 				},
 				HasMore:    true,
 				NextCursor: notion.StringPtr("A^hd"),
@@ -4184,7 +4280,7 @@ func TestSearch(t *testing.T) {
 				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
 			}
 
-			if diff := cmp.Diff(tt.expResponse, resp); diff != "" {
+			if diff := cmp.Diff(tt.expResponse, resp, cmp.AllowUnexported(notion.SearchResult{})); diff != "" {
 				t.Fatalf("response not equal (-exp, +got):\n%v", diff)
 			}
 		})
@@ -4307,7 +4403,7 @@ func TestFindBlockByID(t *testing.T) {
 				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
 			}
 
-			if diff := cmp.Diff(tt.expBlock, block, cmpopts.IgnoreUnexported(notion.ChildPageBlock{})); diff != "" {
+			if diff := cmp.Diff(tt.expBlock, block, cmpopts.IgnoreTypes(notion.BaseBlock{})); diff != "" {
 				t.Fatalf("user not equal (-exp, +got):\n%v", diff)
 			}
 
@@ -4529,7 +4625,7 @@ func TestUpdateBlock(t *testing.T) {
 				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
 			}
 
-			if diff := cmp.Diff(tt.expResponse, updatedBlock, cmpopts.IgnoreUnexported(notion.ParagraphBlock{})); diff != "" {
+			if diff := cmp.Diff(tt.expResponse, updatedBlock, cmpopts.IgnoreTypes(notion.BaseBlock{})); diff != "" {
 				t.Fatalf("response not equal (-exp, +got):\n%v", diff)
 			}
 
@@ -4676,7 +4772,7 @@ func TestDeleteBlock(t *testing.T) {
 				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
 			}
 
-			if diff := cmp.Diff(tt.expResponse, deletedBlock, cmpopts.IgnoreUnexported(notion.ParagraphBlock{})); diff != "" {
+			if diff := cmp.Diff(tt.expResponse, deletedBlock, cmpopts.IgnoreTypes(notion.BaseBlock{})); diff != "" {
 				t.Fatalf("response not equal (-exp, +got):\n%v", diff)
 			}
 
@@ -5125,3 +5221,90 @@ func TestFindCommentsByBlockID(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchAll(t *testing.T) {
+	t.Parallel()
+
+	pageJSON := func(id, title string) string {
+		return fmt.Sprintf(`{
+			"object": "page",
+			"id": %q,
+			"parent": {"type": "workspace", "workspace": true},
+			"properties": {
+				"title": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": %q}, "plain_text": %q}]}
+			}
+		}`, id, title, title)
+	}
+	responses := []string{
+		`{"object": "list", "results": [` + pageJSON("1", "Foo") + `,` + pageJSON("2", "Bar") + `], "has_more": true, "next_cursor": "A"}`,
+		`{"object": "list", "results": [` + pageJSON("2", "Bar") + `,` + pageJSON("3", "foo bar") + `,{"object": "foobar", "id": "4"}], "has_more": false, "next_cursor": null}`,
+	}
+
+	tests := []struct {
+		name   string
+		opts   *notion.SearchAllOpts
+		expIDs []string
+	}{
+		{
+			name:   "all results, de-duplicated",
+			opts:   nil,
+			expIDs: []string{"1", "2", "3", ""},
+		},
+		{
+			name:   "title prefix",
+			opts:   &notion.SearchAllOpts{TitlePrefix: "FOO"},
+			expIDs: []string{"1", "3"},
+		},
+		{
+			name:   "max results",
+			opts:   &notion.SearchAllOpts{MaxResults: 1},
+			expIDs: []string{"1"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cursors []string
+
+			httpClient := &http.Client{
+				Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+					var opts notion.SearchOpts
+					if err := json.NewDecoder(r.Body).Decode(&opts); err != nil && err != io.EOF {
+						t.Fatal(err)
+					}
+					cursors = append(cursors, opts.StartCursor)
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Status:     http.StatusText(http.StatusOK),
+						Body:       ioutil.NopCloser(strings.NewReader(responses[len(cursors)-1])),
+					}, nil
+				}},
+			}
+			client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+
+			results, err := client.SearchAll(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var ids, pageIDs []string
+			for _, result := range results {
+				ids = append(ids, result.ID())
+			}
+			for _, page := range results.Pages() {
+				pageIDs = append(pageIDs, page.ID)
+			}
+
+			if diff := cmp.Diff(tt.expIDs, ids); diff != "" {
+				t.Fatalf("result IDs not equal (-exp, +got):\n%v", diff)
+			}
+			if diff := cmp.Diff(strings.Fields(strings.Join(tt.expIDs, " ")), pageIDs, cmpopts.EquateEmpty()); diff != "" {
+				t.Fatalf("page IDs not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
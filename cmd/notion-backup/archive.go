@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+// archiveVersion is the version of the on-disk layout of archives. It is
+// incremented on incompatible changes.
+const archiveVersion = 1
+
+// An archive is a directory with the following layout:
+//
+//	manifest.json          Version, time of the last backup, and an entry per page and database.
+//	users.json             Users of the workspace.
+//	databases/<id>.json    Database, including its schema, and downloaded files.
+//	pages/<id>.json        Page, its block tree, comments and downloaded files.
+//	files/<id>/<name>      Files of a page and its blocks, or of a database.
+//
+// JSON files are indented, and objects have keys in a stable order, so
+// archives can be diffed and kept in version control.
+type archive struct {
+	dir string
+}
+
+type manifest struct {
+	Version int `json:"version"`
+	// BackupTime is the time the last backup started.
+	BackupTime time.Time        `json:"backup_time"`
+	Pages      map[string]entry `json:"pages"`
+	Databases  map[string]entry `json:"databases"`
+}
+
+// entry describes a page or database in the manifest, so archives can be
+// listed and restored in order without reading every file.
+type entry struct {
+	Title          string        `json:"title"`
+	Parent         notion.Parent `json:"parent"`
+	CreatedTime    time.Time     `json:"created_time"`
+	LastEditedTime time.Time     `json:"last_edited_time"`
+}
+
+type pageRecord struct {
+	Page     notion.Page      `json:"page"`
+	Blocks   []node           `json:"blocks"`
+	Comments []notion.Comment `json:"comments,omitempty"`
+	Files    []archiveFile    `json:"files,omitempty"`
+}
+
+type databaseRecord struct {
+	Database notion.Database `json:"database"`
+	Files    []archiveFile   `json:"files,omitempty"`
+}
+
+// node is a block with its children. Children are stored in the node rather
+// than the block, as blocks returned by the API don't have them.
+type node struct {
+	Block    notion.Block
+	Children []node
+}
+
+type nodeDTO struct {
+	Block    json.RawMessage `json:"block"`
+	Children []node          `json:"children,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (n node) MarshalJSON() ([]byte, error) {
+	block, err := notion.MarshalBlock(n.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(nodeDTO{Block: block, Children: n.Children})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *node) UnmarshalJSON(b []byte) error {
+	var dto nodeDTO
+
+	if err := json.Unmarshal(b, &dto); err != nil {
+		return err
+	}
+
+	block, err := notion.ParseBlock(dto.Block)
+	if err != nil {
+		return err
+	}
+
+	n.Block = block
+	n.Children = dto.Children
+
+	return nil
+}
+
+// walkNodes calls fn for nodes and their descendants, depth-first.
+func walkNodes(nodes []node, fn func(n node)) {
+	for _, n := range nodes {
+		fn(n)
+		walkNodes(n.Children, fn)
+	}
+}
+
+// archiveFile is a downloaded file of a page (icon, cover or files property),
+// of a block, or of a database (icon or cover).
+type archiveFile struct {
+	BlockID string `json:"block_id,omitempty"`
+	// Property is the name of the files property, or "icon" or "cover".
+	Property string `json:"property,omitempty"`
+	Index    int    `json:"index"`
+	Name     string `json:"name"`
+	// Path is relative to the archive directory, with forward slashes.
+	Path string `json:"path"`
+}
+
+func (a archive) readManifest() (manifest, error) {
+	m := manifest{
+		Version:   archiveVersion,
+		Pages:     make(map[string]entry),
+		Databases: make(map[string]entry),
+	}
+
+	err := a.readJSON("manifest.json", &m)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return manifest{}, err
+	}
+
+	if m.Version > archiveVersion {
+		return manifest{}, fmt.Errorf("unsupported archive version %v (max: %v)", m.Version, archiveVersion)
+	}
+
+	return m, nil
+}
+
+func (a archive) writeManifest(m manifest) error {
+	m.Version = archiveVersion
+	return a.writeJSON("manifest.json", m)
+}
+
+func (a archive) readPage(id string) (rec pageRecord, err error) {
+	err = a.readJSON(path.Join("pages", id+".json"), &rec)
+	return rec, err
+}
+
+func (a archive) writePage(rec pageRecord) error {
+	return a.writeJSON(path.Join("pages", rec.Page.ID+".json"), rec)
+}
+
+func (a archive) hasPage(id string) bool {
+	_, err := os.Stat(a.path(path.Join("pages", id+".json")))
+	return err == nil
+}
+
+func (a archive) removePage(id string) error {
+	if err := a.removeFiles(id); err != nil {
+		return err
+	}
+	return removeIfExists(a.path(path.Join("pages", id+".json")))
+}
+
+// removeFiles removes the downloaded files of a page or database.
+func (a archive) removeFiles(id string) error {
+	return os.RemoveAll(a.path(path.Join("files", id)))
+}
+
+func (a archive) readDatabase(id string) (rec databaseRecord, err error) {
+	err = a.readJSON(path.Join("databases", id+".json"), &rec)
+	return rec, err
+}
+
+func (a archive) writeDatabase(rec databaseRecord) error {
+	return a.writeJSON(path.Join("databases", rec.Database.ID+".json"), rec)
+}
+
+func (a archive) removeDatabase(id string) error {
+	if err := a.removeFiles(id); err != nil {
+		return err
+	}
+	return removeIfExists(a.path(path.Join("databases", id+".json")))
+}
+
+func (a archive) writeUsers(users []notion.User) error {
+	return a.writeJSON("users.json", users)
+}
+
+// path returns the file path of a slash separated path in the archive.
+func (a archive) path(name string) string {
+	return filepath.Join(a.dir, filepath.FromSlash(name))
+}
+
+func (a archive) readJSON(name string, v interface{}) error {
+	b, err := os.ReadFile(a.path(name))
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to decode %v: %w", name, err)
+	}
+
+	return nil
+}
+
+// writeJSON writes v as indented JSON, via a temporary file so an interrupted
+// backup doesn't leave a corrupt file.
+func (a archive) writeJSON(name string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %v: %w", name, err)
+	}
+	b = append(b, '\n')
+
+	p := a.path(name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
+
+func removeIfExists(name string) error {
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
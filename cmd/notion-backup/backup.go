@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/download"
+)
+
+type backupOptions struct {
+	// Full crawls all pages, instead of only pages edited since the previous
+	// backup.
+	Full bool
+	// Comments enables backing up the comments of pages and blocks, which
+	// takes a request per block.
+	Comments bool
+	// Files enables downloading Notion hosted files.
+	Files bool
+}
+
+type backup struct {
+	client     notion.API
+	archive    archive
+	downloader *download.Downloader
+	opts       backupOptions
+	log        *log.Logger
+}
+
+// run backs up all pages and databases the integration can access. Pages are
+// only crawled again if their last edited time changed since the previous
+// backup (Notion updates it when blocks of the page are edited). Comments
+// don't change the last edited time, so the comments of unchanged pages are
+// fetched again. Files of databases are only downloaded again if their last
+// edited time changed. Pages and databases that are no longer accessible are
+// removed from the archive.
+//
+// Failures to back up a page don't stop the backup; the page is crawled again
+// by the next backup, and the errors are returned as notion.MultiError.
+func (b *backup) run(ctx context.Context) error {
+	m, err := b.archive.readManifest()
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	start := time.Now()
+
+	results, err := b.client.SearchAll(ctx, &notion.SearchAllOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to search: %w", err)
+	}
+
+	var (
+		errs      notion.MultiError
+		pages     = make(map[string]entry)
+		databases = make(map[string]entry)
+		skipped   int
+	)
+
+	for _, db := range results.Databases() {
+		e := entry{
+			Title:          notion.PlainText(db.Title),
+			Parent:         db.Parent,
+			CreatedTime:    db.CreatedTime,
+			LastEditedTime: db.LastEditedTime,
+		}
+
+		rec := databaseRecord{Database: db}
+		prev, ok := m.Databases[db.ID]
+		unchanged := !b.opts.Full && ok && prev.LastEditedTime.Equal(db.LastEditedTime)
+
+		prevRec, prevErr := b.archive.readDatabase(db.ID)
+		switch {
+		case unchanged && prevErr == nil:
+			rec.Files = prevRec.Files
+		case b.opts.Files:
+			var err error
+			if rec.Files, err = b.files(ctx, db.ID, download.DatabaseAttachments(db)); err != nil {
+				errs = append(errs, fmt.Errorf("failed to back up files of database %v: %w", db.ID, err))
+				// Download the files again with the next backup.
+				e.LastEditedTime = time.Time{}
+			}
+		}
+
+		if err := b.archive.writeDatabase(rec); err != nil {
+			return fmt.Errorf("failed to write database: %w", err)
+		}
+		databases[db.ID] = e
+	}
+
+	for _, page := range results.Pages() {
+		e := entry{
+			Title:          page.Title(),
+			Parent:         page.Parent,
+			CreatedTime:    page.CreatedTime,
+			LastEditedTime: page.LastEditedTime,
+		}
+
+		prev, ok := m.Pages[page.ID]
+		if !b.opts.Full && ok && prev.LastEditedTime.Equal(page.LastEditedTime) && b.archive.hasPage(page.ID) {
+			if b.opts.Comments {
+				if err := b.refreshComments(ctx, page.ID); err != nil {
+					errs = append(errs, fmt.Errorf("failed to back up comments of page %v: %w", page.ID, err))
+				}
+			}
+			pages[page.ID] = e
+			skipped++
+			continue
+		}
+
+		b.log.Printf("Backing up page %q (%v) ...", e.Title, page.ID)
+
+		if err := b.page(ctx, page); err != nil {
+			errs = append(errs, fmt.Errorf("failed to back up page %v: %w", page.ID, err))
+			// Keep the previous last edited time, so the next backup crawls the
+			// page again.
+			if !ok {
+				prev = e
+				prev.LastEditedTime = time.Time{}
+			}
+			pages[page.ID] = prev
+			continue
+		}
+		pages[page.ID] = e
+	}
+
+	for id := range m.Pages {
+		if _, ok := pages[id]; !ok {
+			if err := b.archive.removePage(id); err != nil {
+				return fmt.Errorf("failed to remove page: %w", err)
+			}
+		}
+	}
+	for id := range m.Databases {
+		if _, ok := databases[id]; !ok {
+			if err := b.archive.removeDatabase(id); err != nil {
+				return fmt.Errorf("failed to remove database: %w", err)
+			}
+		}
+	}
+
+	users, err := b.users(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.archive.writeUsers(users); err != nil {
+		return fmt.Errorf("failed to write users: %w", err)
+	}
+
+	m.BackupTime = start
+	m.Pages = pages
+	m.Databases = databases
+	if err := b.archive.writeManifest(m); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	b.log.Printf("Backed up %v pages (%v unchanged) and %v databases.", len(pages), skipped, len(databases))
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (b *backup) page(ctx context.Context, page notion.Page) error {
+	blocks, err := b.blockTree(ctx, page.ID)
+	if err != nil {
+		return err
+	}
+
+	rec := pageRecord{Page: page, Blocks: blocks}
+
+	if b.opts.Comments {
+		if rec.Comments, err = b.pageComments(ctx, page.ID, blocks); err != nil {
+			return err
+		}
+	}
+
+	// Files that failed to download are left out, and downloaded again by the
+	// next backup, as the page is then considered changed.
+	var filesErr error
+	if b.opts.Files {
+		var flat []notion.Block
+		walkNodes(blocks, func(n node) {
+			flat = append(flat, n.Block)
+		})
+		attachments := append(download.PageAttachments(page), download.BlockAttachments(flat)...)
+		rec.Files, filesErr = b.files(ctx, page.ID, attachments)
+	}
+
+	if err := b.archive.writePage(rec); err != nil {
+		return err
+	}
+
+	return filesErr
+}
+
+// blockTree returns the child blocks of a block or page, recursively. Child
+// pages and databases are backed up separately, so their content is skipped.
+func (b *backup) blockTree(ctx context.Context, blockID string) ([]node, error) {
+	var (
+		nodes []node
+		query = &notion.PaginationQuery{PageSize: 100}
+	)
+
+	for {
+		resp, err := b.client.FindBlockChildrenByID(ctx, blockID, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find block children: %w", err)
+		}
+
+		for _, block := range resp.Results {
+			n := node{Block: block}
+
+			switch notion.BlockTypeOf(block) {
+			case notion.BlockTypeChildPage, notion.BlockTypeChildDatabase:
+			default:
+				if block.HasChildren() {
+					if n.Children, err = b.blockTree(ctx, block.ID()); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			nodes = append(nodes, n)
+		}
+
+		if !resp.HasMore || resp.NextCursor == nil {
+			return nodes, nil
+		}
+		query.StartCursor = *resp.NextCursor
+	}
+}
+
+// refreshComments replaces the comments of an archived page with its current
+// comments.
+func (b *backup) refreshComments(ctx context.Context, pageID string) error {
+	rec, err := b.archive.readPage(pageID)
+	if err != nil {
+		return err
+	}
+
+	if rec.Comments, err = b.pageComments(ctx, pageID, rec.Blocks); err != nil {
+		return err
+	}
+
+	return b.archive.writePage(rec)
+}
+
+// pageComments returns the comments of a page and its blocks.
+func (b *backup) pageComments(ctx context.Context, pageID string, blocks []node) ([]notion.Comment, error) {
+	ids := []string{pageID}
+	walkNodes(blocks, func(n node) {
+		ids = append(ids, n.Block.ID())
+	})
+
+	var comments []notion.Comment
+	for _, id := range ids {
+		c, err := b.comments(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, c...)
+	}
+
+	return comments, nil
+}
+
+func (b *backup) comments(ctx context.Context, blockID string) ([]notion.Comment, error) {
+	var (
+		comments []notion.Comment
+		query    = notion.FindCommentsByBlockIDQuery{BlockID: blockID, PageSize: 100}
+	)
+
+	for {
+		resp, err := b.client.FindCommentsByBlockID(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find comments: %w", err)
+		}
+		comments = append(comments, resp.Results...)
+
+		if !resp.HasMore || resp.NextCursor == nil {
+			return comments, nil
+		}
+		query.StartCursor = *resp.NextCursor
+	}
+}
+
+func (b *backup) users(ctx context.Context) ([]notion.User, error) {
+	var (
+		users []notion.User
+		query = &notion.PaginationQuery{PageSize: 100}
+	)
+
+	for {
+		resp, err := b.client.ListUsers(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list users: %w", err)
+		}
+		users = append(users, resp.Results...)
+
+		if !resp.HasMore || resp.NextCursor == nil {
+			return users, nil
+		}
+		query.StartCursor = *resp.NextCursor
+	}
+}
+
+// files downloads the Notion hosted files of a page (and its blocks) or
+// database, replacing the files of its previous backup. The downloaded files
+// are returned, also when some downloads failed.
+func (b *backup) files(ctx context.Context, ownerID string, attachments []download.Attachment) ([]archiveFile, error) {
+	dir := path.Join("files", ownerID)
+	if err := b.archive.removeFiles(ownerID); err != nil {
+		return nil, fmt.Errorf("failed to remove files: %w", err)
+	}
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	results, err := b.downloader.DownloadAll(ctx, attachments, b.archive.path(dir))
+
+	var files []archiveFile
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		files = append(files, archiveFile{
+			BlockID:  r.Attachment.BlockID,
+			Property: r.Attachment.Property,
+			Index:    r.Attachment.Index,
+			Name:     r.Attachment.Name,
+			Path:     path.Join(dir, filepath.Base(r.Path)),
+		})
+	}
+
+	return files, err
+}
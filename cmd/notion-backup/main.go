@@ -0,0 +1,136 @@
+// Command notion-backup backs up everything a Notion integration can access to
+// a local archive, and restores archives into a page.
+//
+// A backup stores pages, databases (including their schema), block trees,
+// comments, users and Notion hosted files. Backing up to an existing archive
+// is incremental: only pages edited since the previous backup are crawled
+// again (comments are fetched again for all pages, as they don't change the
+// last edited time of pages), and pages and databases that are no longer
+// accessible are removed.
+//
+// A restore recreates the pages and databases of an archive, or of a subtree
+// of it, as descendants of a parent page. Relations, mentions and links to
+// restored pages and databases refer to the restored copies.
+//
+// Usage:
+//
+//	notion-backup backup -dir <archive> [-full] [-comments=false] [-files=false]
+//	notion-backup restore -dir <archive> -parent <page-id> [-root <id>]
+//
+// The API key is read from the NOTION_API_KEY environment variable.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/download"
+)
+
+const usage = `Usage:
+  notion-backup backup -dir <archive> [-full] [-comments=false] [-files=false]
+  notion-backup restore -dir <archive> -parent <page-id> [-root <id>]
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	var err error
+	switch os.Args[1] {
+	case "backup":
+		err = runBackup(ctx, os.Args[2:])
+	case "restore":
+		err = runRestore(ctx, os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runBackup(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	dir := fs.String("dir", "", "Archive directory")
+	full := fs.Bool("full", false, "Crawl all pages, also pages that didn't change since the previous backup")
+	comments := fs.Bool("comments", true, "Back up comments (takes a request per block)")
+	files := fs.Bool("files", true, "Download Notion hosted files")
+	fs.Parse(args)
+
+	if *dir == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	b := &backup{
+		client:     client,
+		archive:    archive{dir: *dir},
+		downloader: download.New(download.Options{Client: client}),
+		opts: backupOptions{
+			Full:     *full,
+			Comments: *comments,
+			Files:    *files,
+		},
+		log: log.Default(),
+	}
+
+	return b.run(ctx)
+}
+
+func runRestore(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	dir := fs.String("dir", "", "Archive directory")
+	parent := fs.String("parent", "", "ID of the page to restore into")
+	root := fs.String("root", "", "ID of an archived page or database to restore, with its descendants (default: all)")
+	fs.Parse(args)
+
+	if *dir == "" || *parent == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	r := &restore{
+		client:  client,
+		archive: archive{dir: *dir},
+		opts: restoreOptions{
+			ParentPageID: *parent,
+			RootID:       *root,
+		},
+		log: log.Default(),
+	}
+
+	return r.run(ctx)
+}
+
+func newClient() (*notion.Client, error) {
+	apiKey := os.Getenv("NOTION_API_KEY")
+	if apiKey == "" {
+		return nil, errors.New("NOTION_API_KEY is not set")
+	}
+
+	return notion.NewClient(apiKey), nil
+}
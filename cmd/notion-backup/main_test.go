@@ -0,0 +1,635 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/download"
+)
+
+const (
+	homeID  = "9b4a1a7a-4f0a-4c5e-9f43-2c0c3e6b8f01"
+	tasksID = "1f6e2a5c-8d8b-4a3f-b1b0-5a4e1e0c6d02"
+	taskID  = "c2d9e8a1-7b6f-4e3a-9c1d-0e2f3a4b5c03"
+	// projectsID is a database that isn't shared with the integration.
+	projectsID = "5d1c7e2b-3a4f-4b6e-8c9d-7f0e1a2b3c04"
+)
+
+var homePageJSON = `{
+	"object": "page",
+	"id": "` + homeID + `",
+	"created_time": "2022-05-01T10:00:00.000Z",
+	"last_edited_time": "2022-05-01T10:00:00.000Z",
+	"parent": {"type": "workspace", "workspace": true},
+	"icon": {"type": "emoji", "emoji": "🏠"},
+	"properties": {
+		"title": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "Home"}, "plain_text": "Home"}]}
+	}
+}`
+
+var tasksDatabaseJSON = `{
+	"object": "database",
+	"id": "` + tasksID + `",
+	"created_time": "2022-05-01T10:00:00.000Z",
+	"last_edited_time": "2022-05-01T10:00:00.000Z",
+	"parent": {"type": "page_id", "page_id": "` + homeID + `"},
+	"title": [{"type": "text", "text": {"content": "Tasks"}, "plain_text": "Tasks"}],
+	"properties": {
+		"Name": {"id": "title", "name": "Name", "type": "title", "title": {}},
+		"Tags": {"id": "a", "name": "Tags", "type": "multi_select", "multi_select": {"options": [{"id": "o1", "name": "A", "color": "red"}]}},
+		"Blocked by": {"id": "b", "name": "Blocked by", "type": "relation", "relation": {"database_id": "` + tasksID + `", "type": "single_property", "single_property": {}}},
+		"Project": {"id": "d", "name": "Project", "type": "relation", "relation": {"database_id": "` + projectsID + `", "type": "single_property", "single_property": {}}},
+		"Created": {"id": "c", "name": "Created", "type": "created_time", "created_time": {}}
+	}
+}`
+
+var taskPageJSON = `{
+	"object": "page",
+	"id": "` + taskID + `",
+	"created_time": "2022-05-01T11:00:00.000Z",
+	"last_edited_time": "2022-05-01T11:00:00.000Z",
+	"parent": {"type": "database_id", "database_id": "` + tasksID + `"},
+	"properties": {
+		"Name": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "Write docs"}, "plain_text": "Write docs"}]},
+		"Tags": {"id": "a", "type": "multi_select", "multi_select": [{"id": "o1", "name": "A", "color": "red"}]},
+		"Blocked by": {"id": "b", "type": "relation", "relation": [{"id": "` + taskID + `"}]},
+		"Project": {"id": "d", "type": "relation", "relation": [{"id": "a8f2c4d6-1b3e-4f5a-9c7d-2e4f6a8b0c05"}]},
+		"Created": {"id": "c", "type": "created_time", "created_time": "2022-05-01T11:00:00.000Z"}
+	}
+}`
+
+// blockChildrenJSON are the child blocks of the workspace, by parent ID.
+var blockChildrenJSON = map[string]string{
+	homeID: `[
+		{"object": "block", "id": "b1", "type": "paragraph", "paragraph": {"rich_text": [
+			{"type": "text", "text": {"content": "See "}, "plain_text": "See "},
+			{"type": "mention", "mention": {"type": "page", "page": {"id": "` + taskID + `"}}, "plain_text": "Write docs"}
+		]}},
+		{"object": "block", "id": "b2", "type": "toggle", "has_children": true, "toggle": {"rich_text": [{"type": "text", "text": {"content": "More"}, "plain_text": "More"}]}},
+		{"object": "block", "id": "b3", "type": "child_database", "child_database": {"title": "Tasks"}},
+		{"object": "block", "id": "b4", "type": "column_list", "has_children": true, "column_list": {}},
+		{"object": "block", "id": "b5", "type": "link_to_page", "link_to_page": {"type": "page_id", "page_id": "` + taskID + `"}}
+	]`,
+	"b2": `[
+		{"object": "block", "id": "b21", "type": "paragraph", "paragraph": {"rich_text": [{"type": "text", "text": {"content": "Hidden"}, "plain_text": "Hidden"}]}}
+	]`,
+	"b4": `[
+		{"object": "block", "id": "b41", "type": "column", "has_children": true, "column": {}},
+		{"object": "block", "id": "b42", "type": "column", "has_children": true, "column": {}}
+	]`,
+	"b41": `[
+		{"object": "block", "id": "b411", "type": "paragraph", "paragraph": {"rich_text": [{"type": "text", "text": {"content": "Left"}, "plain_text": "Left"}]}},
+		{"object": "block", "id": "b412", "type": "table", "has_children": true, "table": {"table_width": 1, "has_column_header": false, "has_row_header": false}}
+	]`,
+	"b412": `[
+		{"object": "block", "id": "b4121", "type": "table_row", "table_row": {"cells": [[{"type": "text", "text": {"content": "Cell"}, "plain_text": "Cell"}]]}}
+	]`,
+	"b42": `[
+		{"object": "block", "id": "b421", "type": "bulleted_list_item", "has_children": true, "bulleted_list_item": {"rich_text": [{"type": "text", "text": {"content": "Right"}, "plain_text": "Right"}]}}
+	]`,
+	"b421": `[
+		{"object": "block", "id": "b4211", "type": "paragraph", "paragraph": {"rich_text": [{"type": "text", "text": {"content": "Nested"}, "plain_text": "Nested"}]}}
+	]`,
+	taskID: `[]`,
+}
+
+func mustUnmarshal(t *testing.T, data string, v interface{}) {
+	t.Helper()
+
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func blockChildren(t *testing.T, blockID string) notion.BlockChildrenResponse {
+	t.Helper()
+
+	var resp notion.BlockChildrenResponse
+	mustUnmarshal(t, `{"results": `+blockChildrenJSON[blockID]+`}`, &resp)
+
+	return resp
+}
+
+func newSourceClient(t *testing.T, taskEdited time.Time) *notion.MockClient {
+	var (
+		home  notion.Page
+		tasks notion.Database
+		task  notion.Page
+	)
+	mustUnmarshal(t, homePageJSON, &home)
+	mustUnmarshal(t, tasksDatabaseJSON, &tasks)
+	mustUnmarshal(t, taskPageJSON, &task)
+	task.LastEditedTime = taskEdited
+
+	return &notion.MockClient{
+		SearchAllFunc: func(ctx context.Context, opts *notion.SearchAllOpts) (notion.SearchResults, error) {
+			return notion.SearchResults{
+				notion.NewPageSearchResult(home),
+				notion.NewDatabaseSearchResult(tasks),
+				notion.NewPageSearchResult(task),
+			}, nil
+		},
+		FindBlockChildrenByIDFunc: func(ctx context.Context, blockID string, query *notion.PaginationQuery) (notion.BlockChildrenResponse, error) {
+			if _, ok := blockChildrenJSON[blockID]; !ok {
+				t.Fatalf("unexpected block ID: %v", blockID)
+			}
+			return blockChildren(t, blockID), nil
+		},
+		FindCommentsByBlockIDFunc: func(ctx context.Context, query notion.FindCommentsByBlockIDQuery) (notion.FindCommentsResponse, error) {
+			if query.BlockID != homeID {
+				return notion.FindCommentsResponse{}, nil
+			}
+			var resp notion.FindCommentsResponse
+			mustUnmarshal(t, `{"results": [
+				{"id": "c1", "discussion_id": "d1", "parent": {"type": "page_id", "page_id": "`+homeID+`"}, "rich_text": [{"type": "text", "text": {"content": "First"}, "plain_text": "First"}]},
+				{"id": "c2", "discussion_id": "d1", "parent": {"type": "page_id", "page_id": "`+homeID+`"}, "rich_text": [{"type": "text", "text": {"content": "Reply"}, "plain_text": "Reply"}]}
+			]}`, &resp)
+			return resp, nil
+		},
+		ListUsersFunc: func(ctx context.Context, query *notion.PaginationQuery) (notion.ListUsersResponse, error) {
+			return notion.ListUsersResponse{Results: []notion.User{{BaseUser: notion.BaseUser{ID: "u1"}, Name: "Jane"}}}, nil
+		},
+	}
+}
+
+func TestBackup(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logger := log.New(io.Discard, "", 0)
+	edited := time.Date(2022, 5, 1, 11, 0, 0, 0, time.UTC)
+
+	client := newSourceClient(t, edited)
+	b := &backup{client: client, archive: archive{dir: dir}, opts: backupOptions{Comments: true}, log: logger}
+	if err := b.run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m, err := b.archive.readManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := (map[string]string{homeID: "Home", taskID: "Write docs"}), titles(m.Pages); !cmp.Equal(exp, got) {
+		t.Errorf("pages not equal (-exp, +got):\n%v", cmp.Diff(exp, got))
+	}
+	if exp, got := (map[string]string{tasksID: "Tasks"}), titles(m.Databases); !cmp.Equal(exp, got) {
+		t.Errorf("databases not equal (-exp, +got):\n%v", cmp.Diff(exp, got))
+	}
+
+	rec, err := b.archive.readPage(homeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	walkNodes(rec.Blocks, func(n node) {
+		ids = append(ids, n.Block.ID())
+	})
+	if diff := cmp.Diff([]string{"b1", "b2", "b21", "b3", "b4", "b41", "b411", "b412", "b4121", "b42", "b421", "b4211", "b5"}, ids); diff != "" {
+		t.Errorf("block tree not equal (-exp, +got):\n%v", diff)
+	}
+	if exp, got := 2, len(rec.Comments); exp != got {
+		t.Errorf("comments not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	// An incremental backup only crawls changed pages.
+	for _, tt := range []struct {
+		edited     time.Time
+		expCrawled []string
+	}{
+		{edited: edited, expCrawled: nil},
+		{edited: edited.Add(time.Hour), expCrawled: []string{taskID}},
+	} {
+		client := newSourceClient(t, tt.edited)
+		b := &backup{client: client, archive: archive{dir: dir}, opts: backupOptions{Comments: true}, log: logger}
+		if err := b.run(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var crawled []string
+		for _, call := range client.CallsTo("FindBlockChildrenByID") {
+			crawled = append(crawled, call.Args[1].(string))
+		}
+		if diff := cmp.Diff(tt.expCrawled, crawled); diff != "" {
+			t.Errorf("crawled blocks not equal (-exp, +got):\n%v", diff)
+		}
+
+		// Comments of unchanged pages are fetched again: one request for each
+		// page and block.
+		if exp, got := 15, len(client.CallsTo("FindCommentsByBlockID")); exp != got {
+			t.Errorf("comment requests not equal (expected: %v, got: %v)", exp, got)
+		}
+	}
+}
+
+// uploadMock is a restoreClient that records file uploads.
+type uploadMock struct {
+	*notion.MockClient
+	uploads []notion.UploadFileParams
+}
+
+func (m *uploadMock) UploadFile(ctx context.Context, params notion.UploadFileParams) (notion.FileUpload, error) {
+	m.uploads = append(m.uploads, params)
+	return notion.FileUpload{ID: fmt.Sprintf("upload-%v", len(m.uploads))}, nil
+}
+
+func TestDatabaseFiles(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("cover"))
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	logger := log.New(io.Discard, "", 0)
+
+	var tasks notion.Database
+	mustUnmarshal(t, tasksDatabaseJSON, &tasks)
+	tasks.Parent = notion.Parent{Type: notion.ParentTypeWorkspace, Workspace: true}
+	tasks.Cover = &notion.Cover{
+		Type: notion.FileTypeFile,
+		File: &notion.FileFile{URL: srv.URL + "/cover.png", ExpiryTime: notion.NewDateTime(time.Now().UTC().Add(time.Hour).Truncate(time.Millisecond), true)},
+	}
+
+	source := &notion.MockClient{
+		SearchAllFunc: func(ctx context.Context, opts *notion.SearchAllOpts) (notion.SearchResults, error) {
+			return notion.SearchResults{notion.NewDatabaseSearchResult(tasks)}, nil
+		},
+		ListUsersFunc: func(ctx context.Context, query *notion.PaginationQuery) (notion.ListUsersResponse, error) {
+			return notion.ListUsersResponse{}, nil
+		},
+	}
+
+	b := &backup{
+		client:     source,
+		archive:    archive{dir: dir},
+		downloader: download.New(download.Options{Client: source}),
+		opts:       backupOptions{Files: true},
+		log:        logger,
+	}
+	if err := b.run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec, err := b.archive.readDatabase(tasksID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []archiveFile{{Property: "cover", Name: "cover.png", Path: "files/" + tasksID + "/cover.png"}}
+	if diff := cmp.Diff(exp, rec.Files); diff != "" {
+		t.Fatalf("files not equal (-exp, +got):\n%v", diff)
+	}
+
+	target := &uploadMock{MockClient: newTargetWorkspace(t).client}
+	r := &restore{
+		client:  target,
+		archive: archive{dir: dir},
+		opts:    restoreOptions{ParentPageID: "parent"},
+		log:     logger,
+	}
+	if err := r.run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if exp, got := 1, len(target.uploads); exp != got {
+		t.Fatalf("uploads not equal (expected: %v, got: %v)", exp, got)
+	}
+	db := target.CallsTo("CreateDatabase")[0].Args[1].(notion.CreateDatabaseParams)
+	expCover := &notion.Cover{Type: notion.FileTypeFileUpload, FileUpload: &notion.FileUploadFile{ID: "upload-1"}}
+	if diff := cmp.Diff(expCover, db.Cover); diff != "" {
+		t.Errorf("cover not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func titles(entries map[string]entry) map[string]string {
+	titles := make(map[string]string, len(entries))
+	for id, e := range entries {
+		titles[id] = e.Title
+	}
+	return titles
+}
+
+// restoreMock is a restoreClient without file uploads.
+type restoreMock struct {
+	*notion.MockClient
+}
+
+func (m restoreMock) UploadFile(ctx context.Context, params notion.UploadFileParams) (notion.FileUpload, error) {
+	return notion.FileUpload{}, fmt.Errorf("unexpected upload of %v", params.Filename)
+}
+
+// backupFixture backs up the fixtures to a new archive directory.
+func backupFixture(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	b := &backup{
+		client:  newSourceClient(t, time.Date(2022, 5, 1, 11, 0, 0, 0, time.UTC)),
+		archive: archive{dir: dir},
+		opts:    backupOptions{Comments: true},
+		log:     log.New(io.Discard, "", 0),
+	}
+	if err := b.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+// targetWorkspace fakes the workspace that archives are restored into.
+type targetWorkspace struct {
+	client *notion.MockClient
+	nextID int
+	// appended holds the new IDs of appended blocks by parent ID, including
+	// children appended along with their parent.
+	appended map[string][]string
+	blocks   map[string]notion.Block
+}
+
+func newTargetWorkspace(t *testing.T) *targetWorkspace {
+	w := &targetWorkspace{
+		appended: make(map[string][]string),
+		blocks:   make(map[string]notion.Block),
+	}
+
+	w.client = &notion.MockClient{
+		CreatePageFunc: func(ctx context.Context, params notion.CreatePageParams) (notion.Page, error) {
+			return notion.Page{ID: w.newID()}, nil
+		},
+		CreateDatabaseFunc: func(ctx context.Context, params notion.CreateDatabaseParams) (notion.Database, error) {
+			return notion.Database{ID: w.newID()}, nil
+		},
+		UpdateDatabaseFunc: func(ctx context.Context, id string, params notion.UpdateDatabaseParams) (notion.Database, error) {
+			return notion.Database{ID: id}, nil
+		},
+		UpdatePageFunc: func(ctx context.Context, id string, params notion.UpdatePageParams) (notion.Page, error) {
+			return notion.Page{ID: id}, nil
+		},
+		AppendBlockChildrenFunc: func(ctx context.Context, blockID string, children []notion.Block) (notion.BlockChildrenResponse, error) {
+			return notion.BlockChildrenResponse{Results: w.appendBlocks(t, blockID, children)}, nil
+		},
+		// Children are listed one per page, to cover pagination.
+		FindBlockChildrenByIDFunc: func(ctx context.Context, blockID string, query *notion.PaginationQuery) (notion.BlockChildrenResponse, error) {
+			var resp notion.BlockChildrenResponse
+			i := 0
+			if query.StartCursor != "" {
+				i, _ = strconv.Atoi(query.StartCursor)
+			}
+			if ids := w.appended[blockID]; i < len(ids) {
+				resp.Results = []notion.Block{withID(t, w.blocks[ids[i]], ids[i])}
+				if i+1 < len(ids) {
+					resp.HasMore = true
+					resp.NextCursor = notion.StringPtr(strconv.Itoa(i + 1))
+				}
+			}
+			return resp, nil
+		},
+		CreateCommentFunc: func(ctx context.Context, params notion.CreateCommentParams) (notion.Comment, error) {
+			return notion.Comment{ID: w.newID(), DiscussionID: "new-discussion"}, nil
+		},
+	}
+
+	return w
+}
+
+func (w *targetWorkspace) newID() string {
+	w.nextID++
+	return fmt.Sprintf("new-%v", w.nextID)
+}
+
+func (w *targetWorkspace) appendBlocks(t *testing.T, parentID string, children []notion.Block) []notion.Block {
+	results := make([]notion.Block, len(children))
+	for i, child := range children {
+		id := w.newID()
+		w.blocks[id] = child
+		w.appended[parentID] = append(w.appended[parentID], id)
+		results[i] = withID(t, child, id)
+		w.appendBlocks(t, id, notion.BlockChildren(child))
+	}
+	return results
+}
+
+// text returns the block types and plain text of the blocks appended to a
+// parent, as indented tree.
+func (w *targetWorkspace) text(parentID, indent string) string {
+	var out string
+	for _, id := range w.appended[parentID] {
+		block := w.blocks[id]
+		out += indent + string(notion.BlockTypeOf(block))
+		if rt := notion.BlockRichText(block); rt != nil {
+			out += " " + notion.PlainText(rt)
+		}
+		out += "\n" + w.text(id, indent+"  ")
+	}
+	return out
+}
+
+func TestRestore(t *testing.T) {
+	t.Parallel()
+
+	dir := backupFixture(t)
+	target := newTargetWorkspace(t)
+	client := target.client
+
+	r := &restore{
+		client:  restoreMock{client},
+		archive: archive{dir: dir},
+		opts:    restoreOptions{ParentPageID: "parent"},
+		log:     log.New(io.Discard, "", 0),
+	}
+	if err := r.run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(map[string]string{homeID: "new-1", tasksID: "new-2", taskID: "new-3"}, r.ids); diff != "" {
+		t.Fatalf("restored IDs not equal (-exp, +got):\n%v", diff)
+	}
+
+	calls := client.CallsTo("CreatePage")
+	home := calls[0].Args[1].(notion.CreatePageParams)
+	if exp, got := "parent", home.ParentID; exp != got {
+		t.Errorf("parent of home page not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	db := client.CallsTo("CreateDatabase")[0].Args[1].(notion.CreateDatabaseParams)
+	if exp, got := "new-1", db.ParentPageID; exp != got {
+		t.Errorf("parent of database not equal (expected: %v, got: %v)", exp, got)
+	}
+	if diff := cmp.Diff([]string{"Created", "Name", "Tags"}, propNames(db.Properties)); diff != "" {
+		t.Errorf("database properties not equal (-exp, +got):\n%v", diff)
+	}
+	if diff := cmp.Diff([]notion.SelectOptions{{Name: "A", Color: "red"}}, db.Properties["Tags"].MultiSelect.Options); diff != "" {
+		t.Errorf("options not equal (-exp, +got):\n%v", diff)
+	}
+
+	// The relation to the projects database is skipped, as it isn't archived.
+	relations := client.CallsTo("UpdateDatabase")[0].Args[2].(notion.UpdateDatabaseParams)
+	if diff := cmp.Diff([]string{"Blocked by"}, relationNames(relations.Properties)); diff != "" {
+		t.Errorf("relation properties not equal (-exp, +got):\n%v", diff)
+	}
+	if exp, got := "new-2", relations.Properties["Blocked by"].Relation.DatabaseID; exp != got {
+		t.Errorf("related database not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	task := calls[1].Args[1].(notion.CreatePageParams)
+	if exp, got := notion.ParentTypeDatabase, task.ParentType; exp != got {
+		t.Errorf("parent type of task not equal (expected: %v, got: %v)", exp, got)
+	}
+	if diff := cmp.Diff([]string{"Name", "Tags"}, propNames(*task.DatabasePageProperties)); diff != "" {
+		t.Errorf("task properties not equal (-exp, +got):\n%v", diff)
+	}
+
+	update := client.CallsTo("UpdatePage")[0].Args[2].(notion.UpdatePageParams)
+	if diff := cmp.Diff([]string{"Blocked by"}, propNames(update.DatabasePageProperties)); diff != "" {
+		t.Errorf("updated properties not equal (-exp, +got):\n%v", diff)
+	}
+	if diff := cmp.Diff([]notion.Relation{{ID: "new-3"}}, update.DatabasePageProperties["Blocked by"].Relation); diff != "" {
+		t.Errorf("relation not equal (-exp, +got):\n%v", diff)
+	}
+
+	exp := `paragraph See Write docs
+toggle More
+  paragraph Hidden
+column_list
+  column
+    paragraph Left
+    table
+      table_row Cell
+  column
+    bulleted_list_item Right
+      paragraph Nested
+link_to_page
+`
+	if diff := cmp.Diff(exp, target.text("new-1", "")); diff != "" {
+		t.Errorf("appended blocks not equal (-exp, +got):\n%v", diff)
+	}
+
+	first := target.blocks[target.appended["new-1"][0]].(*notion.ParagraphBlock)
+	if exp, got := "new-3", first.RichText[1].Mention.Page.ID; exp != got {
+		t.Errorf("mentioned page not equal (expected: %v, got: %v)", exp, got)
+	}
+	link := target.blocks[target.appended["new-1"][3]].(*notion.LinkToPageBlock)
+	if exp, got := "new-3", link.PageID; exp != got {
+		t.Errorf("linked page not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	comments := client.CallsTo("CreateComment")
+	if exp, got := "new-1", comments[0].Args[1].(notion.CreateCommentParams).ParentPageID; exp != got {
+		t.Errorf("comment page not equal (expected: %v, got: %v)", exp, got)
+	}
+	if exp, got := "new-discussion", comments[1].Args[1].(notion.CreateCommentParams).DiscussionID; exp != got {
+		t.Errorf("reply discussion not equal (expected: %v, got: %v)", exp, got)
+	}
+}
+
+func TestRestoreSubtree(t *testing.T) {
+	t.Parallel()
+
+	dir := backupFixture(t)
+	target := newTargetWorkspace(t)
+	client := target.client
+
+	r := &restore{
+		client:  restoreMock{client},
+		archive: archive{dir: dir},
+		opts:    restoreOptions{ParentPageID: "parent", RootID: tasksID},
+		log:     log.New(io.Discard, "", 0),
+	}
+	if err := r.run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The home page isn't restored; the database is restored into the parent
+	// page of the restore.
+	if diff := cmp.Diff(map[string]string{tasksID: "new-1", taskID: "new-2"}, r.ids); diff != "" {
+		t.Fatalf("restored IDs not equal (-exp, +got):\n%v", diff)
+	}
+	db := client.CallsTo("CreateDatabase")[0].Args[1].(notion.CreateDatabaseParams)
+	if exp, got := "parent", db.ParentPageID; exp != got {
+		t.Errorf("parent of database not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	task := client.CallsTo("CreatePage")[0].Args[1].(notion.CreatePageParams)
+	if exp, got := "new-1", task.ParentID; exp != got {
+		t.Errorf("parent of task not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	updates := client.CallsTo("UpdatePage")
+	if exp, got := 1, len(updates); exp != got {
+		t.Fatalf("page updates not equal (expected: %v, got: %v)", exp, got)
+	}
+	update := updates[0].Args[2].(notion.UpdatePageParams)
+	if diff := cmp.Diff([]string{"Blocked by"}, propNames(update.DatabasePageProperties)); diff != "" {
+		t.Errorf("updated properties not equal (-exp, +got):\n%v", diff)
+	}
+
+	if calls := client.CallsTo("AppendBlockChildren"); len(calls) != 0 {
+		t.Errorf("expected no appended blocks, got %v requests", len(calls))
+	}
+}
+
+// withID returns a copy of a block with an ID, as returned by the API.
+func withID(t *testing.T, block notion.Block, id string) notion.Block {
+	t.Helper()
+
+	b, err := notion.MarshalBlock(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var obj map[string]interface{}
+	mustUnmarshal(t, string(b), &obj)
+	obj["id"] = id
+	// Like the API, children aren't included.
+	if content, ok := obj[string(notion.BlockTypeOf(block))].(map[string]interface{}); ok {
+		delete(content, "children")
+	}
+
+	if b, err = json.Marshal(obj); err != nil {
+		t.Fatal(err)
+	}
+	copied, err := notion.ParseBlock(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return copied
+}
+
+func relationNames(props map[string]*notion.DatabaseProperty) []string {
+	var names []string
+	for name, prop := range props {
+		if prop.Type == notion.DBPropTypeRelation {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func propNames(props interface{}) []string {
+	var names []string
+	switch props := props.(type) {
+	case notion.DatabaseProperties:
+		for name := range props {
+			names = append(names, name)
+		}
+	case notion.DatabasePageProperties:
+		for name := range props {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
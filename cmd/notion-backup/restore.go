@@ -0,0 +1,935 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/skedida/go-notion"
+)
+
+// maxAppendBlocks is the maximum number of blocks per append request.
+const maxAppendBlocks = 100
+
+var errFileNotArchived = errors.New("file not found in archive")
+
+// restoreClient is implemented by *notion.Client.
+type restoreClient interface {
+	notion.API
+	UploadFile(ctx context.Context, params notion.UploadFileParams) (notion.FileUpload, error)
+}
+
+type restoreOptions struct {
+	// ParentPageID is the page that top-level pages and databases of the
+	// archive are restored into.
+	ParentPageID string
+	// RootID, if set, only restores this page or database and its
+	// descendants.
+	RootID string
+}
+
+// restore recreates pages and databases of an archive. As the restored objects
+// get new IDs, references to archived objects (parents, relations, mentions
+// and links to pages) are remapped to the restored objects. References to
+// objects that aren't restored are kept as is.
+//
+// Restoring happens in three passes:
+//
+//  1. Databases (without relation and rollup properties) and pages (without
+//     relations or content) are created, parents before children.
+//  2. Relation and rollup properties are added to databases. Two-way
+//     relations are restored as one-way relations on both sides.
+//  3. Relations, blocks and comments are added to pages.
+//
+// Child page and child database blocks can't be appended, so restored child
+// pages and databases come after the other content of their parent. Comments
+// are restored as comments of the integration, and only page comments can be
+// restored, as the API doesn't support creating comments on blocks.
+type restore struct {
+	client  restoreClient
+	archive archive
+	opts    restoreOptions
+	log     *log.Logger
+
+	manifest  manifest
+	pages     map[string]pageRecord
+	databases map[string]databaseRecord
+	// owners maps block IDs to the ID of the page containing them.
+	owners map[string]string
+	// ids maps archived IDs to restored IDs.
+	ids map[string]string
+	// relationProps holds the names of the relation properties that were
+	// restored, by archived database ID.
+	relationProps map[string]map[string]bool
+}
+
+func (r *restore) run(ctx context.Context) error {
+	if err := r.load(); err != nil {
+		return err
+	}
+
+	selected := make(map[string]bool)
+	if r.opts.RootID != "" {
+		if _, ok := r.entry(r.opts.RootID); !ok {
+			return fmt.Errorf("page or database %v not found in archive", r.opts.RootID)
+		}
+		children := r.children()
+		var add func(id string)
+		add = func(id string) {
+			selected[id] = true
+			for _, child := range children[id] {
+				add(child)
+			}
+		}
+		add(r.opts.RootID)
+	} else {
+		for id := range r.pages {
+			selected[id] = true
+		}
+		for id := range r.databases {
+			selected[id] = true
+		}
+	}
+
+	order := r.order(selected)
+
+	for _, id := range order {
+		if err := r.create(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range order {
+		if rec, ok := r.databases[id]; ok {
+			if err := r.relations(ctx, rec.Database); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, id := range order {
+		if rec, ok := r.pages[id]; ok {
+			if err := r.content(ctx, rec); err != nil {
+				return err
+			}
+		}
+	}
+
+	r.log.Printf("Restored %v pages and databases.", len(order))
+
+	return nil
+}
+
+func (r *restore) load() error {
+	m, err := r.archive.readManifest()
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	r.manifest = m
+	r.pages = make(map[string]pageRecord, len(m.Pages))
+	r.databases = make(map[string]databaseRecord, len(m.Databases))
+	r.owners = make(map[string]string)
+	r.ids = make(map[string]string)
+	r.relationProps = make(map[string]map[string]bool)
+
+	for id := range m.Pages {
+		rec, err := r.archive.readPage(id)
+		if err != nil {
+			return fmt.Errorf("failed to read page: %w", err)
+		}
+		r.pages[id] = rec
+		walkNodes(rec.Blocks, func(n node) {
+			r.owners[n.Block.ID()] = id
+		})
+	}
+
+	for id := range m.Databases {
+		rec, err := r.archive.readDatabase(id)
+		if err != nil {
+			return fmt.Errorf("failed to read database: %w", err)
+		}
+		r.databases[id] = rec
+	}
+
+	return nil
+}
+
+func (r *restore) entry(id string) (entry, bool) {
+	if e, ok := r.manifest.Pages[id]; ok {
+		return e, true
+	}
+	e, ok := r.manifest.Databases[id]
+	return e, ok
+}
+
+// parentOf returns the ID of the page or database containing an archived page
+// or database, or an empty string for workspace level objects.
+func (r *restore) parentOf(id string) string {
+	e, _ := r.entry(id)
+
+	switch e.Parent.Type {
+	case notion.ParentTypePage:
+		return e.Parent.PageID
+	case notion.ParentTypeDatabase:
+		return e.Parent.DatabaseID
+	case notion.ParentTypeBlock:
+		return r.owners[e.Parent.BlockID]
+	}
+
+	return ""
+}
+
+// children maps IDs of pages and databases to the IDs of their children.
+func (r *restore) children() map[string][]string {
+	children := make(map[string][]string)
+
+	for id := range r.pages {
+		children[r.parentOf(id)] = append(children[r.parentOf(id)], id)
+	}
+	for id := range r.databases {
+		children[r.parentOf(id)] = append(children[r.parentOf(id)], id)
+	}
+
+	return children
+}
+
+// order returns the selected IDs with parents before their children, and
+// siblings in order of creation.
+func (r *restore) order(selected map[string]bool) []string {
+	var (
+		roots    []string
+		children = make(map[string][]string)
+	)
+
+	for id := range selected {
+		if parent := r.parentOf(id); selected[parent] {
+			children[parent] = append(children[parent], id)
+		} else {
+			roots = append(roots, id)
+		}
+	}
+
+	var (
+		order []string
+		visit func(ids []string)
+	)
+	visit = func(ids []string) {
+		sort.Slice(ids, func(i, j int) bool {
+			a, _ := r.entry(ids[i])
+			b, _ := r.entry(ids[j])
+			if !a.CreatedTime.Equal(b.CreatedTime) {
+				return a.CreatedTime.Before(b.CreatedTime)
+			}
+			return ids[i] < ids[j]
+		})
+		for _, id := range ids {
+			order = append(order, id)
+			visit(children[id])
+		}
+	}
+	visit(roots)
+
+	return order
+}
+
+// create creates a page or database in the restored parent, or in the parent
+// page of the restore if its parent isn't restored.
+func (r *restore) create(ctx context.Context, id string) error {
+	parentID, ok := r.ids[r.parentOf(id)]
+	if !ok {
+		parentID = r.opts.ParentPageID
+	}
+
+	if rec, ok := r.databases[id]; ok {
+		db := rec.Database
+		r.log.Printf("Restoring database %q (%v) ...", notion.PlainText(db.Title), id)
+
+		created, err := r.client.CreateDatabase(ctx, notion.CreateDatabaseParams{
+			ParentPageID: parentID,
+			Title:        r.richText(db.Title),
+			Description:  r.richText(db.Description),
+			Properties:   r.schema(db.Properties),
+			Icon:         r.icon(ctx, id, rec.Files, db.Icon),
+			Cover:        r.cover(ctx, id, rec.Files, db.Cover),
+			IsInline:     db.IsInline,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create database %v: %w", id, err)
+		}
+		r.ids[id] = created.ID
+
+		return nil
+	}
+
+	rec := r.pages[id]
+	page := rec.Page
+
+	r.log.Printf("Restoring page %q (%v) ...", page.Title(), id)
+
+	params := notion.CreatePageParams{
+		ParentType: notion.ParentTypePage,
+		ParentID:   parentID,
+		Icon:       r.icon(ctx, id, rec.Files, page.Icon),
+		Cover:      r.cover(ctx, id, rec.Files, page.Cover),
+	}
+
+	// Pages of a database that isn't restored become regular pages.
+	_, dbRestored := r.ids[page.Parent.DatabaseID]
+	if props, ok := page.DatabaseProperties(); ok && dbRestored {
+		props, err := r.properties(ctx, rec, props)
+		if err != nil {
+			return err
+		}
+		params.ParentType = notion.ParentTypeDatabase
+		params.DatabasePageProperties = &props
+	} else {
+		params.Title = r.richText(page.TitleRichText())
+		if params.Title == nil {
+			params.Title = []notion.RichText{}
+		}
+	}
+
+	created, err := r.client.CreatePage(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to create page %v: %w", id, err)
+	}
+	r.ids[id] = created.ID
+
+	return nil
+}
+
+// schema returns the properties of an archived database without relation and
+// rollup properties, which are restored once all databases exist.
+func (r *restore) schema(archived notion.DatabaseProperties) notion.DatabaseProperties {
+	props := make(notion.DatabaseProperties, len(archived))
+
+	for name, prop := range archived {
+		prop.ID = ""
+
+		switch prop.Type {
+		case notion.DBPropTypeRelation, notion.DBPropTypeRollup:
+			continue
+		case notion.DBPropTypeSelect:
+			prop.Select = &notion.SelectMetadata{Options: selectOptions(prop.Select)}
+		case notion.DBPropTypeMultiSelect:
+			prop.MultiSelect = &notion.SelectMetadata{Options: selectOptions(prop.MultiSelect)}
+		case notion.DBPropTypeStatus:
+			// Groups refer to option IDs, which are assigned on creation.
+			var options []notion.SelectOptions
+			if prop.Status != nil {
+				options = selectOptions(&notion.SelectMetadata{Options: prop.Status.Options})
+			}
+			prop.Status = &notion.StatusMetadata{Options: options}
+		}
+
+		props[name] = prop
+	}
+
+	return props
+}
+
+func selectOptions(metadata *notion.SelectMetadata) []notion.SelectOptions {
+	if metadata == nil {
+		return []notion.SelectOptions{}
+	}
+
+	options := make([]notion.SelectOptions, len(metadata.Options))
+	for i, option := range metadata.Options {
+		options[i] = notion.SelectOptions{Name: option.Name, Color: option.Color}
+	}
+
+	return options
+}
+
+// relations adds the relation and rollup properties of an archived database to
+// the restored database. Relations to databases that aren't restored are
+// skipped, as are rollups of skipped relations.
+func (r *restore) relations(ctx context.Context, db notion.Database) error {
+	var (
+		relations = make(map[string]*notion.DatabaseProperty)
+		rollups   = make(map[string]*notion.DatabaseProperty)
+	)
+
+	for name, prop := range db.Properties {
+		switch {
+		case prop.Type == notion.DBPropTypeRelation && prop.Relation != nil:
+			targetID, ok := r.ids[prop.Relation.DatabaseID]
+			if !ok {
+				r.log.Printf("Skipping relation %q of database %v: related database is not restored.", name, db.ID)
+				continue
+			}
+			relations[name] = &notion.DatabaseProperty{
+				Type: notion.DBPropTypeRelation,
+				Relation: &notion.RelationMetadata{
+					DatabaseID:     targetID,
+					Type:           notion.RelationTypeSingleProperty,
+					SingleProperty: &struct{}{},
+				},
+			}
+		case prop.Type == notion.DBPropTypeRollup && prop.Rollup != nil:
+			rollups[name] = &notion.DatabaseProperty{
+				Type: notion.DBPropTypeRollup,
+				Rollup: &notion.RollupMetadata{
+					RelationPropName: prop.Rollup.RelationPropName,
+					RollupPropName:   prop.Rollup.RollupPropName,
+					Function:         prop.Rollup.Function,
+				},
+			}
+		}
+	}
+
+	for name, prop := range rollups {
+		if _, ok := relations[prop.Rollup.RelationPropName]; !ok {
+			delete(rollups, name)
+		}
+	}
+
+	// Rollups can only be added once their relation exists.
+	for _, props := range []map[string]*notion.DatabaseProperty{relations, rollups} {
+		if len(props) == 0 {
+			continue
+		}
+		_, err := r.client.UpdateDatabase(ctx, r.ids[db.ID], notion.UpdateDatabaseParams{Properties: props})
+		if err != nil {
+			return fmt.Errorf("failed to restore relations of database %v: %w", db.ID, err)
+		}
+	}
+
+	r.relationProps[db.ID] = make(map[string]bool, len(relations))
+	for name := range relations {
+		r.relationProps[db.ID][name] = true
+	}
+
+	return nil
+}
+
+// properties returns the property values of an archived database page that can
+// be set on creation. Read-only properties are skipped, and relations are set
+// once all pages exist.
+func (r *restore) properties(ctx context.Context, rec pageRecord, archived notion.DatabasePageProperties) (notion.DatabasePageProperties, error) {
+	props := make(notion.DatabasePageProperties, len(archived))
+
+	for name, prop := range archived {
+		if prop.Raw != nil {
+			continue
+		}
+
+		value := notion.DatabasePageProperty{Type: prop.Type}
+
+		switch prop.Type {
+		case notion.DBPropTypeTitle:
+			value.Title = r.richText(prop.Title)
+			if value.Title == nil {
+				value.Title = []notion.RichText{}
+			}
+		case notion.DBPropTypeRichText:
+			value.RichText = r.richText(prop.RichText)
+			if value.RichText == nil {
+				value.RichText = []notion.RichText{}
+			}
+		case notion.DBPropTypeNumber:
+			value.Number = prop.Number
+		case notion.DBPropTypeSelect:
+			if prop.Select == nil {
+				continue
+			}
+			value.Select = &notion.SelectOptions{Name: prop.Select.Name}
+		case notion.DBPropTypeStatus:
+			if prop.Status == nil {
+				continue
+			}
+			value.Status = &notion.SelectOptions{Name: prop.Status.Name}
+		case notion.DBPropTypeMultiSelect:
+			value.MultiSelect = make([]notion.SelectOptions, len(prop.MultiSelect))
+			for i, option := range prop.MultiSelect {
+				value.MultiSelect[i] = notion.SelectOptions{Name: option.Name}
+			}
+		case notion.DBPropTypeDate:
+			if prop.Date == nil {
+				continue
+			}
+			value.Date = prop.Date
+		case notion.DBPropTypePeople:
+			value.People = make([]notion.User, len(prop.People))
+			for i, user := range prop.People {
+				value.People[i] = notion.User{BaseUser: notion.BaseUser{ID: user.ID}}
+			}
+		case notion.DBPropTypeFiles:
+			value.Files = []notion.File{}
+			for i, f := range prop.Files {
+				if f.Type == notion.FileTypeFile {
+					uploadID, err := r.upload(ctx, rec.Files, archiveFile{Property: name, Index: i})
+					if err != nil {
+						return nil, err
+					}
+					if uploadID == "" {
+						continue
+					}
+					f = notion.File{
+						Name:       f.Name,
+						Type:       notion.FileTypeFileUpload,
+						FileUpload: &notion.FileUploadFile{ID: uploadID},
+					}
+				}
+				value.Files = append(value.Files, f)
+			}
+		case notion.DBPropTypeCheckbox:
+			value.Checkbox = prop.Checkbox
+		case notion.DBPropTypeURL:
+			value.URL = prop.URL
+		case notion.DBPropTypeEmail:
+			value.Email = prop.Email
+		case notion.DBPropTypePhoneNumber:
+			value.PhoneNumber = prop.PhoneNumber
+		default:
+			// Relations are set by content, other types are read-only.
+			continue
+		}
+
+		props[name] = value
+	}
+
+	return props, nil
+}
+
+// content restores the relations, blocks and comments of a page. Values are
+// only set for relation properties that were restored on the database.
+func (r *restore) content(ctx context.Context, rec pageRecord) error {
+	pageID := r.ids[rec.Page.ID]
+
+	if props, ok := rec.Page.DatabaseProperties(); ok {
+		if _, dbRestored := r.ids[rec.Page.Parent.DatabaseID]; dbRestored {
+			var (
+				relations = make(notion.DatabasePageProperties)
+				restored  = r.relationProps[rec.Page.Parent.DatabaseID]
+			)
+			for name, prop := range props {
+				if prop.Type != notion.DBPropTypeRelation || len(prop.Relation) == 0 || !restored[name] {
+					continue
+				}
+				value := notion.DatabasePageProperty{Type: notion.DBPropTypeRelation, Relation: []notion.Relation{}}
+				for _, rel := range prop.Relation {
+					if id, ok := r.ids[rel.ID]; ok {
+						value.Relation = append(value.Relation, notion.Relation{ID: id})
+					}
+				}
+				relations[name] = value
+			}
+			if len(relations) > 0 {
+				_, err := r.client.UpdatePage(ctx, pageID, notion.UpdatePageParams{DatabasePageProperties: relations})
+				if err != nil {
+					return fmt.Errorf("failed to restore relations of page %v: %w", rec.Page.ID, err)
+				}
+			}
+		}
+	}
+
+	if err := r.appendChildren(ctx, rec, pageID, rec.Blocks); err != nil {
+		return fmt.Errorf("failed to restore blocks of page %v: %w", rec.Page.ID, err)
+	}
+
+	// Comments of the same discussion are restored as replies to the first.
+	discussions := make(map[string]string)
+	for _, comment := range rec.Comments {
+		if comment.Parent.Type != notion.ParentTypePage {
+			continue
+		}
+
+		params := notion.CreateCommentParams{RichText: r.richText(comment.RichText)}
+		if id, ok := discussions[comment.DiscussionID]; ok {
+			params.DiscussionID = id
+		} else {
+			params.ParentPageID = pageID
+		}
+
+		created, err := r.client.CreateComment(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to restore comment %v: %w", comment.ID, err)
+		}
+		discussions[comment.DiscussionID] = created.DiscussionID
+	}
+
+	return nil
+}
+
+// preparedBlock is an archived block, prepared to be appended.
+type preparedBlock struct {
+	node  node
+	block notion.Block
+	// children are set when they are appended along with the block, as
+	// required for some block types.
+	children []preparedBlock
+	inline   bool
+	// deferred are children of an inline block that are appended after it, as
+	// they would exceed the maximum nesting of an append request.
+	deferred []node
+}
+
+// appendChildren appends archived blocks and their descendants to a restored
+// page or block.
+func (r *restore) appendChildren(ctx context.Context, rec pageRecord, parentID string, nodes []node) error {
+	prepared, err := r.prepareBlocks(ctx, rec, nodes, 0)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(prepared); start += maxAppendBlocks {
+		end := start + maxAppendBlocks
+		if end > len(prepared) {
+			end = len(prepared)
+		}
+		batch := prepared[start:end]
+
+		blocks := make([]notion.Block, len(batch))
+		for i, p := range batch {
+			blocks[i] = p.block
+		}
+
+		resp, err := r.client.AppendBlockChildren(ctx, parentID, blocks)
+		if err != nil {
+			return err
+		}
+		if len(resp.Results) != len(batch) {
+			return fmt.Errorf("expected %v appended blocks, got %v", len(batch), len(resp.Results))
+		}
+
+		for i, p := range batch {
+			if err := r.appendDescendants(ctx, rec, resp.Results[i].ID(), p); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// appendDescendants appends the children of an archived block that weren't
+// appended along with it.
+func (r *restore) appendDescendants(ctx context.Context, rec pageRecord, blockID string, p preparedBlock) error {
+	if !p.inline {
+		if len(p.node.Children) == 0 {
+			return nil
+		}
+		return r.appendChildren(ctx, rec, blockID, p.node.Children)
+	}
+
+	pending := false
+	for _, child := range p.children {
+		pending = pending || child.inline || len(child.node.Children) > 0
+	}
+
+	if pending {
+		// The IDs of children appended along with their parent are only known
+		// by listing them.
+		children, err := r.listChildren(ctx, blockID)
+		if err != nil {
+			return err
+		}
+		if len(children) != len(p.children) {
+			return fmt.Errorf("expected %v child blocks, got %v", len(p.children), len(children))
+		}
+
+		for i, child := range p.children {
+			if err := r.appendDescendants(ctx, rec, children[i].ID(), child); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(p.deferred) == 0 {
+		return nil
+	}
+
+	return r.appendChildren(ctx, rec, blockID, p.deferred)
+}
+
+func (r *restore) listChildren(ctx context.Context, blockID string) ([]notion.Block, error) {
+	var (
+		children []notion.Block
+		query    = &notion.PaginationQuery{PageSize: 100}
+	)
+
+	for {
+		resp, err := r.client.FindBlockChildrenByID(ctx, blockID, query)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, resp.Results...)
+
+		if !resp.HasMore || resp.NextCursor == nil {
+			return children, nil
+		}
+		query.StartCursor = *resp.NextCursor
+	}
+}
+
+// prepareBlocks prepares archived blocks to be appended, skipping blocks that
+// can't be created. Tables, column lists and columns must be created with
+// children, which are set up to two levels deep, the maximum nesting of an
+// append request. Such blocks that are nested deeper are deferred, see
+// splitInline.
+func (r *restore) prepareBlocks(ctx context.Context, rec pageRecord, nodes []node, depth int) ([]preparedBlock, error) {
+	var prepared []preparedBlock
+
+	for _, n := range nodes {
+		block, err := r.prepareBlock(ctx, rec, n.Block)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			continue
+		}
+
+		p := preparedBlock{node: n, block: block}
+
+		if needsChildren(notion.BlockTypeOf(block)) {
+			var inline []node
+			inline, p.deferred = splitInline(n.Children, depth+1)
+			if p.children, err = r.prepareBlocks(ctx, rec, inline, depth+1); err != nil {
+				return nil, err
+			}
+			children := make([]notion.Block, len(p.children))
+			for i, child := range p.children {
+				children[i] = child.block
+			}
+			if _, err := notion.SetBlockChildren(block, children); err != nil {
+				return nil, err
+			}
+			p.inline = true
+		}
+
+		prepared = append(prepared, p)
+	}
+
+	return prepared, nil
+}
+
+// needsChildren reports whether blocks of a type must be created with their
+// children.
+func needsChildren(blockType notion.BlockType) bool {
+	switch blockType {
+	case notion.BlockTypeTable, notion.BlockTypeColumnList, notion.BlockTypeColumn:
+		return true
+	}
+	return false
+}
+
+// splitInline splits the children of an inline block, at the given depth, into
+// children that are appended along with it and deferred children, that are
+// appended once it exists. Children that must be created with their own
+// children can't be inlined below depth 2; they and their following siblings
+// are deferred, to keep the order of blocks. If that would leave no inline
+// children, only the blocks that can't be inlined are deferred, so they end up
+// after their siblings.
+func splitInline(children []node, depth int) (inline, deferred []node) {
+	if depth < 2 {
+		return children, nil
+	}
+
+	for i, child := range children {
+		if !needsChildren(notion.BlockTypeOf(child.Block)) {
+			continue
+		}
+		if i > 0 {
+			return children[:i], children[i:]
+		}
+
+		for _, child := range children {
+			if needsChildren(notion.BlockTypeOf(child.Block)) {
+				deferred = append(deferred, child)
+			} else {
+				inline = append(inline, child)
+			}
+		}
+		return inline, deferred
+	}
+
+	return children, nil
+}
+
+// prepareBlock updates an archived block to be appended: IDs of pages and
+// databases are remapped, synced blocks are restored as original synced
+// blocks, and Notion hosted files are uploaded from the archive. Nil is
+// returned for blocks that can't be created.
+func (r *restore) prepareBlock(ctx context.Context, rec pageRecord, block notion.Block) (notion.Block, error) {
+	var err error
+	ok := true
+
+	switch b := block.(type) {
+	case *notion.ChildPageBlock, *notion.ChildDatabaseBlock:
+		// Restored as pages and databases.
+		return nil, nil
+	case *notion.UnsupportedBlock, *notion.RawBlock, *notion.LinkPreviewBlock, *notion.TemplateBlock:
+		r.log.Printf("Skipping %v block %v: block type can't be created.", notion.BlockTypeOf(block), block.ID())
+		return nil, nil
+	case *notion.LinkToPageBlock:
+		b.PageID = r.id(b.PageID)
+		b.DatabaseID = r.id(b.DatabaseID)
+	case *notion.SyncedBlock:
+		b.SyncedFrom = nil
+	case *notion.TableRowBlock:
+		for i, cell := range b.Cells {
+			b.Cells[i] = r.richText(cell)
+		}
+	case *notion.ImageBlock:
+		ok, err = r.blockFile(ctx, rec, b.ID(), &b.Type, &b.File, &b.FileUpload)
+	case *notion.FileBlock:
+		ok, err = r.blockFile(ctx, rec, b.ID(), &b.Type, &b.File, &b.FileUpload)
+	case *notion.PDFBlock:
+		ok, err = r.blockFile(ctx, rec, b.ID(), &b.Type, &b.File, &b.FileUpload)
+	case *notion.AudioBlock:
+		ok, err = r.blockFile(ctx, rec, b.ID(), &b.Type, &b.File, &b.FileUpload)
+	case *notion.VideoBlock:
+		ok, err = r.blockFile(ctx, rec, b.ID(), &b.Type, &b.File, &b.FileUpload)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		r.log.Printf("Skipping %v block %v: %v", notion.BlockTypeOf(block), block.ID(), errFileNotArchived)
+		return nil, nil
+	}
+
+	if rt, ok := block.(notion.RichTexter); ok {
+		rt.SetRichText(r.richText(rt.GetRichText()))
+	}
+	if c, ok := block.(notion.Captioner); ok {
+		c.SetCaption(r.richText(c.GetCaption()))
+	}
+
+	return block, nil
+}
+
+// blockFile replaces the Notion hosted file of a block with an upload of the
+// archived file. False is returned if the file isn't archived.
+func (r *restore) blockFile(ctx context.Context, rec pageRecord, blockID string, fileType *notion.FileType, file **notion.FileFile, upload **notion.FileUploadFile) (bool, error) {
+	if *fileType != notion.FileTypeFile {
+		return true, nil
+	}
+
+	uploadID, err := r.upload(ctx, rec.Files, archiveFile{BlockID: blockID})
+	if err != nil || uploadID == "" {
+		return false, err
+	}
+
+	*fileType = notion.FileTypeFileUpload
+	*file = nil
+	*upload = &notion.FileUploadFile{ID: uploadID}
+
+	return true, nil
+}
+
+// icon returns the icon of an archived page or database, with a Notion hosted
+// file replaced by an upload of the archived file.
+func (r *restore) icon(ctx context.Context, ownerID string, files []archiveFile, icon *notion.Icon) *notion.Icon {
+	if icon == nil || icon.Type != notion.IconTypeFile {
+		return icon
+	}
+
+	uploadID, err := r.upload(ctx, files, archiveFile{Property: "icon"})
+	if err == nil && uploadID == "" {
+		err = errFileNotArchived
+	}
+	if err != nil {
+		r.log.Printf("Skipping icon of %v: %v", ownerID, err)
+		return nil
+	}
+
+	return &notion.Icon{Type: notion.IconTypeFileUpload, FileUpload: &notion.FileUploadFile{ID: uploadID}}
+}
+
+// cover returns the cover of an archived page or database, with a Notion
+// hosted file replaced by an upload of the archived file.
+func (r *restore) cover(ctx context.Context, ownerID string, files []archiveFile, cover *notion.Cover) *notion.Cover {
+	if cover == nil || cover.Type != notion.FileTypeFile {
+		return cover
+	}
+
+	uploadID, err := r.upload(ctx, files, archiveFile{Property: "cover"})
+	if err == nil && uploadID == "" {
+		err = errFileNotArchived
+	}
+	if err != nil {
+		r.log.Printf("Skipping cover of %v: %v", ownerID, err)
+		return nil
+	}
+
+	return &notion.Cover{Type: notion.FileTypeFileUpload, FileUpload: &notion.FileUploadFile{ID: uploadID}}
+}
+
+// upload uploads the archived file matching the block ID, property and index
+// of f, and returns the ID of the file upload. An empty ID is returned if the
+// file isn't archived.
+func (r *restore) upload(ctx context.Context, files []archiveFile, f archiveFile) (string, error) {
+	for _, archived := range files {
+		if archived.BlockID != f.BlockID || archived.Property != f.Property || archived.Index != f.Index {
+			continue
+		}
+
+		file, err := os.Open(r.archive.path(archived.Path))
+		if err != nil {
+			return "", fmt.Errorf("failed to open archived file: %w", err)
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			return "", fmt.Errorf("failed to open archived file: %w", err)
+		}
+
+		upload, err := r.client.UploadFile(ctx, notion.UploadFileParams{
+			Filename:    archived.Name,
+			ContentType: mime.TypeByExtension(filepath.Ext(archived.Name)),
+			Content:     file,
+			Size:        info.Size(),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to upload file %v: %w", archived.Path, err)
+		}
+
+		return upload.ID, nil
+	}
+
+	return "", nil
+}
+
+// richText returns a copy of rich text with mentions of archived pages and
+// databases remapped to the restored ones.
+func (r *restore) richText(richText []notion.RichText) []notion.RichText {
+	if richText == nil {
+		return nil
+	}
+
+	remapped := make([]notion.RichText, len(richText))
+
+	for i, rt := range richText {
+		if m := rt.Mention; m != nil {
+			mention := *m
+			if m.Page != nil {
+				mention.Page = &notion.ID{ID: r.id(m.Page.ID)}
+			}
+			if m.Database != nil {
+				mention.Database = &notion.ID{ID: r.id(m.Database.ID)}
+			}
+			rt.Mention = &mention
+		}
+		remapped[i] = rt
+	}
+
+	return remapped
+}
+
+// id returns the restored ID of an archived page or database, or id itself if
+// it isn't restored.
+func (r *restore) id(id string) string {
+	if restored, ok := r.ids[id]; ok {
+		return restored
+	}
+	return id
+}
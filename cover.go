@@ -5,8 +5,9 @@ import "errors"
 type Cover struct {
 	Type FileType `json:"type"`
 
-	File     *FileFile     `json:"file,omitempty"`
-	External *FileExternal `json:"external,omitempty"`
+	File       *FileFile       `json:"file,omitempty"`
+	External   *FileExternal   `json:"external,omitempty"`
+	FileUpload *FileUploadFile `json:"file_upload,omitempty"`
 }
 
 func (cover Cover) Validate() error {
@@ -17,6 +18,9 @@ func (cover Cover) Validate() error {
 	if cover.Type == FileTypeExternal && cover.External == nil {
 		return errors.New("cover external cannot be empty")
 	}
+	if cover.Type == FileTypeFileUpload && (cover.FileUpload == nil || cover.FileUpload.ID == "") {
+		return errors.New("cover file upload ID cannot be empty")
+	}
 
 	return nil
 }
@@ -0,0 +1,380 @@
+// Package csvimport creates Notion database pages from CSV files, either in an
+// existing database, or in a new database with a schema inferred from the CSV.
+package csvimport
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+const (
+	defaultConcurrency = 3
+
+	// defaultRequestInterval corresponds to the average rate limit of the Notion
+	// API: three requests per second.
+	defaultRequestInterval = time.Second / 3
+)
+
+// Client is the subset of the Notion API used for importing.
+type Client interface {
+	CreatePage(ctx context.Context, params notion.CreatePageParams) (notion.Page, error)
+	CreateDatabase(ctx context.Context, params notion.CreateDatabaseParams) (notion.Database, error)
+}
+
+// Options configure how CSV is imported.
+type Options struct {
+	// Columns maps CSV header names to database property names. Columns that
+	// aren't in the map are mapped to the property with the same name.
+	Columns map[string]string
+
+	// IgnoreUnknownColumns skips columns that aren't mapped to a database
+	// property, instead of returning an error.
+	IgnoreUnknownColumns bool
+
+	// AllowNewOptions allows values of select and multi-select columns that
+	// aren't existing options, which are then created by Notion. Status
+	// columns only allow existing options.
+	AllowNewOptions bool
+
+	// Separator separates values of multi-select, people, relation and files
+	// columns. Defaults to ",".
+	Separator string
+
+	// Location is used for times without UTC offset. Defaults to UTC.
+	Location *time.Location
+
+	// Concurrency is the number of pages that are created concurrently.
+	// Defaults to 3.
+	Concurrency int
+
+	// RequestInterval is the minimum interval between requests, used for rate
+	// limiting. Defaults to a third of a second.
+	RequestInterval time.Duration
+
+	// Resume is the report of a previous (failed) import of the same CSV. Rows
+	// for which a page was created are skipped. When creating a new database,
+	// the database of the previous import is used.
+	Resume *Report
+}
+
+// RowResult is the result of importing a CSV row.
+type RowResult struct {
+	// Row is the number of the CSV record, excluding the header, starting at 1.
+	Row int `json:"row"`
+
+	// PageID is the ID of the created page.
+	PageID string `json:"page_id,omitempty"`
+
+	// Skipped is true if the page was created in a previous import.
+	Skipped bool `json:"skipped,omitempty"`
+
+	// Err is the error that occurred, if any. Error holds its message, to
+	// allow saving a report for resuming an import.
+	Err   error  `json:"-"`
+	Error string `json:"error,omitempty"`
+}
+
+// Report contains the result of each CSV row of an import.
+type Report struct {
+	DatabaseID string      `json:"database_id"`
+	Rows       []RowResult `json:"rows"`
+}
+
+// Created returns the number of created pages, including pages that were
+// created in a previous import.
+func (r Report) Created() int {
+	n := 0
+	for _, row := range r.Rows {
+		if row.PageID != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns the results of rows that failed to import.
+func (r Report) Failed() []RowResult {
+	var failed []RowResult
+	for _, row := range r.Rows {
+		if row.PageID == "" {
+			failed = append(failed, row)
+		}
+	}
+	return failed
+}
+
+// Err returns an error describing the failed rows, or nil if all rows were
+// imported.
+func (r Report) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, 0, len(failed))
+	for i, row := range failed {
+		if i == 10 {
+			msgs = append(msgs, fmt.Sprintf("and %v more", len(failed)-i))
+			break
+		}
+		msgs = append(msgs, fmt.Sprintf("row %v: %v", row.Row, row.Error))
+	}
+
+	return fmt.Errorf("csvimport: failed to import %v of %v rows: %v",
+		len(failed), len(r.Rows), strings.Join(msgs, "; "))
+}
+
+// Importer imports CSV into databases.
+type Importer struct {
+	client Client
+	opts   Options
+}
+
+// New returns a new Importer.
+func New(client Client, opts Options) *Importer {
+	if opts.Separator == "" {
+		opts.Separator = ","
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = defaultConcurrency
+	}
+	if opts.RequestInterval <= 0 {
+		opts.RequestInterval = defaultRequestInterval
+	}
+
+	return &Importer{client: client, opts: opts}
+}
+
+// Import reads CSV with a header record from r, and creates a page in db for
+// each record. Errors of individual rows are reported per row in the returned
+// report; an error is only returned if the CSV can't be read or its columns
+// can't be mapped to the database schema.
+func (imp *Importer) Import(ctx context.Context, r io.Reader, db notion.Database) (Report, error) {
+	header, rows, err := readCSV(r)
+	if err != nil {
+		return Report{}, err
+	}
+
+	return imp.importRows(ctx, db, header, rows)
+}
+
+// ImportNew reads CSV with a header record from r, creates a database with a
+// schema inferred from the CSV (see Importer.InferSchema) as child of a page, and then
+// imports the CSV into it.
+func (imp *Importer) ImportNew(ctx context.Context, r io.Reader, parentPageID, title string) (notion.Database, Report, error) {
+	header, rows, err := readCSV(r)
+	if err != nil {
+		return notion.Database{}, Report{}, err
+	}
+
+	db := notion.Database{Properties: imp.InferSchema(header, rows)}
+
+	if imp.opts.Resume != nil && imp.opts.Resume.DatabaseID != "" {
+		db.ID = imp.opts.Resume.DatabaseID
+	} else {
+		db, err = imp.client.CreateDatabase(ctx, notion.CreateDatabaseParams{
+			ParentPageID: parentPageID,
+			Title:        notion.SplitRichText(title),
+			Properties:   db.Properties,
+		})
+		if err != nil {
+			return notion.Database{}, Report{}, fmt.Errorf("csvimport: failed to create database: %w", err)
+		}
+	}
+
+	report, err := imp.importRows(ctx, db, header, rows)
+	if err != nil {
+		return notion.Database{}, Report{}, err
+	}
+
+	return db, report, nil
+}
+
+// PageProperties maps a CSV record to page properties of a database.
+func (imp *Importer) PageProperties(db notion.Database, header, record []string) (notion.DatabasePageProperties, error) {
+	columns, err := imp.columns(db, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return imp.pageProperties(columns, record)
+}
+
+func (imp *Importer) importRows(ctx context.Context, db notion.Database, header []string, rows [][]string) (Report, error) {
+	columns, err := imp.columns(db, header)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		DatabaseID: db.ID,
+		Rows:       make([]RowResult, len(rows)),
+	}
+
+	created := make(map[int]string)
+	if imp.opts.Resume != nil {
+		for _, row := range imp.opts.Resume.Rows {
+			if row.PageID != "" {
+				created[row.Row] = row.PageID
+			}
+		}
+	}
+
+	type job struct {
+		index  int
+		params notion.CreatePageParams
+	}
+
+	var jobs []job
+
+	for i, record := range rows {
+		result := &report.Rows[i]
+		result.Row = i + 1
+
+		if pageID, ok := created[result.Row]; ok {
+			result.PageID = pageID
+			result.Skipped = true
+			continue
+		}
+
+		props, err := imp.pageProperties(columns, record)
+		if err != nil {
+			result.setErr(err)
+			continue
+		}
+
+		jobs = append(jobs, job{
+			index: i,
+			params: notion.CreatePageParams{
+				ParentType:             notion.ParentTypeDatabase,
+				ParentID:               db.ID,
+				DatabasePageProperties: &props,
+			},
+		})
+	}
+
+	ticker := time.NewTicker(imp.opts.RequestInterval)
+	defer ticker.Stop()
+
+	queue := make(chan job)
+	var wg sync.WaitGroup
+
+	for n := 0; n < imp.opts.Concurrency; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := range queue {
+				result := &report.Rows[j.index]
+
+				select {
+				case <-ctx.Done():
+					result.setErr(ctx.Err())
+					continue
+				case <-ticker.C:
+				}
+
+				page, err := imp.client.CreatePage(ctx, j.params)
+				if err != nil {
+					result.setErr(err)
+					continue
+				}
+				result.PageID = page.ID
+			}
+		}()
+	}
+
+	for _, j := range jobs {
+		queue <- j
+	}
+	close(queue)
+	wg.Wait()
+
+	return report, nil
+}
+
+// columns maps CSV header names to database properties.
+func (imp *Importer) columns(db notion.Database, header []string) ([]column, error) {
+	columns := make([]column, 0, len(header))
+
+	for i, name := range header {
+		if mapped, ok := imp.opts.Columns[name]; ok {
+			name = mapped
+		}
+
+		prop, ok := db.Properties[name]
+		if !ok {
+			if imp.opts.IgnoreUnknownColumns {
+				continue
+			}
+			return nil, fmt.Errorf("csvimport: column %q doesn't match a database property", header[i])
+		}
+		if !writable(prop.Type) {
+			return nil, fmt.Errorf("%w: %q (%v)", ErrReadOnlyProperty, name, prop.Type)
+		}
+
+		columns = append(columns, column{index: i, name: name, prop: prop})
+	}
+
+	return columns, nil
+}
+
+func (imp *Importer) pageProperties(columns []column, record []string) (notion.DatabasePageProperties, error) {
+	props := make(notion.DatabasePageProperties, len(columns))
+
+	for _, col := range columns {
+		if col.index >= len(record) {
+			continue
+		}
+
+		value := strings.TrimSpace(record[col.index])
+		if value == "" {
+			continue
+		}
+
+		prop, err := imp.parseValue(col.prop, value)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", col.name, err)
+		}
+		props[col.name] = prop
+	}
+
+	return props, nil
+}
+
+func (r *RowResult) setErr(err error) {
+	r.Err = err
+	r.Error = err.Error()
+}
+
+func readCSV(r io.Reader) (header []string, rows [][]string, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("csvimport: failed to read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("csvimport: missing CSV header")
+	}
+
+	header = records[0]
+	if len(header) > 0 {
+		// Strip a UTF-8 byte order mark, as written by spreadsheet applications.
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	return header, records[1:], nil
+}
@@ -0,0 +1,296 @@
+package csvimport_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/csvimport"
+)
+
+type fakeClient struct {
+	mu       sync.Mutex
+	pages    []notion.CreatePageParams
+	database *notion.CreateDatabaseParams
+	fail     map[string]bool
+}
+
+func (c *fakeClient) CreatePage(_ context.Context, params notion.CreatePageParams) (notion.Page, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	title := notion.PlainText((*params.DatabasePageProperties)["Name"].Title)
+	if c.fail[title] {
+		return notion.Page{}, errors.New("notion: failed to create page")
+	}
+
+	c.pages = append(c.pages, params)
+
+	return notion.Page{ID: "page-" + title}, nil
+}
+
+func (c *fakeClient) CreateDatabase(_ context.Context, params notion.CreateDatabaseParams) (notion.Database, error) {
+	c.database = &params
+	return notion.Database{ID: "db", Properties: params.Properties}, nil
+}
+
+func testDatabase() notion.Database {
+	return notion.Database{
+		ID: "db",
+		Properties: notion.DatabaseProperties{
+			"Name":    {Type: notion.DBPropTypeTitle},
+			"Price":   {Type: notion.DBPropTypeNumber},
+			"Tags":    {Type: notion.DBPropTypeMultiSelect, MultiSelect: &notion.SelectMetadata{Options: []notion.SelectOptions{{Name: "A"}, {Name: "B"}}}},
+			"Status":  {Type: notion.DBPropTypeSelect, Select: &notion.SelectMetadata{Options: []notion.SelectOptions{{Name: "Open"}}}},
+			"Due":     {Type: notion.DBPropTypeDate},
+			"Done":    {Type: notion.DBPropTypeCheckbox},
+			"Link":    {Type: notion.DBPropTypeURL},
+			"Contact": {Type: notion.DBPropTypeEmail},
+			"Total":   {Type: notion.DBPropTypeFormula},
+		},
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	input := strings.Join([]string{
+		"Title,Price,Tags,Status,Due,Done,Link,Contact",
+		`Foo,"1,234.5","a, B",open,2021-05-23/2021-05-25,yes,https://example.com,foo@example.com`,
+		"Bar,abc,,,,,,",
+		"Baz,,C,,,,,",
+		"Qux,,,,2021-05-23T09:00,no,example.com,",
+		"Quux,,,,2021-05-23 09:00,no,,",
+	}, "\n")
+
+	client := &fakeClient{fail: map[string]bool{"Quux": true}}
+	imp := csvimport.New(client, csvimport.Options{
+		Columns:         map[string]string{"Title": "Name"},
+		RequestInterval: time.Millisecond,
+	})
+
+	report, err := imp.Import(context.Background(), strings.NewReader(input), testDatabase())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var results []string
+	for _, row := range report.Rows {
+		results = append(results, fmt.Sprintf("%v %v %v", row.Row, row.PageID, row.Error))
+	}
+
+	exp := []string{
+		"1 page-Foo ",
+		`2  column "Price": invalid number "abc"`,
+		`3  column "Tags": invalid option "C"`,
+		`4  column "Link": invalid URL "example.com"`,
+		"5  notion: failed to create page",
+	}
+	if diff := cmp.Diff(exp, results); diff != "" {
+		t.Fatalf("results not equal (-exp, +got):\n%v", diff)
+	}
+	if report.Created() != 1 || report.Err() == nil {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	got, err := json.Marshal(client.pages[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expJSON := `{"parent":{"database_id":"db"},"properties":{` +
+		`"Contact":{"email":"foo@example.com"},` +
+		`"Done":{"checkbox":true},` +
+		`"Due":{"date":{"start":"2021-05-23","end":"2021-05-25"}},` +
+		`"Link":{"url":"https://example.com"},` +
+		`"Name":{"title":[{"type":"text","text":{"content":"Foo"}}]},` +
+		`"Price":{"number":1234.5},` +
+		`"Status":{"select":{"name":"Open"}},` +
+		`"Tags":{"multi_select":[{"name":"A"},{"name":"B"}]}}}`
+	if diff := cmp.Diff(expJSON, string(got)); diff != "" {
+		t.Fatalf("page params not equal (-exp, +got):\n%v", diff)
+	}
+
+	t.Run("resume", func(t *testing.T) {
+		client := &fakeClient{}
+		imp := csvimport.New(client, csvimport.Options{
+			Columns:         map[string]string{"Title": "Name"},
+			AllowNewOptions: true,
+			RequestInterval: time.Millisecond,
+			Resume:          &report,
+		})
+
+		resumed, err := imp.Import(context.Background(), strings.NewReader(input), testDatabase())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !resumed.Rows[0].Skipped || resumed.Rows[0].PageID != "page-Foo" {
+			t.Fatalf("expected first row to be skipped, got: %+v", resumed.Rows[0])
+		}
+
+		var titles []string
+		for _, params := range client.pages {
+			titles = append(titles, notion.PlainText((*params.DatabasePageProperties)["Name"].Title))
+		}
+		sort.Strings(titles)
+
+		if diff := cmp.Diff([]string{"Baz", "Quux"}, titles); diff != "" {
+			t.Fatalf("created pages not equal (-exp, +got):\n%v", diff)
+		}
+	})
+}
+
+func TestImportInvalidColumns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  string
+		expErr error
+	}{
+		{
+			name:  "unknown column",
+			input: "Name,Foo\nbar,baz\n",
+		},
+		{
+			name:   "read-only column",
+			input:  "Name,Total\nbar,baz\n",
+			expErr: csvimport.ErrReadOnlyProperty,
+		},
+		{
+			name:  "empty",
+			input: "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			imp := csvimport.New(&fakeClient{}, csvimport.Options{})
+			_, err := imp.Import(context.Background(), strings.NewReader(tt.input), testDatabase())
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if tt.expErr != nil && !errors.Is(err, tt.expErr) {
+				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expErr, err)
+			}
+		})
+	}
+}
+
+func TestImportNew(t *testing.T) {
+	t.Parallel()
+
+	input := strings.Join([]string{
+		"Name,Count,Done,Due,Kind,Notes,Site,Empty",
+		"Foo,1,true,2021-05-23,A,Hello,https://example.com,",
+		"Bar,2.5,false,2021-05-24,A,\"Hello, world\",https://example.org,",
+		"Baz,,,,B,x,,",
+	}, "\n")
+
+	client := &fakeClient{}
+	imp := csvimport.New(client, csvimport.Options{RequestInterval: time.Millisecond})
+
+	db, report, err := imp.ImportNew(context.Background(), strings.NewReader(input), "parent", "Imported")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := report.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.ID != "db" || len(client.pages) != 3 {
+		t.Fatalf("unexpected result: %v, %v pages", db.ID, len(client.pages))
+	}
+
+	types := make(map[string]notion.DatabasePropertyType)
+	for name, prop := range client.database.Properties {
+		types[name] = prop.Type
+	}
+
+	exp := map[string]notion.DatabasePropertyType{
+		"Name":  notion.DBPropTypeTitle,
+		"Count": notion.DBPropTypeNumber,
+		"Done":  notion.DBPropTypeCheckbox,
+		"Due":   notion.DBPropTypeDate,
+		"Kind":  notion.DBPropTypeSelect,
+		"Notes": notion.DBPropTypeRichText,
+		"Site":  notion.DBPropTypeURL,
+		"Empty": notion.DBPropTypeRichText,
+	}
+	if diff := cmp.Diff(exp, types); diff != "" {
+		t.Fatalf("property types not equal (-exp, +got):\n%v", diff)
+	}
+
+	if diff := cmp.Diff([]notion.SelectOptions{{Name: "A"}, {Name: "B"}}, client.database.Properties["Kind"].Select.Options); diff != "" {
+		t.Fatalf("select options not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestImportNewColumns(t *testing.T) {
+	t.Parallel()
+
+	// Columns are mapped once, also if a mapped name is a header name too.
+	input := "Title,Name\nFoo,F1\n"
+
+	client := &fakeClient{}
+	imp := csvimport.New(client, csvimport.Options{
+		Columns:         map[string]string{"Title": "Name", "Name": "Code"},
+		RequestInterval: time.Millisecond,
+	})
+
+	_, report, err := imp.ImportNew(context.Background(), strings.NewReader(input), "parent", "Imported")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := report.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	props := *client.pages[0].DatabasePageProperties
+	if exp, got := "Foo", notion.PlainText(props["Name"].Title); exp != got {
+		t.Errorf("title not equal (expected: %q, got: %q)", exp, got)
+	}
+	if exp, got := "F1", notion.PlainText(props["Code"].RichText); exp != got {
+		t.Errorf("code not equal (expected: %q, got: %q)", exp, got)
+	}
+}
+
+func TestInferSchema(t *testing.T) {
+	t.Parallel()
+
+	imp := csvimport.New(nil, csvimport.Options{Columns: map[string]string{"Title": "Name"}})
+
+	props := imp.InferSchema(
+		[]string{"Title", "Due", "Amount", "Ratio", "Ranks"},
+		[][]string{
+			{"Foo", "2021-05-23 10:00", "1,234.5", "1,5", "1,2,3"},
+			{"Bar", "2021-05-24 10:00", "-12,345,678", "2", "4"},
+		},
+	)
+
+	types := make(map[string]notion.DatabasePropertyType)
+	for name, prop := range props {
+		types[name] = prop.Type
+	}
+
+	exp := map[string]notion.DatabasePropertyType{
+		"Name":   notion.DBPropTypeTitle,
+		"Due":    notion.DBPropTypeDate,
+		"Amount": notion.DBPropTypeNumber,
+		"Ratio":  notion.DBPropTypeRichText,
+		"Ranks":  notion.DBPropTypeRichText,
+	}
+	if diff := cmp.Diff(exp, types); diff != "" {
+		t.Fatalf("property types not equal (-exp, +got):\n%v", diff)
+	}
+}
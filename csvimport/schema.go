@@ -0,0 +1,113 @@
+package csvimport
+
+import (
+	"strings"
+
+	"github.com/skedida/go-notion"
+)
+
+// maxInferredOptions is the maximum number of distinct values of a column that
+// is inferred as select property.
+const maxInferredOptions = 25
+
+// InferSchema returns database properties for CSV columns, based on their
+// values. The first column becomes the title property. Other columns become
+// checkbox, number, date, URL, email or select properties if all their
+// (non-empty) values are of that kind, and rich text properties otherwise.
+// A column is inferred as select property if it has few distinct values, that
+// are repeated across rows. Properties are named as mapped by Options.Columns,
+// and dates are parsed as by an import, e.g. using Options.Location.
+func (imp *Importer) InferSchema(header []string, rows [][]string) notion.DatabaseProperties {
+	props := make(notion.DatabaseProperties, len(header))
+
+	for i, name := range header {
+		if mapped, ok := imp.opts.Columns[name]; ok {
+			name = mapped
+		}
+
+		if i == 0 {
+			props[name] = notion.DatabaseProperty{
+				Type:  notion.DBPropTypeTitle,
+				Title: &notion.EmptyMetadata{},
+			}
+			continue
+		}
+
+		var values []string
+		for _, row := range rows {
+			if i < len(row) && strings.TrimSpace(row[i]) != "" {
+				values = append(values, strings.TrimSpace(row[i]))
+			}
+		}
+
+		props[name] = imp.inferProperty(values)
+	}
+
+	return props
+}
+
+func (imp *Importer) inferProperty(values []string) notion.DatabaseProperty {
+	if len(values) == 0 {
+		return notion.DatabaseProperty{Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}}
+	}
+
+	all := func(fn func(string) bool) bool {
+		for _, v := range values {
+			if !fn(v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case all(isCheckboxWord):
+		return notion.DatabaseProperty{Type: notion.DBPropTypeCheckbox, Checkbox: &notion.EmptyMetadata{}}
+	case all(func(v string) bool { _, err := parseNumber(v); return err == nil }):
+		return notion.DatabaseProperty{
+			Type:   notion.DBPropTypeNumber,
+			Number: &notion.NumberMetadata{Format: notion.NumberFormatNumber},
+		}
+	case all(func(v string) bool { _, err := imp.parseDate(v); return err == nil }):
+		return notion.DatabaseProperty{Type: notion.DBPropTypeDate, Date: &notion.EmptyMetadata{}}
+	case all(isURL):
+		return notion.DatabaseProperty{Type: notion.DBPropTypeURL, URL: &notion.EmptyMetadata{}}
+	case all(isEmail):
+		return notion.DatabaseProperty{Type: notion.DBPropTypeEmail, Email: &notion.EmptyMetadata{}}
+	}
+
+	// Select option names can't contain commas.
+	if !all(func(v string) bool { return !strings.Contains(v, ",") }) {
+		return notion.DatabaseProperty{Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}}
+	}
+
+	var options []notion.SelectOptions
+	seen := make(map[string]bool)
+
+	for _, v := range values {
+		key := strings.ToLower(v)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		options = append(options, notion.SelectOptions{Name: v})
+	}
+
+	if len(options) <= maxInferredOptions && len(options) < len(values) {
+		return notion.DatabaseProperty{
+			Type:   notion.DBPropTypeSelect,
+			Select: &notion.SelectMetadata{Options: options},
+		}
+	}
+
+	return notion.DatabaseProperty{Type: notion.DBPropTypeRichText, RichText: &notion.EmptyMetadata{}}
+}
+
+// isCheckboxWord returns true for checkbox values that aren't numbers.
+func isCheckboxWord(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "false", "yes", "no", "checked", "unchecked":
+		return true
+	}
+	return false
+}
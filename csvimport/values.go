@@ -0,0 +1,280 @@
+package csvimport
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ErrReadOnlyProperty is used when a column is mapped to a property that can't
+// be set, e.g. a formula.
+var ErrReadOnlyProperty = errors.New("csvimport: property is read-only")
+
+// column is a CSV column mapped to a database property.
+type column struct {
+	index int
+	name  string
+	prop  notion.DatabaseProperty
+}
+
+// parseValue converts a (non-empty) CSV field to a page property value for a
+// database property.
+func (imp *Importer) parseValue(prop notion.DatabaseProperty, value string) (notion.DatabasePageProperty, error) {
+	switch prop.Type {
+	case notion.DBPropTypeTitle:
+		return notion.DatabasePageProperty{Title: notion.SplitRichText(value)}, nil
+	case notion.DBPropTypeRichText:
+		return notion.DatabasePageProperty{RichText: notion.SplitRichText(value)}, nil
+	case notion.DBPropTypeNumber:
+		n, err := parseNumber(value)
+		if err != nil {
+			return notion.DatabasePageProperty{}, err
+		}
+		return notion.DatabasePageProperty{Number: &n}, nil
+	case notion.DBPropTypeSelect:
+		option, err := imp.selectOption(prop.Select, value, true)
+		if err != nil {
+			return notion.DatabasePageProperty{}, err
+		}
+		return notion.DatabasePageProperty{Select: &option}, nil
+	case notion.DBPropTypeStatus:
+		var options []notion.SelectOptions
+		if prop.Status != nil {
+			options = prop.Status.Options
+		}
+		option, err := imp.selectOption(&notion.SelectMetadata{Options: options}, value, false)
+		if err != nil {
+			return notion.DatabasePageProperty{}, err
+		}
+		return notion.DatabasePageProperty{Status: &option}, nil
+	case notion.DBPropTypeMultiSelect:
+		var options []notion.SelectOptions
+		for _, v := range imp.split(value) {
+			option, err := imp.selectOption(prop.MultiSelect, v, true)
+			if err != nil {
+				return notion.DatabasePageProperty{}, err
+			}
+			options = append(options, option)
+		}
+		return notion.DatabasePageProperty{MultiSelect: options}, nil
+	case notion.DBPropTypeDate:
+		date, err := imp.parseDate(value)
+		if err != nil {
+			return notion.DatabasePageProperty{}, err
+		}
+		return notion.DatabasePageProperty{Date: &date}, nil
+	case notion.DBPropTypeCheckbox:
+		checked, ok := parseCheckbox(value)
+		if !ok {
+			return notion.DatabasePageProperty{}, fmt.Errorf("invalid checkbox value %q", value)
+		}
+		return notion.DatabasePageProperty{Checkbox: &checked}, nil
+	case notion.DBPropTypeURL:
+		if !isURL(value) {
+			return notion.DatabasePageProperty{}, fmt.Errorf("invalid URL %q", value)
+		}
+		return notion.DatabasePageProperty{URL: &value}, nil
+	case notion.DBPropTypeEmail:
+		if !isEmail(value) {
+			return notion.DatabasePageProperty{}, fmt.Errorf("invalid email address %q", value)
+		}
+		return notion.DatabasePageProperty{Email: &value}, nil
+	case notion.DBPropTypePhoneNumber:
+		return notion.DatabasePageProperty{PhoneNumber: &value}, nil
+	case notion.DBPropTypePeople:
+		var people []notion.User
+		for _, id := range imp.split(value) {
+			people = append(people, notion.User{BaseUser: notion.BaseUser{ID: id}})
+		}
+		return notion.DatabasePageProperty{People: people}, nil
+	case notion.DBPropTypeRelation:
+		var relations []notion.Relation
+		for _, id := range imp.split(value) {
+			relations = append(relations, notion.Relation{ID: id})
+		}
+		return notion.DatabasePageProperty{Relation: relations}, nil
+	case notion.DBPropTypeFiles:
+		var files []notion.File
+		for _, v := range imp.split(value) {
+			if !isURL(v) {
+				return notion.DatabasePageProperty{}, fmt.Errorf("invalid file URL %q", v)
+			}
+			files = append(files, notion.File{
+				Name:     fileName(v),
+				Type:     notion.FileTypeExternal,
+				External: &notion.FileExternal{URL: v},
+			})
+		}
+		return notion.DatabasePageProperty{Files: files}, nil
+	}
+
+	return notion.DatabasePageProperty{}, fmt.Errorf("%w: %v", ErrReadOnlyProperty, prop.Type)
+}
+
+// writable returns true if values can be set for a property type.
+func writable(propType notion.DatabasePropertyType) bool {
+	switch propType {
+	case notion.DBPropTypeFormula,
+		notion.DBPropTypeRollup,
+		notion.DBPropTypeCreatedTime,
+		notion.DBPropTypeCreatedBy,
+		notion.DBPropTypeLastEditedTime,
+		notion.DBPropTypeLastEditedBy:
+		return false
+	}
+	return true
+}
+
+func (imp *Importer) selectOption(metadata *notion.SelectMetadata, value string, allowNew bool) (notion.SelectOptions, error) {
+	if metadata != nil {
+		for _, option := range metadata.Options {
+			if strings.EqualFold(option.Name, value) {
+				return notion.SelectOptions{Name: option.Name}, nil
+			}
+		}
+	}
+
+	if allowNew && imp.opts.AllowNewOptions {
+		return notion.SelectOptions{Name: value}, nil
+	}
+
+	return notion.SelectOptions{}, fmt.Errorf("invalid option %q", value)
+}
+
+// parseDate parses an ISO 8601 date, date and time, or an interval of those,
+// e.g. `2021-05-23/2021-05-25`.
+func (imp *Importer) parseDate(value string) (notion.Date, error) {
+	startValue, endValue, isRange := strings.Cut(value, "/")
+
+	start, err := imp.parseDateTime(startValue)
+	if err != nil {
+		return notion.Date{}, err
+	}
+
+	date := notion.Date{Start: start}
+
+	if isRange {
+		end, err := imp.parseDateTime(endValue)
+		if err != nil {
+			return notion.Date{}, err
+		}
+		date.End = &end
+	}
+
+	return date, nil
+}
+
+func (imp *Importer) parseDateTime(value string) (notion.DateTime, error) {
+	value = strings.TrimSpace(value)
+
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return notion.NewDateTime(t, false), nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, imp.opts.Location); err == nil {
+			return notion.NewDateTime(t, true), nil
+		}
+	}
+
+	return notion.DateTime{}, fmt.Errorf("invalid date %q", value)
+}
+
+func (imp *Importer) split(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, imp.opts.Separator) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseNumber parses a number with optional thousands separators, e.g.
+// `1,234.5`. Commas are only accepted between groups of three digits, so that
+// decimal commas (`1,5`) and lists (`1,2,3`) aren't read as other numbers.
+func parseNumber(value string) (float64, error) {
+	s := strings.TrimSpace(value)
+	if strings.Contains(s, ",") {
+		if !hasThousandsSeparators(s) {
+			return 0, fmt.Errorf("invalid number %q", value)
+		}
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	return n, nil
+}
+
+// hasThousandsSeparators returns true if the commas of a number only separate
+// groups of three digits of its integer part.
+func hasThousandsSeparators(s string) bool {
+	integer := strings.TrimLeft(s, "+-")
+	if i := strings.IndexByte(integer, '.'); i >= 0 {
+		if strings.Contains(integer[i:], ",") {
+			return false
+		}
+		integer = integer[:i]
+	}
+
+	for i, group := range strings.Split(integer, ",") {
+		if len(group) != 3 && (i > 0 || len(group) == 0 || len(group) > 3) {
+			return false
+		}
+		for _, r := range group {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func parseCheckbox(value string) (checked bool, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "x", "1", "checked":
+		return true, true
+	case "false", "no", "n", "0", "unchecked":
+		return false, true
+	}
+	return false, false
+}
+
+func isURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func isEmail(value string) bool {
+	addr, err := mail.ParseAddress(value)
+	return err == nil && addr.Address == value
+}
+
+func fileName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if i := strings.LastIndexByte(u.Path, '/'); i >= 0 && i < len(u.Path)-1 {
+		return u.Path[i+1:]
+	}
+	return u.Host
+}
@@ -1,6 +1,7 @@
 package notion
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"time"
@@ -132,8 +133,9 @@ type File struct {
 	Name string   `json:"name"`
 	Type FileType `json:"type"`
 
-	File     *FileFile     `json:"file,omitempty"`
-	External *FileExternal `json:"external,omitempty"`
+	File       *FileFile       `json:"file,omitempty"`
+	External   *FileExternal   `json:"external,omitempty"`
+	FileUpload *FileUploadFile `json:"file_upload,omitempty"`
 }
 
 type DatabaseProperty struct {
@@ -179,6 +181,68 @@ type DatabaseQueryResponse struct {
 	NextCursor *string `json:"next_cursor"`
 }
 
+// DatabaseQueryIterator iterates over the pages of a database query.
+// See: Client.QueryDatabaseIterator.
+//
+//	it := client.QueryDatabaseIterator(dbID, nil)
+//	for it.Next(ctx) {
+//		page := it.Page()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type DatabaseQueryIterator struct {
+	client     *Client
+	databaseID string
+	query      DatabaseQuery
+
+	pages []Page
+	page  Page
+	done  bool
+	err   error
+}
+
+// Next advances the iterator to the next page, which is then available via
+// Page. It returns false when there are no more pages or an error occurred,
+// which is available via Err.
+func (it *DatabaseQueryIterator) Next(ctx context.Context) bool {
+	for len(it.pages) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		resp, err := it.client.QueryDatabase(ctx, it.databaseID, &it.query)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.pages = resp.Results
+
+		if !resp.HasMore || resp.NextCursor == nil {
+			it.done = true
+		} else {
+			it.query.StartCursor = *resp.NextCursor
+		}
+	}
+
+	it.page = it.pages[0]
+	it.pages = it.pages[1:]
+
+	return true
+}
+
+// Page returns the current page.
+func (it *DatabaseQueryIterator) Page() Page {
+	return it.page
+}
+
+// Err returns the first error that occurred while iterating, if any.
+func (it *DatabaseQueryIterator) Err() error {
+	return it.err
+}
+
 // DatabaseQueryFilter is used to filter database contents.
 // See: https://developers.notion.com/reference/post-database-query#post-database-query-filter
 type DatabaseQueryFilter struct {
@@ -430,6 +494,21 @@ const (
 	SortDirDesc SortDirection = "descending"
 )
 
+// known returns true if the property type is mapped by this library.
+func (t DatabasePropertyType) known() bool {
+	switch t {
+	case DBPropTypeTitle, DBPropTypeRichText, DBPropTypeNumber, DBPropTypeSelect,
+		DBPropTypeMultiSelect, DBPropTypeDate, DBPropTypePeople, DBPropTypeFiles,
+		DBPropTypeCheckbox, DBPropTypeURL, DBPropTypeEmail, DBPropTypePhoneNumber,
+		DBPropTypeStatus, DBPropTypeFormula, DBPropTypeRelation, DBPropTypeRollup,
+		DBPropTypeCreatedTime, DBPropTypeCreatedBy, DBPropTypeLastEditedTime,
+		DBPropTypeLastEditedBy:
+		return true
+	default:
+		return false
+	}
+}
+
 // Metadata returns the underlying property metadata, based on its `type` field.
 // When type is unknown/unmapped or doesn't have additional properies, `nil` is returned.
 func (prop DatabaseProperty) Metadata() interface{} {
@@ -0,0 +1,590 @@
+// Package dateparse parses natural language and relative date expressions
+// (e.g. `next friday 3pm`, `in 2 weeks`, `jan 3 - jan 7`) into notion.Date
+// values, for use in page properties and database query filters.
+//
+// Supported expressions, case insensitive:
+//
+//   - `now`, `today`, `tomorrow`, `yesterday`
+//   - `in 2 weeks`, `3 days ago`, `an hour from now` (units: minutes, hours,
+//     days, weeks, months and years)
+//   - `friday` (on or after today), `this friday` (in the current week),
+//     `next friday` (in the next week), `last friday` (before today)
+//   - `this week`, `next month`, `last year` (all-day ranges)
+//   - `2021-01-03`, `2021-01-03T15:04:05Z` and ISO weeks: `2021-W05`,
+//     `2021-W05-3`, `week 5`, `w5`
+//   - `jan 3`, `3 jan`, `january 3rd, 2021`, `jan 2021` (month range)
+//   - times: `3pm`, `3:30 pm`, `15:00`, `noon`, `midnight`, optionally
+//     preceded by `at`, combined with a date or on their own (today)
+//   - ranges of the above, separated by ` - `, ` to `, ` until ` or
+//     ` through `, e.g. `friday 3pm - 5pm`
+//
+// Weeks start on Monday.
+package dateparse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+// ErrUnrecognized is returned when a date expression can't be parsed.
+var ErrUnrecognized = errors.New("dateparse: unrecognized date expression")
+
+var rangeSeparators = []string{" - ", " – ", " to ", " until ", " through "}
+
+var (
+	isoDateRegexp  = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(t[\d:.]+(z|[+-]\d{2}:\d{2})?)?$`)
+	isoWeekRegexp  = regexp.MustCompile(`^(\d{4})-?w(\d{1,2})(?:-?([1-7]))?$`)
+	weekRegexp     = regexp.MustCompile(`^(?:week |w)(\d{1,2})(?: (\d{4}))?$`)
+	relativeRegexp = regexp.MustCompile(`^(?:in )?(\d+|an?) ([a-z]+?)s?( ago| from now)?$`)
+	clockRegexp    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	ordinalRegexp  = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+)
+
+var isoLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+}
+
+var weekdays = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+// span is a parsed expression. For all-day ranges (e.g. weeks), end is the
+// last day of the range.
+type span struct {
+	start    time.Time
+	end      *time.Time
+	hasTime  bool
+	timeOnly bool
+	yearless bool
+}
+
+type clock struct {
+	hour, min int
+}
+
+type parser struct {
+	ref    time.Time
+	anchor time.Time
+	loc    *time.Location
+}
+
+// Parse parses a date expression relative to ref, interpreting dates and times
+// in loc. If loc is nil, the location of ref is used.
+//
+// The returned date has time if the expression includes a time of day (or a
+// relative amount of minutes or hours); otherwise it's an all-day date (range).
+// Dates with time have their TimeZone set to loc, unless loc is UTC or Local.
+func Parse(value string, ref time.Time, loc *time.Location) (notion.Date, error) {
+	if loc == nil {
+		loc = ref.Location()
+	}
+
+	ref = ref.In(loc)
+	p := parser{ref: ref, anchor: ref, loc: loc}
+
+	input := normalize(value)
+	if input == "" {
+		return notion.Date{}, fmt.Errorf("%w: %q", ErrUnrecognized, value)
+	}
+
+	startExpr, endExpr, isRange := splitRange(input)
+
+	start, err := p.parse(startExpr)
+	if err != nil {
+		return notion.Date{}, fmt.Errorf("%w: %q", err, value)
+	}
+
+	date := notion.Date{
+		Start: notion.NewDateTime(start.start, start.hasTime),
+	}
+
+	if !isRange {
+		if start.end != nil {
+			end := notion.NewDateTime(*start.end, false)
+			date.End = &end
+		}
+		return withTimeZone(date, loc), nil
+	}
+
+	p.anchor = start.start
+	end, err := p.parse(endExpr)
+	if err != nil {
+		return notion.Date{}, fmt.Errorf("%w: %q", err, value)
+	}
+
+	endTime := end.start
+	if end.end != nil {
+		endTime = *end.end
+	}
+
+	switch {
+	case end.timeOnly:
+		if !start.hasTime {
+			return notion.Date{}, fmt.Errorf("%w: range mixes dates with and without time: %q", ErrUnrecognized, value)
+		}
+		endTime = time.Date(start.start.Year(), start.start.Month(), start.start.Day(),
+			end.start.Hour(), end.start.Minute(), 0, 0, loc)
+	case start.hasTime != end.hasTime:
+		return notion.Date{}, fmt.Errorf("%w: range mixes dates with and without time: %q", ErrUnrecognized, value)
+	case end.yearless && endTime.Before(start.start):
+		endTime = endTime.AddDate(1, 0, 0)
+	}
+
+	if endTime.Before(start.start) {
+		return notion.Date{}, fmt.Errorf("%w: range ends before it starts: %q", ErrUnrecognized, value)
+	}
+
+	endDT := notion.NewDateTime(endTime, start.hasTime)
+	date.End = &endDT
+
+	return withTimeZone(date, loc), nil
+}
+
+// Filter returns a database query filter for a date property, matching values
+// within the bounds of date (see notion.Date.Bounds), with all-day dates
+// interpreted in loc. A date with time and without end matches that instant.
+func Filter(property string, date notion.Date, loc *time.Location) notion.DatabaseQueryFilter {
+	start, end := date.Bounds(loc)
+
+	if start.Equal(end) {
+		return notion.DatabaseQueryFilter{
+			Property: property,
+			DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{
+				Date: &notion.DatePropertyFilter{
+					Equals: &start,
+				},
+			},
+		}
+	}
+
+	return notion.DatabaseQueryFilter{
+		And: []notion.DatabaseQueryFilter{
+			{
+				Property: property,
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{
+					Date: &notion.DatePropertyFilter{
+						OnOrAfter: &start,
+					},
+				},
+			},
+			{
+				Property: property,
+				DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{
+					Date: &notion.DatePropertyFilter{
+						Before: &end,
+					},
+				},
+			},
+		},
+	}
+}
+
+func withTimeZone(date notion.Date, loc *time.Location) notion.Date {
+	if !date.HasTime() || loc == time.UTC || loc == time.Local {
+		return date
+	}
+
+	timeZone := loc.String()
+	date.TimeZone = &timeZone
+
+	return date
+}
+
+func normalize(value string) string {
+	value = strings.ToLower(value)
+	value = strings.ReplaceAll(value, ",", " ")
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func splitRange(input string) (start, end string, ok bool) {
+	// Separators require surrounding whitespace, as a plain hyphen is ambiguous
+	// with ISO dates.
+	for _, sep := range rangeSeparators {
+		if i := strings.Index(input, sep); i > 0 {
+			return input[:i], input[i+len(sep):], true
+		}
+	}
+	return input, "", false
+}
+
+func (p parser) parse(expr string) (span, error) {
+	dateExpr, c, err := extractClock(expr)
+	if err != nil {
+		return span{}, err
+	}
+
+	if dateExpr == "" {
+		if c == nil {
+			return span{}, ErrUnrecognized
+		}
+		return span{
+			start:    p.at(p.ref, *c),
+			hasTime:  true,
+			timeOnly: true,
+		}, nil
+	}
+
+	s, err := p.parseDate(dateExpr)
+	if err != nil {
+		return span{}, err
+	}
+
+	if c == nil {
+		return s, nil
+	}
+	if s.hasTime || s.end != nil {
+		return span{}, ErrUnrecognized
+	}
+
+	s.start = p.at(s.start, *c)
+	s.hasTime = true
+
+	return s, nil
+}
+
+// extractClock removes a time of day from the end of expr.
+func extractClock(expr string) (string, *clock, error) {
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return "", nil, nil
+	}
+
+	last := fields[len(fields)-1]
+	n := 1
+
+	switch last {
+	case "noon":
+		return trimClock(fields, n), &clock{hour: 12}, nil
+	case "midnight":
+		return trimClock(fields, n), &clock{}, nil
+	case "am", "pm":
+		if len(fields) < 2 {
+			return "", nil, ErrUnrecognized
+		}
+		last = fields[len(fields)-2] + last
+		n = 2
+	}
+
+	m := clockRegexp.FindStringSubmatch(last)
+	if m == nil {
+		return expr, nil, nil
+	}
+
+	// A bare number (e.g. `jan 3`) is only a time of day when preceded by `at`.
+	hasAt := len(fields) > n && fields[len(fields)-n-1] == "at"
+	if m[2] == "" && m[3] == "" && !hasAt {
+		return expr, nil, nil
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	min := 0
+	if m[2] != "" {
+		min, _ = strconv.Atoi(m[2])
+	}
+
+	switch m[3] {
+	case "am":
+		if hour < 1 || hour > 12 {
+			return "", nil, ErrUnrecognized
+		}
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 1 || hour > 12 {
+			return "", nil, ErrUnrecognized
+		}
+		if hour != 12 {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || min > 59 {
+		return "", nil, ErrUnrecognized
+	}
+
+	return trimClock(fields, n), &clock{hour: hour, min: min}, nil
+}
+
+func trimClock(fields []string, n int) string {
+	fields = fields[:len(fields)-n]
+	if len(fields) > 0 && fields[len(fields)-1] == "at" {
+		fields = fields[:len(fields)-1]
+	}
+	return strings.Join(fields, " ")
+}
+
+func (p parser) parseDate(expr string) (span, error) {
+	today := p.day(p.ref)
+
+	switch expr {
+	case "now":
+		return span{start: p.ref, hasTime: true}, nil
+	case "today":
+		return span{start: today}, nil
+	case "tomorrow":
+		return span{start: today.AddDate(0, 0, 1)}, nil
+	case "yesterday":
+		return span{start: today.AddDate(0, 0, -1)}, nil
+	}
+
+	if weekday, ok := weekdays[expr]; ok {
+		anchor := p.day(p.anchor)
+		return span{start: anchor.AddDate(0, 0, daysUntil(anchor.Weekday(), weekday))}, nil
+	}
+
+	if fields := strings.Fields(expr); len(fields) == 2 {
+		if s, ok := p.parseModified(fields[0], fields[1]); ok {
+			return s, nil
+		}
+	}
+
+	if isoDateRegexp.MatchString(expr) {
+		return p.parseISO(strings.ToUpper(expr))
+	}
+
+	if m := isoWeekRegexp.FindStringSubmatch(expr); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		return p.isoWeek(year, week, m[3])
+	}
+
+	if m := weekRegexp.FindStringSubmatch(expr); m != nil {
+		year, _ := p.ref.ISOWeek()
+		if m[2] != "" {
+			year, _ = strconv.Atoi(m[2])
+		}
+		week, _ := strconv.Atoi(m[1])
+		return p.isoWeek(year, week, "")
+	}
+
+	if m := relativeRegexp.FindStringSubmatch(expr); m != nil {
+		if s, ok := p.parseRelative(m[1], m[2], m[3], strings.HasPrefix(expr, "in ")); ok {
+			return s, nil
+		}
+	}
+
+	return p.parseMonthDay(strings.Fields(expr))
+}
+
+// parseModified parses `this`, `next` and `last` followed by a weekday or a
+// week, month or year.
+func (p parser) parseModified(modifier, unit string) (span, bool) {
+	var offset int
+
+	switch modifier {
+	case "this":
+		offset = 0
+	case "next":
+		offset = 1
+	case "last":
+		offset = -1
+	default:
+		return span{}, false
+	}
+
+	today := p.day(p.ref)
+	monday := today.AddDate(0, 0, -daysSince(time.Monday, today.Weekday()))
+
+	if weekday, ok := weekdays[unit]; ok {
+		if modifier == "last" {
+			days := daysSince(weekday, today.Weekday())
+			if days == 0 {
+				days = 7
+			}
+			return span{start: today.AddDate(0, 0, -days)}, true
+		}
+		return span{start: monday.AddDate(0, 0, 7*offset+daysUntil(time.Monday, weekday))}, true
+	}
+
+	var start, end time.Time
+
+	switch unit {
+	case "week":
+		start = monday.AddDate(0, 0, 7*offset)
+		end = start.AddDate(0, 0, 6)
+	case "month":
+		start = time.Date(today.Year(), today.Month()+time.Month(offset), 1, 0, 0, 0, 0, p.loc)
+		end = start.AddDate(0, 1, -1)
+	case "year":
+		start = time.Date(today.Year()+offset, time.January, 1, 0, 0, 0, 0, p.loc)
+		end = start.AddDate(1, 0, -1)
+	default:
+		return span{}, false
+	}
+
+	return span{start: start, end: &end}, true
+}
+
+func (p parser) parseRelative(amount, unit, direction string, hasIn bool) (span, bool) {
+	if hasIn == (direction != "") {
+		return span{}, false
+	}
+
+	n := 1
+	if amount != "a" && amount != "an" {
+		n, _ = strconv.Atoi(amount)
+	}
+	if direction == " ago" {
+		n = -n
+	}
+
+	today := p.day(p.ref)
+
+	switch unit {
+	case "minute", "min":
+		return span{start: p.ref.Add(time.Duration(n) * time.Minute), hasTime: true}, true
+	case "hour", "hr":
+		return span{start: p.ref.Add(time.Duration(n) * time.Hour), hasTime: true}, true
+	case "day":
+		return span{start: today.AddDate(0, 0, n)}, true
+	case "week":
+		return span{start: today.AddDate(0, 0, 7*n)}, true
+	case "month":
+		return span{start: today.AddDate(0, n, 0)}, true
+	case "year":
+		return span{start: today.AddDate(n, 0, 0)}, true
+	}
+
+	return span{}, false
+}
+
+// parseISO parses an ISO 8601 date, or date and time with optional UTC offset.
+// Times without offset are interpreted in the parser's location.
+func (p parser) parseISO(value string) (span, error) {
+	if len(value) == len("2006-01-02") {
+		t, err := time.ParseInLocation("2006-01-02", value, p.loc)
+		if err != nil {
+			return span{}, ErrUnrecognized
+		}
+		return span{start: t}, nil
+	}
+
+	for _, layout := range isoLayouts {
+		if t, err := time.ParseInLocation(layout, value, p.loc); err == nil {
+			return span{start: t, hasTime: true}, nil
+		}
+	}
+
+	return span{}, ErrUnrecognized
+}
+
+// parseMonthDay parses `jan 3`, `3 jan` and `jan 2021`, with optional year.
+func (p parser) parseMonthDay(fields []string) (span, error) {
+	if len(fields) < 2 || len(fields) > 3 {
+		return span{}, ErrUnrecognized
+	}
+
+	month, ok := months[fields[0]]
+	dayField := fields[1]
+	if !ok {
+		month, ok = months[fields[1]]
+		dayField = fields[0]
+	}
+	if !ok {
+		return span{}, ErrUnrecognized
+	}
+
+	// Month and year, e.g. `jan 2021`.
+	if len(fields) == 2 && len(dayField) == 4 {
+		year, err := strconv.Atoi(dayField)
+		if err != nil {
+			return span{}, ErrUnrecognized
+		}
+		start := time.Date(year, month, 1, 0, 0, 0, 0, p.loc)
+		end := start.AddDate(0, 1, -1)
+		return span{start: start, end: &end}, nil
+	}
+
+	m := ordinalRegexp.FindStringSubmatch(dayField)
+	if m == nil {
+		return span{}, ErrUnrecognized
+	}
+	day, _ := strconv.Atoi(m[1])
+
+	year := p.anchor.Year()
+	yearless := true
+	if len(fields) == 3 {
+		var err error
+		if year, err = strconv.Atoi(fields[2]); err != nil {
+			return span{}, ErrUnrecognized
+		}
+		yearless = false
+	}
+
+	start := time.Date(year, month, day, 0, 0, 0, 0, p.loc)
+	if start.Month() != month {
+		return span{}, ErrUnrecognized
+	}
+
+	return span{start: start, yearless: yearless}, nil
+}
+
+func (p parser) isoWeek(year, week int, weekday string) (span, error) {
+	// January 4th is always in ISO week 1.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, p.loc)
+	start := jan4.AddDate(0, 0, -daysSince(time.Monday, jan4.Weekday())+7*(week-1))
+
+	if y, w := start.ISOWeek(); week < 1 || y != year || w != week {
+		return span{}, ErrUnrecognized
+	}
+
+	if weekday != "" {
+		n, _ := strconv.Atoi(weekday)
+		return span{start: start.AddDate(0, 0, n-1)}, nil
+	}
+
+	end := start.AddDate(0, 0, 6)
+
+	return span{start: start, end: &end}, nil
+}
+
+func (p parser) day(t time.Time) time.Time {
+	t = t.In(p.loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, p.loc)
+}
+
+func (p parser) at(day time.Time, c clock) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), c.hour, c.min, 0, 0, p.loc)
+}
+
+// daysUntil returns the number of days from `from` until the next `to`,
+// including `from` itself (0).
+func daysUntil(from, to time.Weekday) int {
+	return (int(to) - int(from) + 7) % 7
+}
+
+// daysSince returns the number of days since the last `since` before or on `day`.
+func daysSince(since, day time.Weekday) int {
+	return (int(day) - int(since) + 7) % 7
+}
@@ -0,0 +1,146 @@
+package dateparse_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion/dateparse"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Wednesday.
+	ref := time.Date(2021, time.May, 19, 10, 30, 0, 0, loc)
+
+	tests := []struct {
+		value   string
+		expJSON string
+		expErr  bool
+	}{
+		{value: "today", expJSON: `{"start":"2021-05-19"}`},
+		{value: "Tomorrow", expJSON: `{"start":"2021-05-20"}`},
+		{value: "yesterday", expJSON: `{"start":"2021-05-18"}`},
+		{value: "now", expJSON: `{"start":"2021-05-19T10:30:00","time_zone":"Europe/Amsterdam"}`},
+		{value: "in 2 weeks", expJSON: `{"start":"2021-06-02"}`},
+		{value: "3 days ago", expJSON: `{"start":"2021-05-16"}`},
+		{value: "an hour from now", expJSON: `{"start":"2021-05-19T11:30:00","time_zone":"Europe/Amsterdam"}`},
+		{value: "in 1 month", expJSON: `{"start":"2021-06-19"}`},
+		{value: "friday", expJSON: `{"start":"2021-05-21"}`},
+		{value: "wednesday", expJSON: `{"start":"2021-05-19"}`},
+		{value: "this monday", expJSON: `{"start":"2021-05-17"}`},
+		{value: "next friday 3pm", expJSON: `{"start":"2021-05-28T15:00:00","time_zone":"Europe/Amsterdam"}`},
+		{value: "last wednesday", expJSON: `{"start":"2021-05-12"}`},
+		{value: "tomorrow at 9:30 am", expJSON: `{"start":"2021-05-20T09:30:00","time_zone":"Europe/Amsterdam"}`},
+		{value: "noon", expJSON: `{"start":"2021-05-19T12:00:00","time_zone":"Europe/Amsterdam"}`},
+		{value: "at 15:00", expJSON: `{"start":"2021-05-19T15:00:00","time_zone":"Europe/Amsterdam"}`},
+		{value: "this week", expJSON: `{"start":"2021-05-17","end":"2021-05-23"}`},
+		{value: "next month", expJSON: `{"start":"2021-06-01","end":"2021-06-30"}`},
+		{value: "last year", expJSON: `{"start":"2020-01-01","end":"2020-12-31"}`},
+		{value: "2021-06-01", expJSON: `{"start":"2021-06-01"}`},
+		{value: "2021-06-01T08:00:00Z", expJSON: `{"start":"2021-06-01T10:00:00","time_zone":"Europe/Amsterdam"}`},
+		{value: "2021-06-01T08:00", expJSON: `{"start":"2021-06-01T08:00:00","time_zone":"Europe/Amsterdam"}`},
+		{value: "2021-W01", expJSON: `{"start":"2021-01-04","end":"2021-01-10"}`},
+		{value: "2020-W53-5", expJSON: `{"start":"2021-01-01"}`},
+		{value: "week 20", expJSON: `{"start":"2021-05-17","end":"2021-05-23"}`},
+		{value: "jan 3", expJSON: `{"start":"2021-01-03"}`},
+		{value: "3rd January, 2022", expJSON: `{"start":"2022-01-03"}`},
+		{value: "feb 2021", expJSON: `{"start":"2021-02-01","end":"2021-02-28"}`},
+		{value: "jan 3 - jan 7", expJSON: `{"start":"2021-01-03","end":"2021-01-07"}`},
+		{value: "dec 30 to jan 2", expJSON: `{"start":"2021-12-30","end":"2022-01-02"}`},
+		{value: "friday 3pm - 5pm", expJSON: `{"start":"2021-05-21T15:00:00","end":"2021-05-21T17:00:00","time_zone":"Europe/Amsterdam"}`},
+		{value: "sat - mon", expJSON: `{"start":"2021-05-22","end":"2021-05-24"}`},
+		{value: "this week until next week", expJSON: `{"start":"2021-05-17","end":"2021-05-30"}`},
+		{value: "", expErr: true},
+		{value: "someday", expErr: true},
+		{value: "feb 30", expErr: true},
+		{value: "13pm", expErr: true},
+		{value: "2021-W60", expErr: true},
+		{value: "friday 3pm - saturday", expErr: true},
+		{value: "jan 7 2021 - jan 3 2021", expErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			date, err := dateparse.Parse(tt.value, ref, loc)
+			if tt.expErr {
+				if !errors.Is(err, dateparse.ErrUnrecognized) {
+					t.Fatalf("expected ErrUnrecognized, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := json.Marshal(date)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.expJSON, string(got)); diff != "" {
+				t.Fatalf("date not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ref := time.Date(2021, time.May, 19, 10, 30, 0, 0, loc)
+
+	tests := []struct {
+		name    string
+		value   string
+		expJSON string
+	}{
+		{
+			name:    "all-day range",
+			value:   "this week",
+			expJSON: `{"and":[{"property":"Due","date":{"on_or_after":"2021-05-17T00:00:00+02:00"}},{"property":"Due","date":{"before":"2021-05-24T00:00:00+02:00"}}]}`,
+		},
+		{
+			name:    "instant",
+			value:   "tomorrow 9am",
+			expJSON: `{"property":"Due","date":{"equals":"2021-05-20T09:00:00+02:00"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			date, err := dateparse.Parse(tt.value, ref, loc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := json.Marshal(dateparse.Filter("Due", date, loc))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.expJSON, string(got)); diff != "" {
+				t.Fatalf("filter not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
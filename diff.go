@@ -0,0 +1,725 @@
+package notion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChangeType is the kind of change of a property or block in a diff.
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// PageDiff is the difference between two versions of a page.
+// See: DiffPages.
+type PageDiff struct {
+	Properties []PropertyDiff `json:"properties,omitempty"`
+}
+
+// PropertyDiff is a changed property of a page.
+type PropertyDiff struct {
+	Name   string               `json:"name"`
+	Type   DatabasePropertyType `json:"type"`
+	Change ChangeType           `json:"change"`
+
+	// Old is nil for added properties, New for removed ones.
+	Old *DatabasePageProperty `json:"old,omitempty"`
+	New *DatabasePageProperty `json:"new,omitempty"`
+
+	// Added and Removed are the added and removed options of multi-select
+	// properties (by name), and the added and removed pages of relation and
+	// users of people properties (by ID).
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// DiffPages returns the properties of which the values differ between two
+// versions of a page, sorted by name. Values are compared by type: rich text
+// by plain text and annotations, multi-select options, relations and people as
+// sets, dates using `DateTime.Equal`, and files by name and URL, ignoring the
+// signature of Notion hosted file URLs, which changes with each request.
+// Pages that aren't in a database have one property, "title".
+func DiffPages(old, new Page) PageDiff {
+	oldProps, newProps := diffProperties(old), diffProperties(new)
+
+	names := make([]string, 0, len(newProps))
+	for name := range newProps {
+		names = append(names, name)
+	}
+	for name := range oldProps {
+		if _, ok := newProps[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diff PageDiff
+
+	for _, name := range names {
+		oldProp, oldOK := oldProps[name]
+		newProp, newOK := newProps[name]
+
+		switch {
+		case !oldOK:
+			diff.Properties = append(diff.Properties, PropertyDiff{
+				Name:   name,
+				Type:   newProp.Type,
+				Change: ChangeAdded,
+				New:    &newProp,
+			})
+		case !newOK:
+			diff.Properties = append(diff.Properties, PropertyDiff{
+				Name:   name,
+				Type:   oldProp.Type,
+				Change: ChangeRemoved,
+				Old:    &oldProp,
+			})
+		case !equalProperty(oldProp, newProp):
+			propDiff := PropertyDiff{
+				Name:   name,
+				Type:   newProp.Type,
+				Change: ChangeModified,
+				Old:    &oldProp,
+				New:    &newProp,
+			}
+			if oldProp.Type == newProp.Type {
+				propDiff.Added, propDiff.Removed = diffSets(propertySet(oldProp), propertySet(newProp))
+			}
+			diff.Properties = append(diff.Properties, propDiff)
+		}
+	}
+
+	return diff
+}
+
+// Empty returns true if the diff has no changes.
+func (d PageDiff) Empty() bool {
+	return len(d.Properties) == 0
+}
+
+// String returns the diff in a human-readable form, one line per property.
+func (d PageDiff) String() string {
+	var sb strings.Builder
+
+	for _, p := range d.Properties {
+		sb.WriteString(p.String())
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// String returns the change in a human-readable form, e.g.
+// `~ Status: "Todo" -> "Done"`.
+func (p PropertyDiff) String() string {
+	switch p.Change {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %v: %q", p.Name, propertyText(*p.New))
+	case ChangeRemoved:
+		return fmt.Sprintf("- %v: %q", p.Name, propertyText(*p.Old))
+	}
+
+	if len(p.Added) > 0 || len(p.Removed) > 0 {
+		var changes []string
+		for _, v := range p.Added {
+			changes = append(changes, "+"+strconv.Quote(v))
+		}
+		for _, v := range p.Removed {
+			changes = append(changes, "-"+strconv.Quote(v))
+		}
+		return fmt.Sprintf("~ %v: %v", p.Name, strings.Join(changes, " "))
+	}
+
+	return fmt.Sprintf("~ %v: %q -> %q", p.Name, propertyText(*p.Old), propertyText(*p.New))
+}
+
+// BlockDiff is the difference between two versions of a block tree.
+// See: DiffBlocks.
+type BlockDiff struct {
+	Blocks []BlockChange `json:"blocks,omitempty"`
+}
+
+// BlockChange is an added, removed or modified block.
+type BlockChange struct {
+	Change ChangeType `json:"change"`
+	ID     string     `json:"id,omitempty"`
+	Type   BlockType  `json:"type"`
+	// Path is the position of the block in the new tree, or in the old tree for
+	// removed blocks, as in WalkInfo.
+	Path []int `json:"path"`
+
+	// Old is nil for added blocks, New for removed ones.
+	Old Block `json:"old,omitempty"`
+	New Block `json:"new,omitempty"`
+}
+
+// DiffBlocks returns the blocks that were added, removed or modified between
+// two versions of a block tree, in depth-first order. Blocks are matched by ID,
+// or by content for blocks without ID (e.g. blocks built for a request), in
+// which case a modified block is reported as removed and added. Children of
+// matched blocks are compared if they are set, e.g. by FindBlockChildrenByID
+// calls for blocks that have children; a modified block is one of which the
+// content, apart from its children, changed. Children of added and removed
+// blocks aren't reported separately.
+func DiffBlocks(old, new []Block) BlockDiff {
+	var diff BlockDiff
+	diffBlocks(&diff, old, new, nil)
+	return diff
+}
+
+// Empty returns true if the diff has no changes.
+func (d BlockDiff) Empty() bool {
+	return len(d.Blocks) == 0
+}
+
+// String returns the diff in a human-readable form, one line per block.
+func (d BlockDiff) String() string {
+	var sb strings.Builder
+
+	for _, b := range d.Blocks {
+		sb.WriteString(b.String())
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// String returns the change in a human-readable form, e.g.
+// `~ [0 2] paragraph: "Foo" -> "Bar"`.
+func (c BlockChange) String() string {
+	switch c.Change {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %v %v: %q", c.Path, c.Type, PlainText(BlockRichText(c.New)))
+	case ChangeRemoved:
+		return fmt.Sprintf("- %v %v: %q", c.Path, c.Type, PlainText(BlockRichText(c.Old)))
+	}
+
+	return fmt.Sprintf("~ %v %v: %q -> %q", c.Path, c.Type, PlainText(BlockRichText(c.Old)), PlainText(BlockRichText(c.New)))
+}
+
+func diffBlocks(diff *BlockDiff, old, new []Block, path []int) {
+	oldKeys, newKeys := blockKeys(old), blockKeys(new)
+
+	// Pairs of indexes of matching blocks, from a longest common subsequence of
+	// block keys, followed by a sentinel.
+	pairs := append(lcs(oldKeys, newKeys), [2]int{len(old), len(new)})
+
+	i, j := 0, 0
+	for _, pair := range pairs {
+		for ; i < pair[0]; i++ {
+			diff.Blocks = append(diff.Blocks, blockChange(ChangeRemoved, old[i], nil, childPath(path, i)))
+		}
+		for ; j < pair[1]; j++ {
+			diff.Blocks = append(diff.Blocks, blockChange(ChangeAdded, nil, new[j], childPath(path, j)))
+		}
+		if i == len(old) && j == len(new) {
+			break
+		}
+
+		if !bytes.Equal(blockContent(old[i]), blockContent(new[j])) {
+			diff.Blocks = append(diff.Blocks, blockChange(ChangeModified, old[i], new[j], childPath(path, j)))
+		}
+		diffBlocks(diff, BlockChildren(old[i]), BlockChildren(new[j]), childPath(path, j))
+
+		i++
+		j++
+	}
+}
+
+func blockChange(change ChangeType, old, new Block, path []int) BlockChange {
+	b := new
+	if b == nil {
+		b = old
+	}
+
+	return BlockChange{
+		Change: change,
+		ID:     b.ID(),
+		Type:   BlockTypeOf(b),
+		Path:   path,
+		Old:    old,
+		New:    new,
+	}
+}
+
+func childPath(path []int, i int) []int {
+	return append(append(make([]int, 0, len(path)+1), path...), i)
+}
+
+// blockKeys returns the keys by which blocks are matched: their ID, or their
+// content for blocks without ID.
+func blockKeys(blocks []Block) []string {
+	keys := make([]string, len(blocks))
+	for i, b := range blocks {
+		if id := b.ID(); id != "" {
+			keys[i] = "id:" + id
+		} else {
+			keys[i] = "content:" + string(blockContent(b))
+		}
+	}
+	return keys
+}
+
+// blockContent returns the normalized JSON of a block without its children.
+func blockContent(b Block) []byte {
+	if len(BlockChildren(b)) > 0 {
+		// Children are set on a copy, to leave b untouched.
+		if copied, err := SetBlockChildren(blockValue(b), nil); err == nil {
+			b = copied
+		}
+	}
+
+	content, err := json.Marshal(b)
+	if err != nil {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(content, &v); err != nil {
+		return content
+	}
+
+	content, _ = json.Marshal(normalizeFiles(v))
+
+	return content
+}
+
+// normalizeFiles removes the expiry time and signature of Notion hosted files
+// in decoded JSON, as they change with each request.
+func normalizeFiles(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if file, ok := v["file"].(map[string]interface{}); ok && v["type"] == string(FileTypeFile) {
+			delete(file, "expiry_time")
+			if u, ok := file["url"].(string); ok {
+				file["url"] = fileURL(u)
+			}
+		}
+		for key, value := range v {
+			v[key] = normalizeFiles(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = normalizeFiles(value)
+		}
+	}
+
+	return v
+}
+
+// lcs returns the index pairs of a longest common subsequence of a and b.
+func lcs(a, b []string) [][2]int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return pairs
+}
+
+// diffProperties returns the properties of a page for diffing.
+func diffProperties(page Page) DatabasePageProperties {
+	switch props := page.Properties.(type) {
+	case DatabasePageProperties:
+		return props
+	case PageProperties:
+		return DatabasePageProperties{"title": props.property()}
+	case *PageProperties:
+		return DatabasePageProperties{"title": props.property()}
+	}
+	return nil
+}
+
+func equalProperty(a, b DatabasePageProperty) bool {
+	if a.Type != b.Type {
+		return false
+	}
+
+	switch a.Type {
+	case DBPropTypeTitle:
+		return equalRichText(a.Title, b.Title)
+	case DBPropTypeRichText:
+		return equalRichText(a.RichText, b.RichText)
+	case DBPropTypeNumber:
+		return equalFloat(a.Number, b.Number)
+	case DBPropTypeSelect:
+		return equalSelect(a.Select, b.Select)
+	case DBPropTypeStatus:
+		return equalSelect(a.Status, b.Status)
+	case DBPropTypeMultiSelect, DBPropTypeRelation, DBPropTypePeople:
+		added, removed := diffSets(propertySet(a), propertySet(b))
+		return len(added) == 0 && len(removed) == 0
+	case DBPropTypeDate:
+		return equalDate(a.Date, b.Date)
+	case DBPropTypeFiles:
+		return equalFiles(a.Files, b.Files)
+	case DBPropTypeCheckbox:
+		return equalBool(a.Checkbox, b.Checkbox)
+	case DBPropTypeURL:
+		return equalString(a.URL, b.URL)
+	case DBPropTypeEmail:
+		return equalString(a.Email, b.Email)
+	case DBPropTypePhoneNumber:
+		return equalString(a.PhoneNumber, b.PhoneNumber)
+	case DBPropTypeFormula:
+		return equalFormula(a.Formula, b.Formula)
+	case DBPropTypeCreatedTime:
+		return equalTime(a.CreatedTime, b.CreatedTime)
+	case DBPropTypeLastEditedTime:
+		return equalTime(a.LastEditedTime, b.LastEditedTime)
+	case DBPropTypeCreatedBy:
+		return equalUser(a.CreatedBy, b.CreatedBy)
+	case DBPropTypeLastEditedBy:
+		return equalUser(a.LastEditedBy, b.LastEditedBy)
+	default:
+		// Rollups and properties of unknown types are compared by their JSON.
+		ja, errA := json.Marshal(a)
+		jb, errB := json.Marshal(b)
+		return errA == nil && errB == nil && bytes.Equal(ja, jb)
+	}
+}
+
+// equalRichText compares rich text by plain text, annotations and links,
+// regardless of how the text is split into elements.
+func equalRichText(a, b []RichText) bool {
+	ra, rb := richTextRuns(a), richTextRuns(b)
+	if len(ra) != len(rb) {
+		return false
+	}
+	for i := range ra {
+		if ra[i] != rb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type richTextRun struct {
+	text        string
+	annotations Annotations
+	href        string
+}
+
+// richTextRuns merges adjacent rich text elements with equal annotations and
+// links, and drops empty ones.
+func richTextRuns(richText []RichText) []richTextRun {
+	var runs []richTextRun
+
+	for _, rt := range richText {
+		run := richTextRun{text: PlainText([]RichText{rt})}
+		if run.text == "" {
+			continue
+		}
+		if rt.Annotations != nil {
+			run.annotations = *rt.Annotations
+		}
+		if run.annotations.Color == ColorDefault {
+			run.annotations.Color = ""
+		}
+		if rt.HRef != nil {
+			run.href = *rt.HRef
+		}
+
+		if n := len(runs); n > 0 && runs[n-1].annotations == run.annotations && runs[n-1].href == run.href {
+			runs[n-1].text += run.text
+			continue
+		}
+		runs = append(runs, run)
+	}
+
+	return runs
+}
+
+func equalString(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalBool(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalSelect(a, b *SelectOptions) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Name == b.Name
+}
+
+func equalDate(a, b *Date) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if !a.Start.Equal(b.Start) {
+		return false
+	}
+	if a.End == nil || b.End == nil {
+		if a.End != b.End {
+			return false
+		}
+	} else if !a.End.Equal(*b.End) {
+		return false
+	}
+	return equalString(a.TimeZone, b.TimeZone)
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func equalUser(a, b *User) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.ID == b.ID
+}
+
+func equalFormula(a, b *FormulaResult) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Type != b.Type {
+		return false
+	}
+	return equalString(a.String, b.String) &&
+		equalFloat(a.Number, b.Number) &&
+		equalBool(a.Boolean, b.Boolean) &&
+		equalDate(a.Date, b.Date)
+}
+
+func equalFiles(a, b []File) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if fileKey(a[i]) != fileKey(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// fileKey identifies a file by name and URL (without the signature of Notion
+// hosted file URLs), or by name and file upload ID.
+func fileKey(f File) string {
+	switch {
+	case f.File != nil:
+		return f.Name + "\n" + fileURL(f.File.URL)
+	case f.External != nil:
+		return f.Name + "\n" + f.External.URL
+	case f.FileUpload != nil:
+		return f.Name + "\n" + f.FileUpload.ID
+	}
+	return f.Name
+}
+
+// fileURL returns a Notion hosted file URL without its query, which holds the
+// signature.
+func fileURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.RawQuery = ""
+	return u.String()
+}
+
+// propertySet returns the values of multi-select, relation and people
+// properties, or nil for other properties.
+func propertySet(prop DatabasePageProperty) []string {
+	var values []string
+
+	switch prop.Type {
+	case DBPropTypeMultiSelect:
+		for _, option := range prop.MultiSelect {
+			values = append(values, option.Name)
+		}
+	case DBPropTypeRelation:
+		for _, relation := range prop.Relation {
+			values = append(values, relation.ID)
+		}
+	case DBPropTypePeople:
+		for _, user := range prop.People {
+			values = append(values, user.ID)
+		}
+	}
+
+	return values
+}
+
+// diffSets returns the values of b that aren't in a, and vice versa.
+func diffSets(a, b []string) (added, removed []string) {
+	inA := make(map[string]bool, len(a))
+	for _, v := range a {
+		inA[v] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, v := range b {
+		inB[v] = true
+	}
+
+	for _, v := range b {
+		if !inA[v] {
+			added = append(added, v)
+		}
+	}
+	for _, v := range a {
+		if !inB[v] {
+			removed = append(removed, v)
+		}
+	}
+
+	return added, removed
+}
+
+// propertyText returns a property value as plain text, for diff output.
+func propertyText(prop DatabasePageProperty) string {
+	switch prop.Type {
+	case DBPropTypeTitle:
+		return PlainText(prop.Title)
+	case DBPropTypeRichText:
+		return PlainText(prop.RichText)
+	case DBPropTypeNumber:
+		if prop.Number != nil {
+			return strconv.FormatFloat(*prop.Number, 'f', -1, 64)
+		}
+	case DBPropTypeSelect:
+		if prop.Select != nil {
+			return prop.Select.Name
+		}
+	case DBPropTypeStatus:
+		if prop.Status != nil {
+			return prop.Status.Name
+		}
+	case DBPropTypeMultiSelect, DBPropTypeRelation, DBPropTypePeople:
+		return strings.Join(propertySet(prop), ", ")
+	case DBPropTypeDate:
+		if prop.Date != nil {
+			return dateText(*prop.Date)
+		}
+	case DBPropTypeFiles:
+		names := make([]string, len(prop.Files))
+		for i, f := range prop.Files {
+			names[i] = f.Name
+		}
+		return strings.Join(names, ", ")
+	case DBPropTypeCheckbox:
+		if prop.Checkbox != nil {
+			return strconv.FormatBool(*prop.Checkbox)
+		}
+	case DBPropTypeURL:
+		if prop.URL != nil {
+			return *prop.URL
+		}
+	case DBPropTypeEmail:
+		if prop.Email != nil {
+			return *prop.Email
+		}
+	case DBPropTypePhoneNumber:
+		if prop.PhoneNumber != nil {
+			return *prop.PhoneNumber
+		}
+	case DBPropTypeFormula:
+		if f := prop.Formula; f != nil {
+			switch {
+			case f.String != nil:
+				return *f.String
+			case f.Number != nil:
+				return strconv.FormatFloat(*f.Number, 'f', -1, 64)
+			case f.Boolean != nil:
+				return strconv.FormatBool(*f.Boolean)
+			case f.Date != nil:
+				return dateText(*f.Date)
+			}
+		}
+	case DBPropTypeCreatedTime:
+		if prop.CreatedTime != nil {
+			return prop.CreatedTime.Format(time.RFC3339)
+		}
+	case DBPropTypeLastEditedTime:
+		if prop.LastEditedTime != nil {
+			return prop.LastEditedTime.Format(time.RFC3339)
+		}
+	case DBPropTypeCreatedBy:
+		if prop.CreatedBy != nil {
+			return prop.CreatedBy.ID
+		}
+	case DBPropTypeLastEditedBy:
+		if prop.LastEditedBy != nil {
+			return prop.LastEditedBy.ID
+		}
+	default:
+		b, _ := json.Marshal(prop)
+		return string(b)
+	}
+
+	return ""
+}
+
+// dateText returns a date, or a date range as an ISO 8601 interval
+// (`start/end`).
+func dateText(d Date) string {
+	text := dateTimeText(d.Start)
+	if d.End != nil {
+		text += "/" + dateTimeText(*d.End)
+	}
+	return text
+}
+
+func dateTimeText(dt DateTime) string {
+	if dt.HasTime() {
+		return dt.Time.Format(time.RFC3339)
+	}
+	return dt.Time.Format("2006-01-02")
+}
@@ -0,0 +1,186 @@
+package notion_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+)
+
+func mustParsePage(t *testing.T, properties string) notion.Page {
+	t.Helper()
+
+	var page notion.Page
+	err := json.Unmarshal([]byte(`{
+		"id": "7c6b1c95-de50-45ca-94e6-af1d9fd295ab",
+		"parent": {"type": "database_id", "database_id": "39ddfc9d-33c9-404c-89cf-79f01c42dd0c"},
+		"properties": `+properties+`
+	}`), &page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return page
+}
+
+func TestDiffPages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		expDiff string
+	}{
+		{
+			name: "rich text split differently",
+			old:  `{"Name": {"type": "title", "title": [{"type": "text", "plain_text": "Foo bar"}]}}`,
+			new: `{"Name": {"type": "title", "title": [
+				{"type": "text", "plain_text": "Foo ", "annotations": {"color": "default"}},
+				{"type": "text", "plain_text": "bar"}
+			]}}`,
+		},
+		{
+			name: "rich text annotations",
+			old:  `{"Name": {"type": "title", "title": [{"type": "text", "plain_text": "Foo"}]}}`,
+			new:  `{"Name": {"type": "title", "title": [{"type": "text", "plain_text": "Foo", "annotations": {"bold": true}}]}}`,
+			expDiff: `~ Name: "Foo" -> "Foo"
+`,
+		},
+		{
+			name: "multi-select as set",
+			old:  `{"Tags": {"type": "multi_select", "multi_select": [{"name": "A"}, {"name": "B"}]}}`,
+			new:  `{"Tags": {"type": "multi_select", "multi_select": [{"name": "B"}, {"name": "A"}]}}`,
+		},
+		{
+			name: "multi-select changes",
+			old:  `{"Tags": {"type": "multi_select", "multi_select": [{"name": "A"}, {"name": "B"}]}}`,
+			new:  `{"Tags": {"type": "multi_select", "multi_select": [{"name": "B"}, {"name": "C"}]}}`,
+			expDiff: `~ Tags: +"C" -"A"
+`,
+		},
+		{
+			name: "relations as set",
+			old:  `{"Related": {"type": "relation", "relation": [{"id": "r1"}, {"id": "r2"}]}}`,
+			new:  `{"Related": {"type": "relation", "relation": [{"id": "r2"}, {"id": "r1"}]}}`,
+		},
+		{
+			name: "equal dates in other time zones",
+			old:  `{"Due": {"type": "date", "date": {"start": "2021-05-23T09:00:00.000Z"}}}`,
+			new:  `{"Due": {"type": "date", "date": {"start": "2021-05-23T11:00:00.000+02:00"}}}`,
+		},
+		{
+			name: "date with time",
+			old:  `{"Due": {"type": "date", "date": {"start": "2021-05-23"}}}`,
+			new:  `{"Due": {"type": "date", "date": {"start": "2021-05-23", "end": "2021-05-25"}}}`,
+			expDiff: `~ Due: "2021-05-23" -> "2021-05-23/2021-05-25"
+`,
+		},
+		{
+			name: "files with new signature",
+			old:  `{"Files": {"type": "files", "files": [{"name": "a.pdf", "type": "file", "file": {"url": "https://s3.example.com/a.pdf?X-Amz-Signature=1", "expiry_time": "2021-05-23T10:00:00.000Z"}}]}}`,
+			new:  `{"Files": {"type": "files", "files": [{"name": "a.pdf", "type": "file", "file": {"url": "https://s3.example.com/a.pdf?X-Amz-Signature=2", "expiry_time": "2021-05-23T11:00:00.000Z"}}]}}`,
+		},
+		{
+			name: "files with other file upload",
+			old:  `{"Files": {"type": "files", "files": [{"name": "a.pdf", "type": "file_upload", "file_upload": {"id": "43833259-72ae-404e-8441-b6577f3159b4"}}]}}`,
+			new:  `{"Files": {"type": "files", "files": [{"name": "a.pdf", "type": "file_upload", "file_upload": {"id": "b0668f48-8d66-4733-9bdb-2f82215707f7"}}]}}`,
+			expDiff: `~ Files: "a.pdf" -> "a.pdf"
+`,
+		},
+		{
+			name: "added, removed and modified",
+			old: `{
+				"Price": {"type": "number", "number": 12.5},
+				"Status": {"type": "select", "select": {"name": "Todo"}},
+				"Done": {"type": "checkbox", "checkbox": false}
+			}`,
+			new: `{
+				"Price": {"type": "number", "number": 12.5},
+				"Status": {"type": "select", "select": {"name": "Done"}},
+				"URL": {"type": "url", "url": "https://example.com"}
+			}`,
+			expDiff: `- Done: "false"
+~ Status: "Todo" -> "Done"
++ URL: "https://example.com"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			diff := notion.DiffPages(mustParsePage(t, tt.old), mustParsePage(t, tt.new))
+
+			if diff := cmp.Diff(tt.expDiff, diff.String()); diff != "" {
+				t.Errorf("diff not equal (-exp, +got):\n%v", diff)
+			}
+			if exp, got := tt.expDiff == "", diff.Empty(); exp != got {
+				t.Errorf("empty not equal (expected: %v, got: %v)", exp, got)
+			}
+		})
+	}
+}
+
+func TestDiffPagesJSON(t *testing.T) {
+	t.Parallel()
+
+	diff := notion.DiffPages(
+		mustParsePage(t, `{"Tags": {"id": "a", "type": "multi_select", "multi_select": [{"name": "A"}]}}`),
+		mustParsePage(t, `{"Tags": {"id": "a", "type": "multi_select", "multi_select": [{"name": "B"}]}}`),
+	)
+
+	b, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := `{"properties":[{"name":"Tags","type":"multi_select","change":"modified",` +
+		`"old":{"id":"a","type":"multi_select","multi_select":[{"name":"A"}]},` +
+		`"new":{"id":"a","type":"multi_select","multi_select":[{"name":"B"}]},` +
+		`"added":["B"],"removed":["A"]}]}`
+	if diff := cmp.Diff(exp, string(b)); diff != "" {
+		t.Errorf("JSON not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestDiffBlocks(t *testing.T) {
+	t.Parallel()
+
+	paragraph := func(id, text string, children ...notion.Block) notion.Block {
+		return &notion.ParagraphBlock{
+			BaseBlock: notion.BaseBlock{IdProperty: id},
+			RichText:  []notion.RichText{{Text: &notion.Text{Content: text}}},
+			Children:  children,
+		}
+	}
+
+	old := []notion.Block{
+		paragraph("a", "Foo"),
+		paragraph("b", "Bar", paragraph("c", "Child")),
+		paragraph("d", "Removed"),
+	}
+	new := []notion.Block{
+		paragraph("a", "Foo"),
+		paragraph("e", "Added"),
+		paragraph("b", "Baz", paragraph("c", "Child, edited")),
+	}
+
+	diff := notion.DiffBlocks(old, new)
+
+	exp := `+ [1] paragraph: "Added"
+~ [2] paragraph: "Bar" -> "Baz"
+~ [2 0] paragraph: "Child" -> "Child, edited"
+- [2] paragraph: "Removed"
+`
+	if diff := cmp.Diff(exp, diff.String()); diff != "" {
+		t.Errorf("diff not equal (-exp, +got):\n%v", diff)
+	}
+
+	if !notion.DiffBlocks(old, old).Empty() {
+		t.Error("expected empty diff of equal blocks")
+	}
+}
@@ -0,0 +1,206 @@
+package download
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+// Attachment is a Notion hosted file, and the page or block it belongs to,
+// which is fetched again to refresh its URL when it expires.
+type Attachment struct {
+	// Name is the file name: the name of a file of a files property, or the
+	// last segment of the URL path for other files.
+	Name       string
+	URL        string
+	ExpiryTime time.Time
+
+	// BlockID is set for files of blocks, PageID for files of pages, and
+	// DatabaseID for files of databases.
+	BlockID    string
+	PageID     string
+	DatabaseID string
+	// Property is the name of the files property, or "icon" or "cover" for the
+	// icon and cover of a page or database.
+	Property string
+	// Index is the position of the file in the files property.
+	Index int
+}
+
+// OwnerID returns the ID of the page, database or block of the attachment.
+func (a Attachment) OwnerID() string {
+	switch {
+	case a.BlockID != "":
+		return a.BlockID
+	case a.DatabaseID != "":
+		return a.DatabaseID
+	}
+	return a.PageID
+}
+
+// key identifies the attachment within its owner.
+func (a Attachment) key() string {
+	return a.Property + "\x00" + strconv.Itoa(a.Index)
+}
+
+// PageAttachments returns the Notion hosted files of a page: its icon, cover,
+// and the files of its files properties, sorted by property name. Files with
+// external URLs are omitted.
+func PageAttachments(page notion.Page) []Attachment {
+	var attachments []Attachment
+
+	if icon := page.Icon; icon != nil && icon.Type == notion.IconTypeFile && icon.File != nil {
+		attachments = append(attachments, pageFileAttachment(page.ID, "icon", icon.File))
+	}
+	if cover := page.Cover; cover != nil && cover.Type == notion.FileTypeFile && cover.File != nil {
+		attachments = append(attachments, pageFileAttachment(page.ID, "cover", cover.File))
+	}
+
+	props, _ := page.DatabaseProperties()
+
+	names := make([]string, 0, len(props))
+	for name, prop := range props {
+		if prop.Type == notion.DBPropTypeFiles {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		attachments = append(attachments, FileAttachments(page.ID, name, props[name].Files)...)
+	}
+
+	return attachments
+}
+
+// DatabaseAttachments returns the Notion hosted files of a database: its icon
+// and cover. Files with external URLs are omitted.
+func DatabaseAttachments(db notion.Database) []Attachment {
+	var attachments []Attachment
+
+	if icon := db.Icon; icon != nil && icon.Type == notion.IconTypeFile && icon.File != nil {
+		a := pageFileAttachment("", "icon", icon.File)
+		a.DatabaseID = db.ID
+		attachments = append(attachments, a)
+	}
+	if cover := db.Cover; cover != nil && cover.Type == notion.FileTypeFile && cover.File != nil {
+		a := pageFileAttachment("", "cover", cover.File)
+		a.DatabaseID = db.ID
+		attachments = append(attachments, a)
+	}
+
+	return attachments
+}
+
+func pageFileAttachment(pageID, property string, f *notion.FileFile) Attachment {
+	return Attachment{
+		Name:       urlName(f.URL),
+		URL:        f.URL,
+		ExpiryTime: f.ExpiryTime.Time,
+		PageID:     pageID,
+		Property:   property,
+	}
+}
+
+// FileAttachments returns the Notion hosted files of a files property of a page.
+func FileAttachments(pageID, property string, files []notion.File) []Attachment {
+	var attachments []Attachment
+
+	for i, f := range files {
+		if f.Type != notion.FileTypeFile || f.File == nil {
+			continue
+		}
+
+		name := f.Name
+		if name == "" {
+			name = urlName(f.File.URL)
+		}
+
+		attachments = append(attachments, Attachment{
+			Name:       name,
+			URL:        f.File.URL,
+			ExpiryTime: f.File.ExpiryTime.Time,
+			PageID:     pageID,
+			Property:   property,
+			Index:      i,
+		})
+	}
+
+	return attachments
+}
+
+// BlockAttachments returns the Notion hosted files of image, file, PDF, audio
+// and video blocks in a block tree, in depth-first order.
+func BlockAttachments(blocks []notion.Block) []Attachment {
+	var attachments []Attachment
+
+	notion.Inspect(blocks, func(b notion.Block, _ notion.WalkInfo) bool {
+		if f := blockFile(b); f != nil {
+			attachments = append(attachments, Attachment{
+				Name:       urlName(f.URL),
+				URL:        f.URL,
+				ExpiryTime: f.ExpiryTime.Time,
+				BlockID:    b.ID(),
+			})
+		}
+		return true
+	})
+
+	return attachments
+}
+
+// blockFile returns the Notion hosted file of a block, or nil.
+func blockFile(b notion.Block) *notion.FileFile {
+	var (
+		fileType notion.FileType
+		file     *notion.FileFile
+	)
+
+	switch b := b.(type) {
+	case *notion.ImageBlock:
+		fileType, file = b.Type, b.File
+	case notion.ImageBlock:
+		fileType, file = b.Type, b.File
+	case *notion.FileBlock:
+		fileType, file = b.Type, b.File
+	case notion.FileBlock:
+		fileType, file = b.Type, b.File
+	case *notion.PDFBlock:
+		fileType, file = b.Type, b.File
+	case notion.PDFBlock:
+		fileType, file = b.Type, b.File
+	case *notion.AudioBlock:
+		fileType, file = b.Type, b.File
+	case notion.AudioBlock:
+		fileType, file = b.Type, b.File
+	case *notion.VideoBlock:
+		fileType, file = b.Type, b.File
+	case notion.VideoBlock:
+		fileType, file = b.Type, b.File
+	}
+
+	if fileType != notion.FileTypeFile {
+		return nil
+	}
+
+	return file
+}
+
+// urlName returns the unescaped last segment of the path of a URL.
+func urlName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return ""
+	}
+
+	return name
+}
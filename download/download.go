@@ -0,0 +1,309 @@
+// Package download downloads Notion hosted files of pages, blocks and files
+// properties. The URLs of these files expire after an hour; a Downloader
+// fetches the page, database or block of a file again to refresh its URL when it's
+// expired or about to expire, or when the download is rejected as expired.
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+const (
+	defaultConcurrency   = 4
+	defaultRefreshBefore = 5 * time.Minute
+)
+
+// ErrSizeMismatch is used when the size of a downloaded file differs from the
+// Content-Length of the response.
+var ErrSizeMismatch = errors.New("download: size mismatch")
+
+// errExpired is used for downloads that are rejected, presumably because the
+// URL expired.
+var errExpired = errors.New("download: URL expired")
+
+// Client is the subset of the Notion API used to refresh file URLs.
+type Client interface {
+	FindPageByID(ctx context.Context, id string) (notion.Page, error)
+	FindDatabaseByID(ctx context.Context, id string) (notion.Database, error)
+	FindBlockByID(ctx context.Context, blockID string) (notion.Block, error)
+}
+
+// Options configure a Downloader.
+type Options struct {
+	// Client is used to refresh file URLs. Without client, expired URLs fail
+	// to download.
+	Client Client
+
+	// HTTPClient is used to download files. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Concurrency is the maximum number of concurrent downloads of
+	// DownloadAll. Defaults to 4.
+	Concurrency int
+
+	// RefreshBefore is how long before their expiry URLs are refreshed.
+	// Defaults to 5 minutes.
+	RefreshBefore time.Duration
+}
+
+// Downloader downloads Notion hosted files.
+type Downloader struct {
+	opts Options
+}
+
+// Result is the result of downloading an attachment with DownloadAll.
+type Result struct {
+	Attachment Attachment
+	// Path is the path of the downloaded file.
+	Path string
+	Size int64
+	Err  error
+}
+
+// New returns a new Downloader.
+func New(opts Options) *Downloader {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultConcurrency
+	}
+	if opts.RefreshBefore <= 0 {
+		opts.RefreshBefore = defaultRefreshBefore
+	}
+
+	return &Downloader{opts: opts}
+}
+
+// Download streams an attachment to w, and returns the number of bytes
+// written.
+func (d *Downloader) Download(ctx context.Context, a Attachment, w io.Writer) (int64, error) {
+	refreshed := false
+
+	if !a.ExpiryTime.IsZero() && time.Until(a.ExpiryTime) < d.opts.RefreshBefore && d.opts.Client != nil {
+		var err error
+		if a, err = d.Refresh(ctx, a); err != nil {
+			return 0, err
+		}
+		refreshed = true
+	}
+
+	n, err := d.fetch(ctx, a, w)
+	if errors.Is(err, errExpired) && !refreshed && d.opts.Client != nil {
+		if a, err = d.Refresh(ctx, a); err != nil {
+			return 0, err
+		}
+		n, err = d.fetch(ctx, a, w)
+	}
+	if err != nil {
+		return n, fmt.Errorf("download: failed to download %v: %w", a.Name, err)
+	}
+
+	return n, nil
+}
+
+// DownloadAll downloads attachments to a directory, with bounded concurrency.
+// Files are named after their attachment, with a numeric suffix for duplicate
+// names, and written to a temporary file first, so failed downloads don't
+// leave partial files. The results are in order of the attachments; the
+// returned error combines the errors of failed downloads.
+func (d *Downloader) DownloadAll(ctx context.Context, attachments []Attachment, dir string) ([]Result, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("download: failed to create directory: %w", err)
+	}
+
+	results := make([]Result, len(attachments))
+	names := uniqueNames(attachments)
+
+	sem := make(chan struct{}, d.opts.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, a := range attachments {
+		results[i] = Result{Attachment: a, Path: filepath.Join(dir, names[i])}
+
+		select {
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(r *Result) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			r.Size, r.Err = d.downloadFile(ctx, r.Attachment, r.Path)
+		}(&results[i])
+	}
+
+	wg.Wait()
+
+	var errs notion.MultiError
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+	}
+	if len(errs) > 0 {
+		return results, errs
+	}
+
+	return results, nil
+}
+
+func (d *Downloader) downloadFile(ctx context.Context, a Attachment, path string) (int64, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return 0, fmt.Errorf("download: failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := d.Download(ctx, a, tmp)
+	if err != nil {
+		tmp.Close()
+		return n, err
+	}
+	if err := tmp.Close(); err != nil {
+		return n, fmt.Errorf("download: failed to write file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return n, fmt.Errorf("download: failed to write file: %w", err)
+	}
+
+	return n, nil
+}
+
+// Refresh fetches the page, database or block of an attachment, and returns the
+// attachment with its current URL and expiry time. The file must still have the
+// same name and position.
+func (d *Downloader) Refresh(ctx context.Context, a Attachment) (Attachment, error) {
+	if d.opts.Client == nil {
+		return Attachment{}, errors.New("download: client is required to refresh URLs")
+	}
+
+	var attachments []Attachment
+
+	switch {
+	case a.BlockID != "":
+		block, err := d.opts.Client.FindBlockByID(ctx, a.BlockID)
+		if err != nil {
+			return Attachment{}, fmt.Errorf("download: failed to refresh URL: %w", err)
+		}
+		attachments = BlockAttachments([]notion.Block{block})
+	case a.DatabaseID != "":
+		db, err := d.opts.Client.FindDatabaseByID(ctx, a.DatabaseID)
+		if err != nil {
+			return Attachment{}, fmt.Errorf("download: failed to refresh URL: %w", err)
+		}
+		attachments = DatabaseAttachments(db)
+	default:
+		page, err := d.opts.Client.FindPageByID(ctx, a.PageID)
+		if err != nil {
+			return Attachment{}, fmt.Errorf("download: failed to refresh URL: %w", err)
+		}
+		attachments = PageAttachments(page)
+	}
+
+	for _, refreshed := range attachments {
+		// The name is compared as well, as files of a files property may have
+		// been reordered or removed since the attachment was listed.
+		if refreshed.key() == a.key() && refreshed.Name == a.Name {
+			return refreshed, nil
+		}
+	}
+
+	return Attachment{}, fmt.Errorf("download: file %v of %v no longer exists", a.Name, a.OwnerID())
+}
+
+func (d *Downloader) fetch(ctx context.Context, a Attachment, w io.Writer) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.URL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid request: %w", err)
+	}
+
+	res, err := d.opts.HTTPClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to make HTTP request: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusBadRequest:
+		// Expired signed URLs are rejected with one of these statuses.
+		return 0, errExpired
+	case res.StatusCode != http.StatusOK:
+		return 0, fmt.Errorf("unexpected HTTP status %v", res.Status)
+	}
+
+	n, err := io.Copy(w, res.Body)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		// The body is shorter than its Content-Length.
+		return n, fmt.Errorf("%w: expected %v bytes, got %v", ErrSizeMismatch, res.ContentLength, n)
+	}
+	if err != nil {
+		return n, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if res.ContentLength >= 0 && n != res.ContentLength {
+		return n, fmt.Errorf("%w: expected %v bytes, got %v", ErrSizeMismatch, res.ContentLength, n)
+	}
+
+	return n, nil
+}
+
+// uniqueNames returns file names for attachments, with a suffix for names that
+// occur multiple times, e.g. `image (2).png`.
+func uniqueNames(attachments []Attachment) []string {
+	names := make([]string, len(attachments))
+	used := make(map[string]bool, len(attachments))
+
+	for i, a := range attachments {
+		name := sanitizeName(a.Name)
+		if name == "" {
+			name = a.OwnerID()
+		}
+
+		// Suffixed names can collide with other names, e.g. `a.png`, `a.png`
+		// and `a (2).png`, so the suffix is increased until the name is unused.
+		ext := filepath.Ext(name)
+		base := strings.TrimSuffix(name, ext)
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = base + " (" + strconv.Itoa(n) + ")" + ext
+		}
+
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+
+	return names
+}
+
+// sanitizeName returns a file name without path separators.
+func sanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == 0 {
+			return '_'
+		}
+		return r
+	}, name)
+
+	if name == "." || name == ".." {
+		return ""
+	}
+
+	return name
+}
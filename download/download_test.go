@@ -0,0 +1,336 @@
+package download_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/download"
+)
+
+const blockID = "f25f1dea-8c3c-4ab1-b1e7-a2b5b6a3e2a1"
+
+func imageBlock(url string, expiry time.Time) *notion.ImageBlock {
+	return &notion.ImageBlock{
+		BaseBlock: notion.BaseBlock{IdProperty: blockID},
+		Type:      notion.FileTypeFile,
+		File: &notion.FileFile{
+			URL:        url,
+			ExpiryTime: notion.NewDateTime(expiry, true),
+		},
+	}
+}
+
+func TestBlockAttachments(t *testing.T) {
+	t.Parallel()
+
+	expiry := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	blocks := []notion.Block{
+		&notion.ParagraphBlock{},
+		imageBlock("https://files.example.com/a/photo%201.png?sig=x", expiry),
+		&notion.FileBlock{
+			Type:     notion.FileTypeExternal,
+			External: &notion.FileExternal{URL: "https://example.com/external.pdf"},
+		},
+	}
+
+	exp := []download.Attachment{
+		{
+			Name:       "photo 1.png",
+			URL:        "https://files.example.com/a/photo%201.png?sig=x",
+			ExpiryTime: expiry,
+			BlockID:    blockID,
+		},
+	}
+
+	if diff := cmp.Diff(exp, download.BlockAttachments(blocks)); diff != "" {
+		t.Errorf("attachments not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fresh/photo.png":
+			w.Write([]byte("content"))
+		case "/short/photo.png":
+			w.Header().Set("Content-Length", "10")
+			w.Write([]byte("content"))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name       string
+		url        string
+		expiry     time.Time
+		expRefresh bool
+		expContent string
+		expError   error
+	}{
+		{
+			name:       "valid URL",
+			url:        srv.URL + "/fresh/photo.png",
+			expiry:     time.Now().Add(time.Hour),
+			expContent: "content",
+		},
+		{
+			name:       "about to expire",
+			url:        srv.URL + "/expired/photo.png",
+			expiry:     time.Now().Add(time.Minute),
+			expRefresh: true,
+			expContent: "content",
+		},
+		{
+			name:       "rejected as expired",
+			url:        srv.URL + "/expired/photo.png",
+			expiry:     time.Now().Add(time.Hour),
+			expRefresh: true,
+			expContent: "content",
+		},
+		{
+			name:     "size mismatch",
+			url:      srv.URL + "/short/photo.png",
+			expiry:   time.Now().Add(time.Hour),
+			expError: download.ErrSizeMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			refreshed := false
+			client := &notion.MockClient{
+				FindBlockByIDFunc: func(ctx context.Context, id string) (notion.Block, error) {
+					if id != blockID {
+						t.Fatalf("unexpected block ID: %v", id)
+					}
+					refreshed = true
+					return imageBlock(srv.URL+"/fresh/photo.png", time.Now().Add(time.Hour)), nil
+				},
+			}
+
+			d := download.New(download.Options{Client: client})
+			a := download.BlockAttachments([]notion.Block{imageBlock(tt.url, tt.expiry)})[0]
+
+			var buf bytes.Buffer
+			_, err := d.Download(context.Background(), a, &buf)
+			if !errors.Is(err, tt.expError) {
+				t.Fatalf("error not equal (expected: %v, got: %v)", tt.expError, err)
+			}
+			if tt.expRefresh != refreshed {
+				t.Errorf("refreshed not equal (expected: %v, got: %v)", tt.expRefresh, refreshed)
+			}
+			if tt.expError == nil && tt.expContent != buf.String() {
+				t.Errorf("content not equal (expected: %q, got: %q)", tt.expContent, buf.String())
+			}
+		})
+	}
+}
+
+func TestRefreshDatabase(t *testing.T) {
+	t.Parallel()
+
+	db := func(url string) notion.Database {
+		return notion.Database{
+			ID: "39ddfc9d-33c9-404c-89cf-79f01c42dd0c",
+			Cover: &notion.Cover{
+				Type: notion.FileTypeFile,
+				File: &notion.FileFile{URL: url, ExpiryTime: notion.NewDateTime(time.Now().Add(time.Hour), true)},
+			},
+		}
+	}
+
+	client := &notion.MockClient{
+		FindDatabaseByIDFunc: func(ctx context.Context, id string) (notion.Database, error) {
+			return db("https://example.com/fresh/cover.png"), nil
+		},
+	}
+
+	attachments := download.DatabaseAttachments(db("https://example.com/expired/cover.png"))
+	if exp, got := 1, len(attachments); exp != got {
+		t.Fatalf("attachment count not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	d := download.New(download.Options{Client: client})
+	refreshed, err := d.Refresh(context.Background(), attachments[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := download.Attachment{
+		Name:       "cover.png",
+		URL:        "https://example.com/fresh/cover.png",
+		DatabaseID: "39ddfc9d-33c9-404c-89cf-79f01c42dd0c",
+		Property:   "cover",
+	}
+	if diff := cmp.Diff(exp, refreshed, cmpopts.IgnoreFields(download.Attachment{}, "ExpiryTime")); diff != "" {
+		t.Errorf("attachment not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestRefreshFiles(t *testing.T) {
+	t.Parallel()
+
+	page := func(names ...string) notion.Page {
+		files := make([]notion.File, len(names))
+		for i, name := range names {
+			files[i] = notion.File{
+				Name: name,
+				Type: notion.FileTypeFile,
+				File: &notion.FileFile{URL: "https://example.com/fresh/" + name, ExpiryTime: notion.NewDateTime(time.Now().Add(time.Hour), true)},
+			}
+		}
+		return notion.Page{
+			ID: "cb261dc5-6c85-4767-8585-3852382fb466",
+			Properties: notion.DatabasePageProperties{
+				"Files": {Type: notion.DBPropTypeFiles, Files: files},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		current  notion.Page
+		expURL   string
+		expError bool
+	}{
+		{
+			name:    "unchanged",
+			current: page("a.txt", "b.txt", "c.txt"),
+			expURL:  "https://example.com/fresh/b.txt",
+		},
+		{
+			name:     "earlier file removed",
+			current:  page("b.txt", "c.txt"),
+			expError: true,
+		},
+		{
+			name:     "reordered",
+			current:  page("a.txt", "c.txt", "b.txt"),
+			expError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &notion.MockClient{
+				FindPageByIDFunc: func(ctx context.Context, id string) (notion.Page, error) {
+					return tt.current, nil
+				},
+			}
+
+			a := download.PageAttachments(page("a.txt", "b.txt", "c.txt"))[1]
+
+			d := download.New(download.Options{Client: client})
+			refreshed, err := d.Refresh(context.Background(), a)
+			if tt.expError {
+				if err == nil {
+					t.Fatalf("expected error, got: %+v", refreshed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expURL != refreshed.URL {
+				t.Errorf("URL not equal (expected: %v, got: %v)", tt.expURL, refreshed.URL)
+			}
+		})
+	}
+}
+
+func TestDownloadAll(t *testing.T) {
+	t.Parallel()
+
+	var active, maxActive int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			max := atomic.LoadInt32(&maxActive)
+			if n <= max || atomic.CompareAndSwapInt32(&maxActive, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if r.URL.Path == "/missing.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+
+	expiry := time.Now().Add(time.Hour)
+	files := []notion.File{
+		{Name: "notes.txt", Type: notion.FileTypeFile, File: &notion.FileFile{URL: srv.URL + "/a", ExpiryTime: notion.NewDateTime(expiry, true)}},
+		{Name: "notes.txt", Type: notion.FileTypeFile, File: &notion.FileFile{URL: srv.URL + "/b", ExpiryTime: notion.NewDateTime(expiry, true)}},
+		{Name: "external.txt", Type: notion.FileTypeExternal, External: &notion.FileExternal{URL: "https://example.com/external.txt"}},
+		{Name: "missing.txt", Type: notion.FileTypeFile, File: &notion.FileFile{URL: srv.URL + "/missing.txt", ExpiryTime: notion.NewDateTime(expiry, true)}},
+		{Name: "../report.txt", Type: notion.FileTypeFile, File: &notion.FileFile{URL: srv.URL + "/c", ExpiryTime: notion.NewDateTime(expiry, true)}},
+		{Name: "notes (2).txt", Type: notion.FileTypeFile, File: &notion.FileFile{URL: srv.URL + "/d", ExpiryTime: notion.NewDateTime(expiry, true)}},
+	}
+
+	dir := t.TempDir()
+	d := download.New(download.Options{Concurrency: 2})
+
+	results, err := d.DownloadAll(context.Background(), download.FileAttachments("page", "Files", files), dir)
+
+	var multiErr notion.MultiError
+	if !errors.As(err, &multiErr) || len(multiErr) != 1 {
+		t.Fatalf("expected one download error, got: %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got: %v", len(results))
+	}
+	if results[2].Err == nil {
+		t.Errorf("expected error for missing file")
+	}
+	if max := atomic.LoadInt32(&maxActive); max > 2 {
+		t.Errorf("expected at most 2 concurrent downloads, got: %v", max)
+	}
+
+	got := map[string]string{}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[entry.Name()] = string(b)
+	}
+
+	exp := map[string]string{
+		"notes.txt":         "/a",
+		"notes (2).txt":     "/b",
+		".._report.txt":     "/c",
+		"notes (2) (2).txt": "/d",
+	}
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Errorf("files not equal (-exp, +got):\n%v", diff)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 // See: https://developers.notion.com/reference/errors.
@@ -67,3 +70,95 @@ func parseErrorResponse(res *http.Response) error {
 
 	return &apiErr
 }
+
+// ValidationError is a violation of the request schema, as described by the
+// message of an API error with code `validation_error`.
+type ValidationError struct {
+	// Path is the path to the invalid value in the request, e.g.
+	// `body.children[3].paragraph.rich_text[0].text.content.length`.
+	Path string
+	// Constraint is the expected value, e.g. `≤ 2000` or `defined`.
+	Constraint string
+	// Actual is the invalid value, if reported, e.g. `2001` or `undefined`.
+	Actual string
+
+	// BlockPath holds the index of the offending block and of each of its
+	// ancestors in the request children (see WalkInfo.Path), or nil if the path
+	// doesn't point into children.
+	BlockPath []int
+	// Property is the name (or ID) of the offending page or database property,
+	// or empty if the path doesn't point into properties.
+	Property string
+}
+
+// Error implements `error`.
+func (err *ValidationError) Error() string {
+	if err.Actual == "" {
+		return fmt.Sprintf("%v should be %v", err.Path, err.Constraint)
+	}
+	return fmt.Sprintf("%v should be %v, instead was %v", err.Path, err.Constraint, err.Actual)
+}
+
+var (
+	validationMessageRegexp = regexp.MustCompile(
+		"(body(?:\\.[^.\\[\\]`]+|\\[\\d+\\])*) should (?:be )?(.+?)(?:, instead was `((?:[^`]|``)*)`)?(?:\\.\\s|\\.?$)",
+	)
+	validationPathRegexp = regexp.MustCompile(`\.([^.\[\]]+)|\[(\d+)\]`)
+)
+
+// ValidationErrors parses the message of a `validation_error` API error into
+// structured errors. Messages can describe multiple violations, e.g. when one
+// of several parameters is required. Nil is returned for other errors, and for
+// messages that don't describe violations in a known format.
+func (err *APIError) ValidationErrors() []*ValidationError {
+	if err.Code != "validation_error" {
+		return nil
+	}
+
+	var errs []*ValidationError
+
+	for _, match := range validationMessageRegexp.FindAllStringSubmatch(err.Message, -1) {
+		verr := &ValidationError{
+			Path:       match[1],
+			Constraint: strings.ReplaceAll(match[2], "`", ""),
+			Actual:     match[3],
+		}
+		verr.BlockPath, verr.Property = parseValidationPath(verr.Path)
+		errs = append(errs, verr)
+	}
+
+	return errs
+}
+
+// parseValidationPath finds the block indexes and the property name in a path
+// such as `body.children[3].toggle.children[0]` or `body.properties.Name.title`.
+func parseValidationPath(path string) (blockPath []int, property string) {
+	type segment struct {
+		name  string
+		index int
+	}
+
+	var segments []segment
+	for _, match := range validationPathRegexp.FindAllStringSubmatch(path, -1) {
+		if match[2] != "" {
+			index, _ := strconv.Atoi(match[2])
+			segments = append(segments, segment{index: index})
+		} else {
+			segments = append(segments, segment{name: match[1], index: -1})
+		}
+	}
+
+	if len(segments) >= 2 && segments[0].name == "properties" {
+		return nil, segments[1].name
+	}
+
+	// Children are nested as `children[i].<block type>.children[j]`.
+	for i := 0; i+1 < len(segments); i += 3 {
+		if segments[i].name != "children" || segments[i+1].index < 0 {
+			break
+		}
+		blockPath = append(blockPath, segments[i+1].index)
+	}
+
+	return blockPath, ""
+}
@@ -0,0 +1,105 @@
+package notion_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+)
+
+func TestAPIErrorValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		apiErr   notion.APIError
+		expected []*notion.ValidationError
+	}{
+		{
+			name: "rich text length of nested block",
+			apiErr: notion.APIError{
+				Code:    "validation_error",
+				Message: "body failed validation: body.children[3].toggle.children[1].paragraph.rich_text[0].text.content.length should be ≤ `2000`, instead was `2001`.",
+			},
+			expected: []*notion.ValidationError{
+				{
+					Path:       "body.children[3].toggle.children[1].paragraph.rich_text[0].text.content.length",
+					Constraint: "≤ 2000",
+					Actual:     "2001",
+					BlockPath:  []int{3, 1},
+				},
+			},
+		},
+		{
+			name: "property",
+			apiErr: notion.APIError{
+				Code:    "validation_error",
+				Message: "body failed validation: body.properties.Due Date.date.start should be defined, instead was `undefined`.",
+			},
+			expected: []*notion.ValidationError{
+				{
+					Path:       "body.properties.Due Date.date.start",
+					Constraint: "defined",
+					Actual:     "undefined",
+					Property:   "Due Date",
+				},
+			},
+		},
+		{
+			name: "multiple violations",
+			apiErr: notion.APIError{
+				Code:    "validation_error",
+				Message: "body failed validation. Fix one: body.parent.page_id should be defined, instead was `undefined`. body.parent.database_id should be defined, instead was `undefined`.",
+			},
+			expected: []*notion.ValidationError{
+				{Path: "body.parent.page_id", Constraint: "defined", Actual: "undefined"},
+				{Path: "body.parent.database_id", Constraint: "defined", Actual: "undefined"},
+			},
+		},
+		{
+			name: "children length",
+			apiErr: notion.APIError{
+				Code:    "validation_error",
+				Message: "body failed validation: body.children.length should be ≤ `100`, instead was `101`.",
+			},
+			expected: []*notion.ValidationError{
+				{Path: "body.children.length", Constraint: "≤ 100", Actual: "101"},
+			},
+		},
+		{
+			name: "unknown message format",
+			apiErr: notion.APIError{
+				Code:    "validation_error",
+				Message: "Invalid property identifier.",
+			},
+		},
+		{
+			name: "other error code",
+			apiErr: notion.APIError{
+				Code:    "object_not_found",
+				Message: "body.parent.page_id should be defined.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.apiErr.ValidationErrors()
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Fatalf("validation errors not equal (-exp, +got):\n%v", diff)
+			}
+
+			for _, verr := range got {
+				var target *notion.ValidationError
+				if !errors.As(fmt.Errorf("wrapped: %w", verr), &target) || target != verr {
+					t.Errorf("expected errors.As to find *notion.ValidationError")
+				}
+			}
+		})
+	}
+}
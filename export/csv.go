@@ -0,0 +1,107 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/skedida/go-notion"
+)
+
+// CSVWriter writes database pages as CSV records, with a header record of
+// property names.
+type CSVWriter struct {
+	w         *csv.Writer
+	columns   []string
+	formatter formatter
+	header    bool
+}
+
+// NewCSVWriter returns a CSVWriter that writes pages of db to w.
+func NewCSVWriter(w io.Writer, db notion.Database, opts Options) *CSVWriter {
+	return &CSVWriter{
+		w:         csv.NewWriter(w),
+		columns:   opts.columns(db),
+		formatter: newFormatter(opts),
+	}
+}
+
+// Write writes a page as CSV record, preceded by the header record for the
+// first page. Records are buffered; call Flush when done.
+func (cw *CSVWriter) Write(page notion.Page) error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(cw.columns))
+	for i, name := range cw.columns {
+		if prop, ok := page.Property(name); ok {
+			record[i] = cw.formatter.property(prop)
+		}
+	}
+
+	if err := cw.w.Write(record); err != nil {
+		return fmt.Errorf("export: failed to write CSV record: %w", err)
+	}
+
+	return nil
+}
+
+// Flush writes the header record (if no pages were written) and any buffered
+// records to the underlying writer.
+func (cw *CSVWriter) Flush() error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+
+	cw.w.Flush()
+	if err := cw.w.Error(); err != nil {
+		return fmt.Errorf("export: failed to write CSV: %w", err)
+	}
+
+	return nil
+}
+
+func (cw *CSVWriter) writeHeader() error {
+	if cw.header {
+		return nil
+	}
+	cw.header = true
+
+	if err := cw.w.Write(cw.columns); err != nil {
+		return fmt.Errorf("export: failed to write CSV header: %w", err)
+	}
+
+	return nil
+}
+
+// CSV writes pages of db to w as CSV, with a header record of property names.
+func CSV(w io.Writer, db notion.Database, pages []notion.Page, opts Options) error {
+	cw := NewCSVWriter(w, db, opts)
+
+	for _, page := range pages {
+		if err := cw.Write(page); err != nil {
+			return err
+		}
+	}
+
+	return cw.Flush()
+}
+
+// StreamCSV writes the pages of a database query to w as CSV, while iterating
+// over the query results.
+func StreamCSV(ctx context.Context, w io.Writer, db notion.Database, it *notion.DatabaseQueryIterator, opts Options) error {
+	cw := NewCSVWriter(w, db, opts)
+
+	for it.Next(ctx) {
+		if err := cw.Write(it.Page()); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("export: failed to query database: %w", err)
+	}
+
+	return cw.Flush()
+}
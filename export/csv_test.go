@@ -0,0 +1,162 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/export"
+)
+
+type mockRoundtripper struct {
+	fn func(*http.Request) (*http.Response, error)
+}
+
+func (m *mockRoundtripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	return m.fn(r)
+}
+
+func mustUnmarshal(data string, v interface{}) {
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		panic(err)
+	}
+}
+
+const testDatabaseJSON = `{
+	"id": "39ddfc9d-33c9-404c-89cf-79f01c42dd0c",
+	"properties": {
+		"Tags": {"id": "a", "type": "multi_select", "multi_select": {"options": []}},
+		"Name": {"id": "title", "type": "title", "title": {}},
+		"Price": {"id": "b", "type": "number", "number": {"format": "euro"}},
+		"Due": {"id": "c", "type": "date", "date": {}},
+		"Owner": {"id": "d", "type": "people", "people": {}},
+		"Related": {"id": "e", "type": "relation", "relation": {"database_id": "foo"}},
+		"Total": {"id": "f", "type": "formula", "formula": {"expression": "1"}},
+		"Done": {"id": "g", "type": "checkbox", "checkbox": {}},
+		"Files": {"id": "h", "type": "files", "files": {}}
+	}
+}`
+
+const testPageJSON = `{
+	"id": "7c6b1c95-de50-45ca-94e6-af1d9fd295ab",
+	"parent": {"type": "database_id", "database_id": "39ddfc9d-33c9-404c-89cf-79f01c42dd0c"},
+	"properties": {
+		"Name": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "Foo, \"bar\""}, "plain_text": "Foo, \"bar\""}]},
+		"Tags": {"id": "a", "type": "multi_select", "multi_select": [{"name": "A"}, {"name": "B"}]},
+		"Price": {"id": "b", "type": "number", "number": 12.5},
+		"Due": {"id": "c", "type": "date", "date": {"start": "2021-05-23", "end": "2021-05-25"}},
+		"Owner": {"id": "d", "type": "people", "people": [{"id": "u1", "name": "Jane", "person": {"email": "jane@example.com"}}]},
+		"Related": {"id": "e", "type": "relation", "relation": [{"id": "r1"}, {"id": "r2"}]},
+		"Total": {"id": "f", "type": "formula", "formula": {"type": "date", "date": {"start": "2021-05-23T09:00:00.000Z"}}},
+		"Done": {"id": "g", "type": "checkbox", "checkbox": true},
+		"Files": {"id": "h", "type": "files", "files": [{"name": "x", "type": "external", "external": {"url": "https://example.com/x.png"}}]}
+	}
+}`
+
+func TestCSV(t *testing.T) {
+	t.Parallel()
+
+	var db notion.Database
+	mustUnmarshal(testDatabaseJSON, &db)
+
+	var page notion.Page
+	mustUnmarshal(testPageJSON, &page)
+
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		opts   export.Options
+		pages  []notion.Page
+		expCSV string
+	}{
+		{
+			name:  "default options",
+			pages: []notion.Page{page},
+			expCSV: "Name,Done,Due,Files,Owner,Price,Related,Tags,Total\n" +
+				`"Foo, ""bar""",true,2021-05-23/2021-05-25,https://example.com/x.png,Jane,12.5,"r1, r2","A, B",2021-05-23T09:00:00Z` + "\n",
+		},
+		{
+			name: "custom options",
+			opts: export.Options{
+				Columns:        []string{"Owner", "Related", "Total", "Missing"},
+				Separator:      ";",
+				People:         export.PeopleEmail,
+				RelationTitles: map[string]string{"r1": "Baz"},
+				Location:       loc,
+				TimeFormat:     "2006-01-02 15:04",
+			},
+			pages: []notion.Page{page},
+			expCSV: "Owner,Related,Total,Missing\n" +
+				"jane@example.com,Baz;r2,2021-05-23 11:00,\n",
+		},
+		{
+			name:   "no pages",
+			opts:   export.Options{Columns: []string{"Name"}},
+			expCSV: "Name\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := export.CSV(&buf, db, tt.pages, tt.opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.expCSV, buf.String()); diff != "" {
+				t.Fatalf("CSV not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestStreamCSV(t *testing.T) {
+	t.Parallel()
+
+	var db notion.Database
+	mustUnmarshal(testDatabaseJSON, &db)
+
+	responses := []string{
+		`{"object": "list", "results": [` + testPageJSON + `], "has_more": true, "next_cursor": "A"}`,
+		`{"object": "list", "results": [` + testPageJSON + `], "has_more": false, "next_cursor": null}`,
+	}
+	calls := 0
+
+	httpClient := &http.Client{
+		Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Status:     http.StatusText(http.StatusOK),
+				Body:       ioutil.NopCloser(strings.NewReader(responses[calls-1])),
+			}, nil
+		}},
+	}
+	client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+
+	var buf bytes.Buffer
+	it := client.QueryDatabaseIterator(db.ID, nil)
+	err := export.StreamCSV(context.Background(), &buf, db, it, export.Options{Columns: []string{"Price", "Done"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := "Price,Done\n12.5,true\n12.5,true\n"
+	if diff := cmp.Diff(exp, buf.String()); diff != "" {
+		t.Fatalf("CSV not equal (-exp, +got):\n%v", diff)
+	}
+}
@@ -0,0 +1,250 @@
+// Package export writes Notion database pages to tabular file formats, with a
+// column per database property.
+//
+// The Notion API doesn't expose the column order of a database as shown in
+// Notion. By default, the title property is exported as first column, followed
+// by the other properties sorted by name. Set Options.Columns to export
+// properties in a specific order, or only some of them.
+package export
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+// PeopleFormat determines how users are rendered.
+type PeopleFormat int
+
+const (
+	// PeopleName renders users by name.
+	PeopleName PeopleFormat = iota
+	// PeopleEmail renders users by email address, if available, and by name
+	// otherwise (e.g. for bots).
+	PeopleEmail
+	// PeopleID renders users by ID.
+	PeopleID
+)
+
+// Options configure which columns are exported, and how property values are
+// formatted.
+type Options struct {
+	// Columns are the names of the properties to export, in order. Defaults to
+	// all database properties: the title property first, followed by the other
+	// properties sorted by name, as the Notion API doesn't expose the column
+	// order of a database.
+	Columns []string
+
+	// Separator is used to join multiple values, e.g. multi-select options.
+	// Defaults to ", ".
+	Separator string
+
+	// People determines how users are rendered. Defaults to PeopleName.
+	People PeopleFormat
+
+	// RelationTitles maps related page IDs to titles. Relations to pages that
+	// aren't in the map are rendered by ID.
+	RelationTitles map[string]string
+
+	// Location is used for rendering times. Defaults to UTC.
+	Location *time.Location
+
+	// TimeFormat is the layout used for times. Defaults to RFC 3339. Dates
+	// without time are always rendered as `2006-01-02`.
+	TimeFormat string
+}
+
+const dateFormat = "2006-01-02"
+
+// columns returns the properties to export, in order.
+func (opts Options) columns(db notion.Database) []string {
+	if len(opts.Columns) > 0 {
+		return opts.Columns
+	}
+
+	columns := make([]string, 0, len(db.Properties))
+	var title string
+
+	for name, prop := range db.Properties {
+		if prop.Type == notion.DBPropTypeTitle {
+			title = name
+			continue
+		}
+		columns = append(columns, name)
+	}
+
+	sort.Strings(columns)
+
+	if title != "" {
+		columns = append([]string{title}, columns...)
+	}
+
+	return columns
+}
+
+// formatter renders property values as text.
+type formatter struct {
+	opts Options
+}
+
+func newFormatter(opts Options) formatter {
+	if opts.Separator == "" {
+		opts.Separator = ", "
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.TimeFormat == "" {
+		opts.TimeFormat = time.RFC3339
+	}
+
+	return formatter{opts: opts}
+}
+
+// property renders the value of a page property.
+func (f formatter) property(prop notion.DatabasePageProperty) string {
+	return f.value(prop.Value())
+}
+
+// value renders a value as returned by notion.DatabasePageProperty.Value (and
+// the Value methods of formula and rollup results).
+func (f formatter) value(v interface{}) string {
+	switch v := v.(type) {
+	case []notion.RichText:
+		return notion.PlainText(v)
+	case *float64:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	case *notion.SelectOptions:
+		if v == nil {
+			return ""
+		}
+		return v.Name
+	case []notion.SelectOptions:
+		names := make([]string, len(v))
+		for i, option := range v {
+			names[i] = option.Name
+		}
+		return f.join(names)
+	case *notion.Date:
+		if v == nil {
+			return ""
+		}
+		return f.date(*v)
+	case []notion.User:
+		users := make([]string, len(v))
+		for i, user := range v {
+			users[i] = f.user(user)
+		}
+		return f.join(users)
+	case *notion.User:
+		if v == nil {
+			return ""
+		}
+		return f.user(*v)
+	case []notion.File:
+		urls := make([]string, len(v))
+		for i, file := range v {
+			urls[i] = fileURL(file)
+		}
+		return f.join(urls)
+	case *bool:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatBool(*v)
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case *notion.FormulaResult:
+		if v == nil {
+			return ""
+		}
+		return f.value(v.Value())
+	case *notion.RollupResult:
+		if v == nil {
+			return ""
+		}
+		return f.value(v.Value())
+	case []notion.Relation:
+		relations := make([]string, len(v))
+		for i, relation := range v {
+			relations[i] = f.relation(relation.ID)
+		}
+		return f.join(relations)
+	case []notion.DatabasePageProperty:
+		values := make([]string, 0, len(v))
+		for _, prop := range v {
+			if s := f.property(prop); s != "" {
+				values = append(values, s)
+			}
+		}
+		return f.join(values)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.In(f.opts.Location).Format(f.opts.TimeFormat)
+	default:
+		return ""
+	}
+}
+
+// date renders a date, or a date range as an ISO 8601 interval (`start/end`).
+func (f formatter) date(date notion.Date) string {
+	s := f.dateTime(date.Start)
+	if date.End != nil {
+		s += "/" + f.dateTime(*date.End)
+	}
+	return s
+}
+
+func (f formatter) dateTime(dt notion.DateTime) string {
+	if !dt.HasTime() {
+		return dt.Format(dateFormat)
+	}
+	return dt.In(f.opts.Location).Format(f.opts.TimeFormat)
+}
+
+func (f formatter) user(user notion.User) string {
+	switch f.opts.People {
+	case PeopleEmail:
+		if user.Person != nil && user.Person.Email != "" {
+			return user.Person.Email
+		}
+		return user.Name
+	case PeopleID:
+		return user.ID
+	default:
+		return user.Name
+	}
+}
+
+func (f formatter) relation(id string) string {
+	if title, ok := f.opts.RelationTitles[id]; ok {
+		return title
+	}
+	return id
+}
+
+func (f formatter) join(values []string) string {
+	return strings.Join(values, f.opts.Separator)
+}
+
+func fileURL(file notion.File) string {
+	switch {
+	case file.File != nil:
+		return file.File.URL
+	case file.External != nil:
+		return file.External.URL
+	default:
+		return file.Name
+	}
+}
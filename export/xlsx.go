@@ -0,0 +1,479 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+// Cell styles, as indexes of `cellXfs` in the stylesheet. Styles for number
+// formats are appended after these.
+const (
+	styleDefault = iota
+	styleHeader
+	styleDate
+	styleDateTime
+	numberStyleOffset
+)
+
+// Custom number format IDs start at 164; lower IDs are built-in formats.
+const customNumFmtOffset = 164
+
+const (
+	dateNumFmt     = "yyyy-mm-dd"
+	dateTimeNumFmt = "yyyy-mm-dd hh:mm:ss"
+)
+
+// excelEpoch is the zero date of Excel date serial numbers (in the 1900 date
+// system, accounting for its erroneous leap day in 1900).
+var excelEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// currencySymbols maps Notion currency number formats to their symbol.
+var currencySymbols = map[notion.NumberFormat]string{
+	notion.NumberFormatDollar:           "$",
+	notion.NumberFormatEuro:             "€",
+	notion.NumberFormatPound:            "£",
+	notion.NumberFormatPonud:            "¥",
+	notion.NumberFormatRuble:            "₽",
+	notion.NumberFormatRupee:            "₹",
+	notion.NumberFormatWon:              "₩",
+	notion.NumberFormatYuan:             "CN¥",
+	notion.NumberFormatHongKongDollar:   "HK$",
+	notion.NumberFormatNewZealandDollar: "NZ$",
+	notion.NumberFormatKrona:            "kr",
+	notion.NumberFormatNorwegianKrone:   "kr",
+	notion.NumberFormatMexicanPeso:      "MX$",
+	notion.NumberFormatRand:             "R",
+	notion.NumberFormatNewTaiwanDollar:  "NT$",
+	notion.NumberFormatDanishKrone:      "kr",
+	notion.NumberFormatZloty:            "zł",
+	notion.NumberFormatBaht:             "฿",
+	notion.NumberFormatForint:           "Ft",
+	notion.NumberFormatKoruna:           "Kč",
+	notion.NumberFormatShekel:           "₪",
+	notion.NumberFormatChileanPeso:      "CLP$",
+	notion.NumberFormatPhilippinePeso:   "₱",
+	notion.NumberFormatDirham:           "AED",
+	notion.NumberFormatColombianPeso:    "COP$",
+	notion.NumberFormatRiyal:            "SAR",
+	notion.NumberFormatRinggit:          "RM",
+	notion.NumberFormatLeu:              "lei",
+}
+
+// numFmtCode returns the Excel number format code for a Notion number format,
+// or an empty string for the General format.
+func numFmtCode(format notion.NumberFormat) string {
+	switch format {
+	case "", notion.NumberFormatNumber:
+		return ""
+	case notion.NumberFormatNumberWithCommas:
+		return "#,##0.00"
+	case notion.NumberFormatPercent:
+		return "0.00%"
+	case notion.NumberFormatPonud, notion.NumberFormatWon:
+		return fmt.Sprintf(`"%v"#,##0`, currencySymbols[format])
+	}
+
+	if symbol, ok := currencySymbols[format]; ok {
+		return fmt.Sprintf(`"%v"#,##0.00`, symbol)
+	}
+
+	return ""
+}
+
+// XLSXWriter writes database pages as rows of an Excel worksheet, with typed
+// cells and a header row of property names. Number properties are formatted
+// according to their Notion number format; dates without end are written as
+// Excel dates.
+type XLSXWriter struct {
+	zw        *zip.Writer
+	sheet     io.Writer
+	enc       *xml.Encoder
+	db        notion.Database
+	columns   []string
+	styles    []int
+	formatter formatter
+	row       int
+	err       error
+}
+
+// NewXLSXWriter returns an XLSXWriter that writes pages of db to w. The
+// worksheet is named after the database title.
+func NewXLSXWriter(w io.Writer, db notion.Database, opts Options) *XLSXWriter {
+	xw := &XLSXWriter{
+		zw:        zip.NewWriter(w),
+		db:        db,
+		columns:   opts.columns(db),
+		formatter: newFormatter(opts),
+	}
+
+	xw.err = xw.writeHeader()
+
+	return xw
+}
+
+// Write writes a page as worksheet row.
+func (xw *XLSXWriter) Write(page notion.Page) error {
+	if xw.err != nil {
+		return xw.err
+	}
+
+	xw.row++
+	row := xlsxRow{R: xw.row}
+
+	for i, name := range xw.columns {
+		prop, ok := page.Property(name)
+		if !ok {
+			continue
+		}
+
+		c := xw.cell(prop.Value(), xw.styles[i])
+		if c == nil {
+			continue
+		}
+
+		c.R = cellRef(i, xw.row)
+		row.Cells = append(row.Cells, *c)
+	}
+
+	if err := xw.enc.Encode(row); err != nil {
+		xw.err = fmt.Errorf("export: failed to write XLSX row: %w", err)
+	}
+
+	return xw.err
+}
+
+// Close finishes the worksheet and writes the XLSX file's central directory. It
+// doesn't close the underlying writer.
+func (xw *XLSXWriter) Close() error {
+	if xw.err != nil {
+		return xw.err
+	}
+
+	if err := xw.enc.Flush(); err != nil {
+		return fmt.Errorf("export: failed to write XLSX: %w", err)
+	}
+	if _, err := io.WriteString(xw.sheet, "</sheetData></worksheet>"); err != nil {
+		return fmt.Errorf("export: failed to write XLSX: %w", err)
+	}
+	if err := xw.zw.Close(); err != nil {
+		return fmt.Errorf("export: failed to write XLSX: %w", err)
+	}
+
+	return nil
+}
+
+// writeHeader writes all package parts but the worksheet, and starts the
+// worksheet with the header row.
+func (xw *XLSXWriter) writeHeader() error {
+	numFmts, styles := xw.numberStyles()
+	xw.styles = styles
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{name: "[Content_Types].xml", content: contentTypesXML},
+		{name: "_rels/.rels", content: relsXML},
+		{name: "xl/workbook.xml", content: fmt.Sprintf(workbookXML, xmlEscape(sheetName(xw.db)))},
+		{name: "xl/_rels/workbook.xml.rels", content: workbookRelsXML},
+		{name: "xl/styles.xml", content: stylesXML(numFmts)},
+	}
+
+	for _, part := range parts {
+		f, err := xw.zw.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("export: failed to write XLSX: %w", err)
+		}
+		if _, err := io.WriteString(f, xml.Header+part.content); err != nil {
+			return fmt.Errorf("export: failed to write XLSX: %w", err)
+		}
+	}
+
+	sheet, err := xw.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("export: failed to write XLSX: %w", err)
+	}
+	xw.sheet = sheet
+
+	start := xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+		`<sheetData>`
+	if _, err := io.WriteString(sheet, start); err != nil {
+		return fmt.Errorf("export: failed to write XLSX: %w", err)
+	}
+
+	xw.enc = xml.NewEncoder(sheet)
+	xw.row = 1
+
+	header := xlsxRow{R: 1}
+	for i, name := range xw.columns {
+		header.Cells = append(header.Cells, xlsxCell{
+			R:  cellRef(i, 1),
+			S:  styleHeader,
+			T:  "inlineStr",
+			IS: &xlsxInlineString{T: xlsxText{Space: "preserve", Value: name}},
+		})
+	}
+
+	if err := xw.enc.Encode(header); err != nil {
+		return fmt.Errorf("export: failed to write XLSX header: %w", err)
+	}
+
+	return nil
+}
+
+// numberStyles returns the number format codes used by number columns, and the
+// cell style of each column.
+func (xw *XLSXWriter) numberStyles() (numFmts []string, styles []int) {
+	styles = make([]int, len(xw.columns))
+	index := make(map[string]int)
+
+	for i, name := range xw.columns {
+		prop := xw.db.Properties[name]
+		if prop.Type != notion.DBPropTypeNumber || prop.Number == nil {
+			continue
+		}
+
+		code := numFmtCode(prop.Number.Format)
+		if code == "" {
+			continue
+		}
+
+		n, ok := index[code]
+		if !ok {
+			n = len(numFmts)
+			index[code] = n
+			numFmts = append(numFmts, code)
+		}
+
+		styles[i] = numberStyleOffset + n
+	}
+
+	return numFmts, styles
+}
+
+// cell returns a typed cell for a property value, or nil if the value is empty.
+func (xw *XLSXWriter) cell(v interface{}, numberStyle int) *xlsxCell {
+	switch v := v.(type) {
+	case *float64:
+		if v == nil || math.IsNaN(*v) || math.IsInf(*v, 0) {
+			return nil
+		}
+		return &xlsxCell{S: numberStyle, V: strconv.FormatFloat(*v, 'f', -1, 64)}
+	case *bool:
+		if v == nil {
+			return nil
+		}
+		value := "0"
+		if *v {
+			value = "1"
+		}
+		return &xlsxCell{T: "b", V: value}
+	case *notion.Date:
+		if v == nil {
+			return nil
+		}
+		if v.End == nil {
+			return xw.dateCell(v.Start.Time, v.Start.HasTime())
+		}
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return xw.dateCell(*v, true)
+	case *notion.FormulaResult:
+		if v == nil {
+			return nil
+		}
+		return xw.cell(v.Value(), styleDefault)
+	case *notion.RollupResult:
+		if v == nil {
+			return nil
+		}
+		if v.Type != notion.RollupResultTypeArray {
+			return xw.cell(v.Value(), styleDefault)
+		}
+	}
+
+	s := xw.formatter.value(v)
+	if s == "" {
+		return nil
+	}
+
+	return &xlsxCell{T: "inlineStr", IS: &xlsxInlineString{T: xlsxText{Space: "preserve", Value: s}}}
+}
+
+func (xw *XLSXWriter) dateCell(t time.Time, hasTime bool) *xlsxCell {
+	if !hasTime {
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return &xlsxCell{S: styleDate, V: strconv.FormatFloat(excelSerial(t), 'f', -1, 64)}
+	}
+
+	// Excel dates don't have a time zone, so times are written as wall clock
+	// time in the configured location.
+	t = t.In(xw.formatter.opts.Location)
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+
+	return &xlsxCell{S: styleDateTime, V: strconv.FormatFloat(excelSerial(wall), 'f', -1, 64)}
+}
+
+// excelSerial returns the Excel serial number of a (UTC) wall clock time: the
+// number of days since the epoch, with the time as fraction.
+func excelSerial(t time.Time) float64 {
+	d := t.Sub(excelEpoch)
+	days := math.Floor(d.Hours() / 24)
+	fraction := float64(d-time.Duration(days)*24*time.Hour) / float64(24*time.Hour)
+
+	// Round to milliseconds, to avoid floating point noise.
+	return days + math.Round(fraction*86400000)/86400000
+}
+
+// XLSX writes pages of db to w as Excel workbook.
+func XLSX(w io.Writer, db notion.Database, pages []notion.Page, opts Options) error {
+	xw := NewXLSXWriter(w, db, opts)
+
+	for _, page := range pages {
+		if err := xw.Write(page); err != nil {
+			return err
+		}
+	}
+
+	return xw.Close()
+}
+
+// StreamXLSX writes the pages of a database query to w as Excel workbook, while
+// iterating over the query results.
+func StreamXLSX(ctx context.Context, w io.Writer, db notion.Database, it *notion.DatabaseQueryIterator, opts Options) error {
+	xw := NewXLSXWriter(w, db, opts)
+
+	for it.Next(ctx) {
+		if err := xw.Write(it.Page()); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("export: failed to query database: %w", err)
+	}
+
+	return xw.Close()
+}
+
+type xlsxRow struct {
+	XMLName xml.Name   `xml:"row"`
+	R       int        `xml:"r,attr"`
+	Cells   []xlsxCell `xml:"c"`
+}
+
+type xlsxCell struct {
+	R  string            `xml:"r,attr"`
+	S  int               `xml:"s,attr,omitempty"`
+	T  string            `xml:"t,attr,omitempty"`
+	V  string            `xml:"v,omitempty"`
+	IS *xlsxInlineString `xml:"is,omitempty"`
+}
+
+type xlsxInlineString struct {
+	T xlsxText `xml:"t"`
+}
+
+type xlsxText struct {
+	Space string `xml:"xml:space,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// cellRef returns the A1-style reference of a cell, e.g. `AB12`.
+func cellRef(col, row int) string {
+	var name []byte
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = append([]byte{byte('A' + (col-1)%26)}, name...)
+	}
+	return string(name) + strconv.Itoa(row)
+}
+
+// sheetName returns a valid worksheet name for a database: at most 31
+// characters, without `[]:*?/\`.
+func sheetName(db notion.Database) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(notion.PlainText(db.Title)))
+
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if strings.Trim(name, "'") == "" {
+		return "Sheet1"
+	}
+
+	return name
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func stylesXML(numFmts []string) string {
+	var b strings.Builder
+
+	b.WriteString(`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	fmt.Fprintf(&b, `<numFmts count="%v">`, len(numFmts)+2)
+	for i, code := range append([]string{dateNumFmt, dateTimeNumFmt}, numFmts...) {
+		fmt.Fprintf(&b, `<numFmt numFmtId="%v" formatCode="%v"/>`, customNumFmtOffset+i, xmlEscape(code))
+	}
+	b.WriteString(`</numFmts>`)
+
+	b.WriteString(`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>`)
+	b.WriteString(`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>`)
+	b.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+
+	fmt.Fprintf(&b, `<cellXfs count="%v">`, numberStyleOffset+len(numFmts))
+	b.WriteString(`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>`)
+	b.WriteString(`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>`)
+	fmt.Fprintf(&b, `<xf numFmtId="%v" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`, customNumFmtOffset)
+	fmt.Fprintf(&b, `<xf numFmtId="%v" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`, customNumFmtOffset+1)
+	for i := range numFmts {
+		fmt.Fprintf(&b, `<xf numFmtId="%v" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`, customNumFmtOffset+2+i)
+	}
+	b.WriteString(`</cellXfs>`)
+
+	b.WriteString(`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>`)
+	b.WriteString(`</styleSheet>`)
+
+	return b.String()
+}
+
+const contentTypesXML = `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const relsXML = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const workbookXML = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%v" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const workbookRelsXML = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
@@ -0,0 +1,98 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/export"
+)
+
+func readZipFile(t *testing.T, zr *zip.Reader, name string) string {
+	t.Helper()
+
+	f, err := zr.Open(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// All parts must be well-formed XML.
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid XML in %v: %v", name, err)
+		}
+	}
+
+	return string(b)
+}
+
+func TestXLSX(t *testing.T) {
+	t.Parallel()
+
+	var db notion.Database
+	mustUnmarshal(testDatabaseJSON, &db)
+	db.Title = []notion.RichText{{PlainText: "Sales: 2021/Q2"}}
+
+	var page notion.Page
+	mustUnmarshal(testPageJSON, &page)
+
+	var buf bytes.Buffer
+	opts := export.Options{Columns: []string{"Name", "Price", "Done", "Due", "Total", "Tags"}}
+	if err := export.XLSX(&buf, db, []notion.Page{page}, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels"} {
+		readZipFile(t, zr, name)
+	}
+
+	workbook := readZipFile(t, zr, "xl/workbook.xml")
+	if !strings.Contains(workbook, `<sheet name="Sales 2021Q2"`) {
+		t.Fatalf("expected sanitized sheet name, got: %v", workbook)
+	}
+
+	styles := readZipFile(t, zr, "xl/styles.xml")
+	if !strings.Contains(styles, `<numFmt numFmtId="166" formatCode="&#34;€&#34;#,##0.00"/>`) {
+		t.Fatalf("expected euro number format, got: %v", styles)
+	}
+
+	sheet := readZipFile(t, zr, "xl/worksheets/sheet1.xml")
+
+	expCells := []string{
+		// Bold header.
+		`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Name</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">Foo, &#34;bar&#34;</t></is></c>`,
+		// Number with euro format.
+		`<c r="B2" s="4"><v>12.5</v></c>`,
+		`<c r="C2" t="b"><v>1</v></c>`,
+		// Date range as text.
+		`<c r="D2" t="inlineStr"><is><t xml:space="preserve">2021-05-23/2021-05-25</t></is></c>`,
+		// Formula date with time: 2021-05-23 09:00 UTC.
+		`<c r="E2" s="3"><v>44339.375</v></c>`,
+		`<c r="F2" t="inlineStr"><is><t xml:space="preserve">A, B</t></is></c>`,
+	}
+
+	for _, cell := range expCells {
+		if !strings.Contains(sheet, cell) {
+			t.Fatalf("expected cell %v in sheet: %v", cell, sheet)
+		}
+	}
+}
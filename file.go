@@ -9,9 +9,18 @@ type FileExternal struct {
 	URL string `json:"url"`
 }
 
+// FileUploadFile references a file upload, to attach an uploaded file to a
+// block, icon, cover or files property. Once attached, the file is returned as
+// a FileFile.
+// See: https://developers.notion.com/docs/uploading-small-files#step-3-attach-the-file-upload
+type FileUploadFile struct {
+	ID string `json:"id"`
+}
+
 type FileType string
 
 const (
-	FileTypeFile     FileType = "file"
-	FileTypeExternal FileType = "external"
+	FileTypeFile       FileType = "file"
+	FileTypeExternal   FileType = "external"
+	FileTypeFileUpload FileType = "file_upload"
 )
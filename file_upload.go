@@ -0,0 +1,186 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// MaxSinglePartUploadSize is the maximum size of a file that is uploaded in
+	// one part.
+	MaxSinglePartUploadSize = 20 << 20
+
+	// UploadPartSize is the size of the parts (except the last one) of files
+	// uploaded by UploadFile in multiple parts.
+	UploadPartSize = 10 << 20
+)
+
+type (
+	FileUploadStatus string
+	FileUploadMode   string
+)
+
+const (
+	FileUploadStatusPending  FileUploadStatus = "pending"
+	FileUploadStatusUploaded FileUploadStatus = "uploaded"
+	FileUploadStatusExpired  FileUploadStatus = "expired"
+	FileUploadStatusFailed   FileUploadStatus = "failed"
+
+	FileUploadModeSinglePart  FileUploadMode = "single_part"
+	FileUploadModeMultiPart   FileUploadMode = "multi_part"
+	FileUploadModeExternalURL FileUploadMode = "external_url"
+)
+
+// FileUpload is a file that is uploaded to Notion, to be attached to blocks,
+// icons, covers or files properties using a FileUploadFile with its ID.
+// See: https://developers.notion.com/reference/file-upload
+type FileUpload struct {
+	ID             string           `json:"id"`
+	CreatedTime    time.Time        `json:"created_time"`
+	CreatedBy      *BaseUser        `json:"created_by,omitempty"`
+	LastEditedTime time.Time        `json:"last_edited_time"`
+	ExpiryTime     *time.Time       `json:"expiry_time"`
+	Archived       bool             `json:"archived"`
+	Status         FileUploadStatus `json:"status"`
+	Filename       *string          `json:"filename"`
+	ContentType    *string          `json:"content_type"`
+	ContentLength  *int64           `json:"content_length"`
+	UploadURL      string           `json:"upload_url,omitempty"`
+	CompleteURL    string           `json:"complete_url,omitempty"`
+	NumberOfParts  *FileUploadParts `json:"number_of_parts,omitempty"`
+
+	FileImportResult *FileImportResult `json:"file_import_result,omitempty"`
+}
+
+// FileUploadParts is the progress of a multi-part upload.
+type FileUploadParts struct {
+	Total     int `json:"total"`
+	SentCount int `json:"sent_count"`
+}
+
+// FileImportResult is the result of importing a file from an external URL.
+type FileImportResult struct {
+	ImportedTime time.Time `json:"imported_time"`
+	Type         string    `json:"type"`
+	Error        *struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// CreateFileUploadParams are the params used for creating a file upload.
+type CreateFileUploadParams struct {
+	// Mode defaults to single part.
+	Mode        FileUploadMode `json:"mode,omitempty"`
+	Filename    string         `json:"filename,omitempty"`
+	ContentType string         `json:"content_type,omitempty"`
+
+	// NumberOfParts is required for multi-part uploads.
+	NumberOfParts int `json:"number_of_parts,omitempty"`
+
+	// ExternalURL is the URL of the file to import, for external URL uploads.
+	ExternalURL string `json:"external_url,omitempty"`
+}
+
+// Validate validates params for creating a file upload.
+func (p CreateFileUploadParams) Validate() error {
+	switch p.Mode {
+	case "", FileUploadModeSinglePart:
+		if p.NumberOfParts != 0 {
+			return errors.New("number of parts is only allowed for multi-part uploads")
+		}
+	case FileUploadModeMultiPart:
+		if p.NumberOfParts < 1 {
+			return errors.New("number of parts is required for multi-part uploads")
+		}
+		if p.Filename == "" {
+			return errors.New("filename is required for multi-part uploads")
+		}
+	case FileUploadModeExternalURL:
+		if p.ExternalURL == "" {
+			return errors.New("external URL is required for external URL uploads")
+		}
+		if p.Filename == "" {
+			return errors.New("filename is required for external URL uploads")
+		}
+	default:
+		return fmt.Errorf("unknown file upload mode %q", p.Mode)
+	}
+
+	return nil
+}
+
+// SendFileUploadParams are the params used for sending the content of a file
+// upload, or of one part of a multi-part upload.
+type SendFileUploadParams struct {
+	// Content is read until EOF, and streamed as request body.
+	Content io.Reader
+
+	// Filename and ContentType describe the content. ContentType defaults to
+	// "application/octet-stream". The API requires it to match the content
+	// type of the file upload, so it must be set if the upload has one.
+	Filename    string
+	ContentType string
+
+	// PartNumber is the 1-based number of the part, for multi-part uploads.
+	PartNumber int
+}
+
+// Validate validates params for sending a file upload.
+func (p SendFileUploadParams) Validate() error {
+	if p.Content == nil {
+		return errors.New("content is required")
+	}
+	if p.PartNumber < 0 {
+		return errors.New("part number cannot be negative")
+	}
+
+	return nil
+}
+
+// FindFileUploadsQuery is used when listing file uploads.
+type FindFileUploadsQuery struct {
+	Status      FileUploadStatus
+	StartCursor string
+	PageSize    int
+}
+
+// FindFileUploadsResponse contains results (file uploads) and pagination data
+// returned from a list request.
+type FindFileUploadsResponse struct {
+	Results    []FileUpload `json:"results"`
+	HasMore    bool         `json:"has_more"`
+	NextCursor *string      `json:"next_cursor"`
+}
+
+// UploadFileParams are the params used for uploading a file with UploadFile.
+type UploadFileParams struct {
+	Filename string
+	// ContentType is optional; by default, Notion infers it from the filename.
+	ContentType string
+	Content     io.Reader
+
+	// Size is the size of the content in bytes. Files larger than
+	// MaxSinglePartUploadSize are uploaded in parts of UploadPartSize. If the
+	// size is unknown (0), the content is sent in a single part, which the API
+	// rejects for content larger than MaxSinglePartUploadSize.
+	Size int64
+}
+
+// Validate validates params for uploading a file.
+func (p UploadFileParams) Validate() error {
+	if p.Filename == "" {
+		return errors.New("filename is required")
+	}
+	if p.Content == nil {
+		return errors.New("content is required")
+	}
+	if p.Size < 0 {
+		return errors.New("size cannot be negative")
+	}
+
+	return nil
+}
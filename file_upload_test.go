@@ -0,0 +1,216 @@
+package notion_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+)
+
+func TestUploadFile(t *testing.T) {
+	t.Parallel()
+
+	const uploadID = "43833259-72ae-404e-8441-b6577f3159b4"
+
+	type sentPart struct {
+		PartNumber  string
+		Filename    string
+		ContentType string
+		Size        int
+	}
+
+	tests := []struct {
+		name        string
+		size        int
+		contentType string
+		expCreate   map[string]interface{}
+		expParts    []sentPart
+		expComplete bool
+	}{
+		{
+			name:        "single part",
+			size:        1024,
+			contentType: "application/pdf",
+			expCreate: map[string]interface{}{
+				"filename":     "report.pdf",
+				"content_type": "application/pdf",
+			},
+			expParts: []sentPart{
+				{Filename: "report.pdf", ContentType: "application/pdf", Size: 1024},
+			},
+		},
+		{
+			name:        "content type of upload",
+			size:        1024,
+			contentType: "",
+			expCreate: map[string]interface{}{
+				"filename": "report.pdf",
+			},
+			expParts: []sentPart{
+				{Filename: "report.pdf", ContentType: "application/pdf", Size: 1024},
+			},
+		},
+		{
+			name:        "multiple parts",
+			size:        notion.MaxSinglePartUploadSize + 1,
+			contentType: "application/pdf",
+			expCreate: map[string]interface{}{
+				"mode":            "multi_part",
+				"filename":        "report.pdf",
+				"content_type":    "application/pdf",
+				"number_of_parts": float64(3),
+			},
+			expParts: []sentPart{
+				{PartNumber: "1", Filename: "report.pdf", ContentType: "application/pdf", Size: notion.UploadPartSize},
+				{PartNumber: "2", Filename: "report.pdf", ContentType: "application/pdf", Size: notion.UploadPartSize},
+				{PartNumber: "3", Filename: "report.pdf", ContentType: "application/pdf", Size: 1},
+			},
+			expComplete: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				create   map[string]interface{}
+				parts    []sentPart
+				complete bool
+			)
+
+			httpClient := &http.Client{
+				Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+					switch r.URL.Path {
+					case "/v1/file_uploads":
+						if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+							t.Fatal(err)
+						}
+					case fmt.Sprintf("/v1/file_uploads/%v/send", uploadID):
+						_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+						if err != nil {
+							t.Fatal(err)
+						}
+						form, err := multipart.NewReader(r.Body, params["boundary"]).ReadForm(1 << 20)
+						if err != nil {
+							t.Fatal(err)
+						}
+						file := form.File["file"][0]
+						part := sentPart{
+							Filename:    file.Filename,
+							ContentType: file.Header.Get("Content-Type"),
+							Size:        int(file.Size),
+						}
+						if values := form.Value["part_number"]; len(values) > 0 {
+							part.PartNumber = values[0]
+						}
+						parts = append(parts, part)
+						form.RemoveAll()
+					case fmt.Sprintf("/v1/file_uploads/%v/complete", uploadID):
+						complete = true
+					default:
+						t.Fatalf("unexpected request: %v %v", r.Method, r.URL.Path)
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Status:     http.StatusText(http.StatusOK),
+						Body:       io.NopCloser(strings.NewReader(`{"object": "file_upload", "id": "` + uploadID + `", "status": "uploaded", "content_type": "application/pdf"}`)),
+					}, nil
+				}},
+			}
+			client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+
+			upload, err := client.UploadFile(context.Background(), notion.UploadFileParams{
+				Filename:    "report.pdf",
+				ContentType: tt.contentType,
+				Content:     bytes.NewReader(make([]byte, tt.size)),
+				Size:        int64(tt.size),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if exp, got := notion.FileUploadStatusUploaded, upload.Status; exp != got {
+				t.Errorf("status not equal (expected: %v, got: %v)", exp, got)
+			}
+			if diff := cmp.Diff(tt.expCreate, create); diff != "" {
+				t.Errorf("create params not equal (-exp, +got):\n%v", diff)
+			}
+			if diff := cmp.Diff(tt.expParts, parts); diff != "" {
+				t.Errorf("sent parts not equal (-exp, +got):\n%v", diff)
+			}
+			if tt.expComplete != complete {
+				t.Errorf("complete not equal (expected: %v, got: %v)", tt.expComplete, complete)
+			}
+		})
+	}
+}
+
+func TestUploadFileShortContent(t *testing.T) {
+	t.Parallel()
+
+	var sent, completed int
+
+	httpClient := &http.Client{
+		Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/send"):
+				sent++
+			case strings.HasSuffix(r.URL.Path, "/complete"):
+				completed++
+			}
+			io.Copy(io.Discard, r.Body)
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Status:     http.StatusText(http.StatusOK),
+				Body:       io.NopCloser(strings.NewReader(`{"object": "file_upload", "id": "43833259-72ae-404e-8441-b6577f3159b4", "status": "pending"}`)),
+			}, nil
+		}},
+	}
+	client := notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient))
+
+	_, err := client.UploadFile(context.Background(), notion.UploadFileParams{
+		Filename: "report.pdf",
+		Content:  bytes.NewReader(make([]byte, 2*notion.UploadPartSize)),
+		Size:     2*notion.UploadPartSize + 1,
+	})
+	if err == nil || !strings.Contains(err.Error(), "content is shorter than size") {
+		t.Fatalf("expected error for short content, got: %v", err)
+	}
+
+	if sent != 2 {
+		t.Errorf("sent parts not equal (expected: 2, got: %v)", sent)
+	}
+	if completed != 0 {
+		t.Errorf("expected upload not to be completed")
+	}
+}
+
+func TestFileUploadAttachment(t *testing.T) {
+	t.Parallel()
+
+	b, err := json.Marshal(notion.ImageBlock{
+		Type:       notion.FileTypeFileUpload,
+		FileUpload: &notion.FileUploadFile{ID: "43833259-72ae-404e-8441-b6577f3159b4"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := `{"image":{"type":"file_upload","file_upload":{"id":"43833259-72ae-404e-8441-b6577f3159b4"}}}`
+	if diff := cmp.Diff(exp, string(b)); diff != "" {
+		t.Errorf("JSON not equal (-exp, +got):\n%v", diff)
+	}
+}
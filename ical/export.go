@@ -0,0 +1,224 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/skedida/go-notion"
+)
+
+// maxLineLength is the maximum length of a content line in octets, excluding
+// the line break.
+const maxLineLength = 75
+
+// ExportOptions configure how database pages are converted to events.
+type ExportOptions struct {
+	// DateProperty is the name of the date property used for the event date.
+	// Date, formula and rollup (with date result), created time and last edited
+	// time properties are supported. Required.
+	DateProperty string
+
+	// DescriptionProperty is the name of an optional rich text property, used
+	// for the event description.
+	DescriptionProperty string
+
+	// CalendarName is an optional display name for the calendar.
+	CalendarName string
+
+	// ProdID is the product identifier of the calendar. Defaults to DefaultProdID.
+	ProdID string
+}
+
+// PageEvent converts a database page to an event. The page title is used as
+// summary, and the page URL as URL. The event UID is derived from the page ID.
+// If the page doesn't have a value for the date property, false is returned.
+func PageEvent(page notion.Page, opts ExportOptions) (Event, bool) {
+	prop, ok := page.Property(opts.DateProperty)
+	if !ok {
+		return Event{}, false
+	}
+
+	date, ok := propertyDate(prop)
+	if !ok {
+		return Event{}, false
+	}
+
+	event := Event{
+		UID:          page.ID + "@notion.so",
+		Summary:      page.Title(),
+		URL:          page.URL,
+		Date:         date,
+		Created:      page.CreatedTime,
+		LastModified: page.LastEditedTime,
+	}
+
+	if opts.DescriptionProperty != "" {
+		if prop, ok := page.Property(opts.DescriptionProperty); ok {
+			event.Description = notion.PlainText(prop.RichText)
+		}
+	}
+
+	return event, true
+}
+
+// Export writes an iCalendar feed to w, with an event for each page that has a
+// value for the date property. Archived pages are skipped.
+func Export(w io.Writer, pages []notion.Page, opts ExportOptions) error {
+	if opts.DateProperty == "" {
+		return fmt.Errorf("ical: date property is required")
+	}
+
+	events := make([]Event, 0, len(pages))
+	for _, page := range pages {
+		if page.Archived {
+			continue
+		}
+		if event, ok := PageEvent(page, opts); ok {
+			events = append(events, event)
+		}
+	}
+
+	return Encode(w, events, opts)
+}
+
+// Encode writes an iCalendar feed with events to w. Only the CalendarName and
+// ProdID options are used.
+//
+// Times are written in UTC. All-day events are written as dates, with an
+// exclusive end date (DTEND) as required by RFC 5545.
+func Encode(w io.Writer, events []Event, opts ExportOptions) error {
+	prodID := opts.ProdID
+	if prodID == "" {
+		prodID = DefaultProdID
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := encoder{w: bw}
+
+	enc.line("BEGIN:VCALENDAR")
+	enc.line("VERSION:2.0")
+	enc.line("PRODID:" + escapeText(prodID))
+	enc.line("CALSCALE:GREGORIAN")
+	if opts.CalendarName != "" {
+		enc.line("X-WR-CALNAME:" + escapeText(opts.CalendarName))
+	}
+
+	for _, event := range events {
+		enc.event(event)
+	}
+
+	enc.line("END:VCALENDAR")
+
+	if enc.err != nil {
+		return fmt.Errorf("ical: failed to write calendar: %w", enc.err)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("ical: failed to write calendar: %w", err)
+	}
+
+	return nil
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (enc *encoder) event(event Event) {
+	stamp := event.LastModified
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	enc.line("BEGIN:VEVENT")
+	enc.line("UID:" + escapeText(event.UID))
+	enc.line("DTSTAMP:" + stamp.UTC().Format(utcFormat))
+
+	if event.Date.HasTime() {
+		start, end := event.Date.Bounds(time.UTC)
+		enc.line("DTSTART:" + start.UTC().Format(utcFormat))
+		if !end.Equal(start) {
+			enc.line("DTEND:" + end.UTC().Format(utcFormat))
+		}
+	} else {
+		start, end := event.Date.Bounds(time.UTC)
+		enc.line("DTSTART;VALUE=DATE:" + start.Format(dateFormat))
+		enc.line("DTEND;VALUE=DATE:" + end.Format(dateFormat))
+	}
+
+	if event.Summary != "" {
+		enc.line("SUMMARY:" + escapeText(event.Summary))
+	}
+	if event.Description != "" {
+		enc.line("DESCRIPTION:" + escapeText(event.Description))
+	}
+	if event.URL != "" {
+		enc.line("URL:" + event.URL)
+	}
+	if !event.Created.IsZero() {
+		enc.line("CREATED:" + event.Created.UTC().Format(utcFormat))
+	}
+	if !event.LastModified.IsZero() {
+		enc.line("LAST-MODIFIED:" + event.LastModified.UTC().Format(utcFormat))
+	}
+
+	enc.line("END:VEVENT")
+}
+
+// line writes a content line, folded at 75 octets without splitting UTF-8
+// encoded characters.
+func (enc *encoder) line(s string) {
+	if enc.err != nil {
+		return
+	}
+
+	limit := maxLineLength
+	for len(s) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(s[i]) {
+			i--
+		}
+		if _, enc.err = enc.w.WriteString(s[:i] + "\r\n "); enc.err != nil {
+			return
+		}
+		s = s[i:]
+		// Continuation lines start with a space, which counts towards the limit.
+		limit = maxLineLength - 1
+	}
+
+	_, enc.err = enc.w.WriteString(s + "\r\n")
+}
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func propertyDate(prop notion.DatabasePageProperty) (notion.Date, bool) {
+	switch prop.Type {
+	case notion.DBPropTypeDate:
+		if prop.Date != nil {
+			return *prop.Date, true
+		}
+	case notion.DBPropTypeFormula:
+		if prop.Formula != nil && prop.Formula.Date != nil {
+			return *prop.Formula.Date, true
+		}
+	case notion.DBPropTypeRollup:
+		if prop.Rollup != nil && prop.Rollup.Date != nil {
+			return *prop.Rollup.Date, true
+		}
+	case notion.DBPropTypeCreatedTime:
+		if prop.CreatedTime != nil {
+			return notion.Date{Start: notion.NewDateTime(*prop.CreatedTime, true)}, true
+		}
+	case notion.DBPropTypeLastEditedTime:
+		if prop.LastEditedTime != nil {
+			return notion.Date{Start: notion.NewDateTime(*prop.LastEditedTime, true)}, true
+		}
+	}
+
+	return notion.Date{}, false
+}
@@ -0,0 +1,57 @@
+// Package ical converts Notion database pages to and from iCalendar (RFC 5545)
+// events, so that date-bearing databases can be subscribed to from calendar
+// apps, and calendar feeds can be imported into databases.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc5545
+package ical
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+// DefaultProdID is the product identifier used for exported calendars.
+const DefaultProdID = "-//go-notion//ical//EN"
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405"
+	utcFormat      = "20060102T150405Z"
+)
+
+// ErrInvalidCalendar is used when iCalendar data can't be parsed.
+var ErrInvalidCalendar = errors.New("ical: invalid calendar")
+
+// Event is a calendar event (VEVENT).
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+
+	// Date is the date (range) of the event. A date without time is an all-day
+	// event. In contrast to iCalendar, the end of an all-day range is inclusive.
+	Date notion.Date
+
+	Created      time.Time
+	LastModified time.Time
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
@@ -0,0 +1,225 @@
+package ical_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/ical"
+)
+
+func mustUnmarshalPage(data string) notion.Page {
+	var page notion.Page
+	if err := json.Unmarshal([]byte(data), &page); err != nil {
+		panic(err)
+	}
+	return page
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	pages := []notion.Page{
+		mustUnmarshalPage(`{
+			"id": "be6bb2a0-5d0e-4a86-8e40-0a7b6d4f0c01",
+			"created_time": "2021-05-01T10:00:00.000Z",
+			"last_edited_time": "2021-05-02T10:00:00.000Z",
+			"url": "https://www.notion.so/Launch-be6bb2a05d0e4a868e400a7b6d4f0c01",
+			"parent": {"type": "database_id", "database_id": "39ddfc9d-33c9-404c-89cf-79f01c42dd0c"},
+			"properties": {
+				"Name": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "Launch; v1, final"}, "plain_text": "Launch; v1, final"}]},
+				"Due": {"id": "abc", "type": "date", "date": {"start": "2021-05-23", "end": "2021-05-25"}},
+				"Notes": {"id": "def", "type": "rich_text", "rich_text": [{"type": "text", "text": {"content": "Line 1\nLine 2"}, "plain_text": "Line 1\nLine 2"}]}
+			}
+		}`),
+		mustUnmarshalPage(`{
+			"id": "be6bb2a0-5d0e-4a86-8e40-0a7b6d4f0c02",
+			"created_time": "2021-05-01T10:00:00.000Z",
+			"last_edited_time": "2021-05-02T10:00:00.000Z",
+			"url": "https://www.notion.so/Meeting",
+			"parent": {"type": "database_id", "database_id": "39ddfc9d-33c9-404c-89cf-79f01c42dd0c"},
+			"properties": {
+				"Name": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "Meeting"}, "plain_text": "Meeting"}]},
+				"Due": {"id": "abc", "type": "date", "date": {"start": "2021-05-24T09:00:00", "end": "2021-05-24T10:30:00", "time_zone": "Europe/Amsterdam"}}
+			}
+		}`),
+		mustUnmarshalPage(`{
+			"id": "be6bb2a0-5d0e-4a86-8e40-0a7b6d4f0c03",
+			"parent": {"type": "database_id", "database_id": "39ddfc9d-33c9-404c-89cf-79f01c42dd0c"},
+			"properties": {
+				"Name": {"id": "title", "type": "title", "title": []},
+				"Due": {"id": "abc", "type": "date", "date": null}
+			}
+		}`),
+	}
+
+	var buf bytes.Buffer
+	err := ical.Export(&buf, pages, ical.ExportOptions{
+		DateProperty:        "Due",
+		DescriptionProperty: "Notes",
+		CalendarName:        "Deadlines",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//go-notion//ical//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Deadlines",
+		"BEGIN:VEVENT",
+		"UID:be6bb2a0-5d0e-4a86-8e40-0a7b6d4f0c01@notion.so",
+		"DTSTAMP:20210502T100000Z",
+		"DTSTART;VALUE=DATE:20210523",
+		"DTEND;VALUE=DATE:20210526",
+		`SUMMARY:Launch\; v1\, final`,
+		`DESCRIPTION:Line 1\nLine 2`,
+		"URL:https://www.notion.so/Launch-be6bb2a05d0e4a868e400a7b6d4f0c01",
+		"CREATED:20210501T100000Z",
+		"LAST-MODIFIED:20210502T100000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:be6bb2a0-5d0e-4a86-8e40-0a7b6d4f0c02@notion.so",
+		"DTSTAMP:20210502T100000Z",
+		"DTSTART:20210524T070000Z",
+		"DTEND:20210524T083000Z",
+		"SUMMARY:Meeting",
+		"URL:https://www.notion.so/Meeting",
+		"CREATED:20210501T100000Z",
+		"LAST-MODIFIED:20210502T100000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	if diff := cmp.Diff(exp, buf.String()); diff != "" {
+		t.Fatalf("calendar not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestExportFolding(t *testing.T) {
+	t.Parallel()
+
+	summary := strings.Repeat("é", 100)
+	event := ical.Event{
+		UID:          "foo",
+		Summary:      summary,
+		Date:         notion.Date{Start: notion.NewDateTime(time.Date(2021, 5, 23, 0, 0, 0, 0, time.UTC), false)},
+		LastModified: time.Date(2021, 5, 23, 0, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, []ical.Event{event}, ical.ExportOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("line exceeds 75 octets: %q", line)
+		}
+	}
+
+	events, err := ical.Decode(&buf, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].Summary != summary {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	cal := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:1",
+		"DTSTART;VALUE=DATE:20210523",
+		"DTEND;VALUE=DATE:20210526",
+		`SUMMARY:Launch\, v1`,
+		`DESCRIPTION:Line 1\nLine 2`,
+		"URL:https://example.com",
+		"BEGIN:VALARM",
+		"DESCRIPTION:Reminder",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:2",
+		`DTSTART;TZID="Europe/Amsterdam":20210524T090000`,
+		"DURATION:PT1H30M",
+		"SUMMARY:Meeting in Amster",
+		" dam",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:3",
+		"DTSTART;VALUE=DATE:20210527",
+		"DTEND;VALUE=DATE:20210528",
+		"SUMMARY:All day",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	params, err := ical.Import(strings.NewReader(cal), ical.ImportOptions{
+		DatabaseID:          "39ddfc9d-33c9-404c-89cf-79f01c42dd0c",
+		DateProperty:        "Due",
+		DescriptionProperty: "Notes",
+		URLProperty:         "Link",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, p := range params {
+		b, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, string(b))
+	}
+
+	exp := []string{
+		`{"parent":{"database_id":"39ddfc9d-33c9-404c-89cf-79f01c42dd0c"},"properties":{"Due":{"date":{"start":"2021-05-23","end":"2021-05-25"}},"Link":{"url":"https://example.com"},"Name":{"title":[{"type":"text","text":{"content":"Launch, v1"}}]},"Notes":{"rich_text":[{"type":"text","text":{"content":"Line 1\nLine 2"}}]}}}`,
+		`{"parent":{"database_id":"39ddfc9d-33c9-404c-89cf-79f01c42dd0c"},"properties":{"Due":{"date":{"start":"2021-05-24T09:00:00","end":"2021-05-24T10:30:00","time_zone":"Europe/Amsterdam"}},"Name":{"title":[{"type":"text","text":{"content":"Meeting in Amsterdam"}}]}}}`,
+		`{"parent":{"database_id":"39ddfc9d-33c9-404c-89cf-79f01c42dd0c"},"properties":{"Due":{"date":{"start":"2021-05-27"}},"Name":{"title":[{"type":"text","text":{"content":"All day"}}]}}}`,
+	}
+
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Fatalf("params not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cal  string
+	}{
+		{name: "missing DTSTART", cal: "BEGIN:VEVENT\r\nUID:1\r\nEND:VEVENT\r\n"},
+		{name: "invalid date", cal: "BEGIN:VEVENT\r\nDTSTART:2021\r\nEND:VEVENT\r\n"},
+		{name: "invalid line", cal: "BEGIN:VEVENT\r\nfoo\r\nEND:VEVENT\r\n"},
+		{name: "unterminated", cal: "BEGIN:VEVENT\r\nDTSTART:20210523\r\n"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ical.Decode(strings.NewReader(tt.cal), nil)
+			if !errors.Is(err, ical.ErrInvalidCalendar) {
+				t.Fatalf("expected ErrInvalidCalendar, got: %v", err)
+			}
+		})
+	}
+}
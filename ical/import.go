@@ -0,0 +1,373 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+var durationRegexp = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ImportOptions configure how events are converted to database pages.
+type ImportOptions struct {
+	// DatabaseID is the ID of the database pages are created in. Required.
+	DatabaseID string
+
+	// TitleProperty is the name of the database title property, used for the
+	// event summary. Defaults to `Name`.
+	TitleProperty string
+
+	// DateProperty is the name of the date property, used for the event date.
+	// Required.
+	DateProperty string
+
+	// DescriptionProperty is the name of an optional rich text property, used
+	// for the event description.
+	DescriptionProperty string
+
+	// URLProperty is the name of an optional URL property, used for the event URL.
+	URLProperty string
+
+	// Location is used for event times without UTC offset or time zone
+	// ("floating" times). Defaults to UTC.
+	Location *time.Location
+}
+
+// Import reads an iCalendar feed from r, and returns params for creating a
+// database page for each event.
+func Import(r io.Reader, opts ImportOptions) ([]notion.CreatePageParams, error) {
+	if opts.DatabaseID == "" || opts.DateProperty == "" {
+		return nil, fmt.Errorf("ical: database ID and date property are required")
+	}
+
+	events, err := Decode(r, opts.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]notion.CreatePageParams, len(events))
+	for i, event := range events {
+		params[i] = event.CreatePageParams(opts)
+	}
+
+	return params, nil
+}
+
+// CreatePageParams returns params for creating a database page for the event.
+func (e Event) CreatePageParams(opts ImportOptions) notion.CreatePageParams {
+	titleProperty := opts.TitleProperty
+	if titleProperty == "" {
+		titleProperty = "Name"
+	}
+
+	date := e.Date
+	props := notion.DatabasePageProperties{
+		titleProperty: notion.DatabasePageProperty{
+			Title: notion.SplitRichText(e.Summary),
+		},
+		opts.DateProperty: notion.DatabasePageProperty{
+			Date: &date,
+		},
+	}
+
+	if opts.DescriptionProperty != "" && e.Description != "" {
+		props[opts.DescriptionProperty] = notion.DatabasePageProperty{
+			RichText: notion.SplitRichText(e.Description),
+		}
+	}
+	if opts.URLProperty != "" && e.URL != "" {
+		url := e.URL
+		props[opts.URLProperty] = notion.DatabasePageProperty{
+			URL: &url,
+		}
+	}
+
+	return notion.CreatePageParams{
+		ParentType:             notion.ParentTypeDatabase,
+		ParentID:               opts.DatabaseID,
+		DatabasePageProperties: &props,
+	}
+}
+
+// Decode reads the events (VEVENT components) of an iCalendar feed. Times
+// without UTC offset or time zone are interpreted in loc, or UTC if loc is nil.
+// Events with a time zone (TZID) get a date with that time zone.
+func Decode(r io.Reader, loc *time.Location) ([]Event, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events []Event
+		event  *rawEvent
+		depth  int
+	)
+
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		prop, err := parseContentLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %v: %v", ErrInvalidCalendar, i+1, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			if event != nil {
+				// Nested components, e.g. VALARM, are ignored.
+				depth++
+			} else if strings.EqualFold(prop.value, "VEVENT") {
+				event = &rawEvent{props: map[string]contentLine{}}
+			}
+			continue
+		case "END":
+			if event == nil {
+				continue
+			}
+			if depth > 0 {
+				depth--
+				continue
+			}
+			e, err := event.decode(loc)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %v: %v", ErrInvalidCalendar, i+1, err)
+			}
+			events = append(events, e)
+			event = nil
+			continue
+		}
+
+		if event != nil && depth == 0 {
+			if _, ok := event.props[prop.name]; !ok {
+				event.props[prop.name] = prop
+			}
+		}
+	}
+
+	if event != nil {
+		return nil, fmt.Errorf("%w: unterminated VEVENT", ErrInvalidCalendar)
+	}
+
+	return events, nil
+}
+
+type contentLine struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+type rawEvent struct {
+	props map[string]contentLine
+}
+
+func (e rawEvent) decode(loc *time.Location) (Event, error) {
+	event := Event{
+		UID:         e.props["UID"].value,
+		Summary:     unescapeText(e.props["SUMMARY"].value),
+		Description: unescapeText(e.props["DESCRIPTION"].value),
+		URL:         e.props["URL"].value,
+	}
+
+	if prop, ok := e.props["CREATED"]; ok {
+		event.Created, _ = time.Parse(utcFormat, prop.value)
+	}
+	if prop, ok := e.props["LAST-MODIFIED"]; ok {
+		event.LastModified, _ = time.Parse(utcFormat, prop.value)
+	}
+
+	dtstart, ok := e.props["DTSTART"]
+	if !ok {
+		return Event{}, fmt.Errorf("missing DTSTART in VEVENT %q", event.UID)
+	}
+
+	start, hasTime, timeZone, err := parseDateTime(dtstart, loc)
+	if err != nil {
+		return Event{}, err
+	}
+
+	var end time.Time
+
+	if dtend, ok := e.props["DTEND"]; ok {
+		if end, _, _, err = parseDateTime(dtend, loc); err != nil {
+			return Event{}, err
+		}
+	} else if duration, ok := e.props["DURATION"]; ok {
+		if end, err = addDuration(start, duration.value); err != nil {
+			return Event{}, err
+		}
+	}
+
+	event.Date = notion.Date{Start: notion.NewDateTime(start, hasTime)}
+
+	if timeZone != "" {
+		event.Date.TimeZone = &timeZone
+	}
+
+	if hasTime {
+		if !end.IsZero() && end.After(start) {
+			endDT := notion.NewDateTime(end.In(start.Location()), true)
+			event.Date.End = &endDT
+		}
+		return event, nil
+	}
+
+	// The end date of all-day events is exclusive in iCalendar.
+	if !end.IsZero() {
+		if end = end.AddDate(0, 0, -1); end.After(start) {
+			endDT := notion.NewDateTime(end, false)
+			event.Date.End = &endDT
+		}
+	}
+
+	return event, nil
+}
+
+// parseDateTime parses a DATE or DATE-TIME value, returning the time zone name
+// if the value has a TZID parameter.
+func parseDateTime(prop contentLine, loc *time.Location) (time.Time, bool, string, error) {
+	if prop.params["VALUE"] == "DATE" || len(prop.value) == len(dateFormat) {
+		t, err := time.ParseInLocation(dateFormat, prop.value, time.UTC)
+		if err != nil {
+			return time.Time{}, false, "", fmt.Errorf("invalid %v date: %w", prop.name, err)
+		}
+		return t, false, "", nil
+	}
+
+	if strings.HasSuffix(prop.value, "Z") {
+		t, err := time.Parse(utcFormat, prop.value)
+		if err != nil {
+			return time.Time{}, false, "", fmt.Errorf("invalid %v date-time: %w", prop.name, err)
+		}
+		return t, true, "", nil
+	}
+
+	var timeZone string
+	if tzid, ok := prop.params["TZID"]; ok {
+		// Time zones that aren't in the IANA database (e.g. custom VTIMEZONE
+		// definitions) fall back to loc.
+		if tzLoc, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = tzLoc
+			timeZone = tzLoc.String()
+		}
+	}
+
+	t, err := time.ParseInLocation(dateTimeFormat, prop.value, loc)
+	if err != nil {
+		return time.Time{}, false, "", fmt.Errorf("invalid %v date-time: %w", prop.name, err)
+	}
+
+	return t, true, timeZone, nil
+}
+
+func addDuration(t time.Time, value string) (time.Time, error) {
+	m := durationRegexp.FindStringSubmatch(value)
+	if m == nil || value == "P" || value == "PT" {
+		return time.Time{}, fmt.Errorf("invalid DURATION %q", value)
+	}
+
+	n := make([]int, 5)
+	for i := range n {
+		n[i], _ = strconv.Atoi(m[i+2])
+	}
+
+	sign := 1
+	if m[1] == "-" {
+		sign = -1
+	}
+
+	t = t.AddDate(0, 0, sign*(7*n[0]+n[1]))
+
+	return t.Add(time.Duration(sign) * (time.Duration(n[2])*time.Hour +
+		time.Duration(n[3])*time.Minute +
+		time.Duration(n[4])*time.Second)), nil
+}
+
+// unfold reads content lines, joining folded lines.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ical: failed to read calendar: %w", err)
+	}
+
+	return lines, nil
+}
+
+// parseContentLine parses `NAME;PARAM=VALUE;PARAM="QUOTED:VALUE":value`.
+func parseContentLine(line string) (contentLine, error) {
+	prop := contentLine{params: map[string]string{}}
+
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return contentLine{}, fmt.Errorf("invalid content line %q", line)
+	}
+	prop.name = strings.ToUpper(line[:i])
+
+	for line[i] == ';' {
+		line = line[i+1:]
+
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return contentLine{}, fmt.Errorf("invalid parameter in %v", prop.name)
+		}
+		name := strings.ToUpper(line[:eq])
+		line = line[eq+1:]
+
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				return contentLine{}, fmt.Errorf("unterminated quoted parameter in %v", prop.name)
+			}
+			value = line[1 : end+1]
+			line = line[end+2:]
+			i = 0
+		} else {
+			i = strings.IndexAny(line, ";:")
+			if i < 0 {
+				return contentLine{}, fmt.Errorf("invalid parameter in %v", prop.name)
+			}
+			value = line[:i]
+		}
+
+		if i >= len(line) || (line[i] != ';' && line[i] != ':') {
+			return contentLine{}, fmt.Errorf("invalid parameter in %v", prop.name)
+		}
+
+		prop.params[name] = value
+	}
+
+	prop.value = line[i+1:]
+
+	return prop, nil
+}
+
+func unescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
@@ -5,19 +5,21 @@ import "errors"
 type IconType string
 
 const (
-	IconTypeEmoji    IconType = "emoji"
-	IconTypeFile     IconType = "file"
-	IconTypeExternal IconType = "external"
+	IconTypeEmoji      IconType = "emoji"
+	IconTypeFile       IconType = "file"
+	IconTypeExternal   IconType = "external"
+	IconTypeFileUpload IconType = "file_upload"
 )
 
-// Icon has one non-nil Emoji or External field, denoted by the corresponding
-// IconType.
+// Icon has one non-nil Emoji, External or FileUpload field, denoted by the
+// corresponding IconType.
 type Icon struct {
 	Type IconType `json:"type"`
 
-	Emoji    *string       `json:"emoji,omitempty"`
-	File     *FileFile     `json:"file,omitempty"`
-	External *FileExternal `json:"external,omitempty"`
+	Emoji      *string         `json:"emoji,omitempty"`
+	File       *FileFile       `json:"file,omitempty"`
+	External   *FileExternal   `json:"external,omitempty"`
+	FileUpload *FileUploadFile `json:"file_upload,omitempty"`
 }
 
 func (icon Icon) Validate() error {
@@ -31,6 +33,9 @@ func (icon Icon) Validate() error {
 	if icon.Type == IconTypeExternal && icon.External == nil {
 		return errors.New("icon external cannot be empty")
 	}
+	if icon.Type == IconTypeFileUpload && (icon.FileUpload == nil || icon.FileUpload.ID == "") {
+		return errors.New("icon file upload ID cannot be empty")
+	}
 
 	return nil
 }
@@ -0,0 +1,274 @@
+// Command mockgen generates a mock implementation of an interface, with a func
+// field per method for programming responses, and call recording. Interfaces
+// embedded in the interface must be declared in the same source file.
+//
+// Usage:
+//
+//	go run ./internal/cmd/mockgen -source api.go -interface API -type MockClient -out mock_api.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	source := flag.String("source", "", "Go source file declaring the interface")
+	iface := flag.String("interface", "", "Name of the interface to mock")
+	typeName := flag.String("type", "", "Name of the generated mock type")
+	out := flag.String("out", "", "Output file (default: stdout)")
+	flag.Parse()
+
+	if *source == "" || *iface == "" || *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := os.ReadFile(*source)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := generate(*source, src, *iface, *typeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(code)
+		return
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type method struct {
+	name     string
+	params   []param
+	results  []string
+	variadic bool
+}
+
+type param struct {
+	name string
+	typ  string
+}
+
+// generate returns the formatted source of a mock of interface iface, which is
+// declared in src.
+func generate(filename string, src []byte, iface, typeName string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source: %w", err)
+	}
+
+	interfaces := make(map[string]*ast.InterfaceType)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok {
+			if it, ok := spec.Type.(*ast.InterfaceType); ok {
+				interfaces[spec.Name.Name] = it
+			}
+		}
+		return true
+	})
+
+	methods, err := interfaceMethods(fset, interfaces, iface)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := []string{strconv.Quote("sync")}
+	for _, spec := range file.Imports {
+		imports = append(imports, spec.Path.Value)
+	}
+
+	var buf bytes.Buffer
+	w := func(format string, args ...interface{}) {
+		fmt.Fprintf(&buf, format, args...)
+	}
+
+	w("// Code generated by mockgen; DO NOT EDIT.\n\n")
+	w("package %v\n\n", file.Name.Name)
+	w("import (\n")
+	for _, imp := range imports {
+		w("%v\n", imp)
+	}
+	w(")\n\n")
+
+	w("// %vCall is a method call recorded by %v.\n", typeName, typeName)
+	w("type %vCall struct {\nMethod string\nArgs []interface{}\n}\n\n", typeName)
+
+	w("// %v implements %v for tests. Each method records its call, and\n", typeName, iface)
+	w("// returns the results of the corresponding func field, or zero values if the\n")
+	w("// field is nil.\n")
+	w("type %v struct {\n", typeName)
+	for _, m := range methods {
+		w("%vFunc func(%v) %v\n", m.name, m.paramList(), m.resultList(false))
+	}
+	w("\nmu sync.Mutex\ncalls []%vCall\n}\n\n", typeName)
+
+	w("// Calls returns the recorded calls, in order.\n")
+	w("func (m *%v) Calls() []%vCall {\n", typeName, typeName)
+	w("m.mu.Lock()\ndefer m.mu.Unlock()\n\n")
+	w("return append([]%vCall(nil), m.calls...)\n}\n\n", typeName)
+
+	w("// CallsTo returns the recorded calls of a method, in order.\n")
+	w("func (m *%v) CallsTo(method string) []%vCall {\n", typeName, typeName)
+	w("m.mu.Lock()\ndefer m.mu.Unlock()\n\n")
+	w("var calls []%vCall\nfor _, call := range m.calls {\n", typeName)
+	w("if call.Method == method {\ncalls = append(calls, call)\n}\n}\n\nreturn calls\n}\n\n")
+
+	w("// Reset removes the recorded calls.\n")
+	w("func (m *%v) Reset() {\n", typeName)
+	w("m.mu.Lock()\ndefer m.mu.Unlock()\n\nm.calls = nil\n}\n\n")
+
+	w("func (m *%v) record(method string, args ...interface{}) {\n", typeName)
+	w("m.mu.Lock()\ndefer m.mu.Unlock()\n\n")
+	w("m.calls = append(m.calls, %vCall{Method: method, Args: args})\n}\n", typeName)
+
+	for _, m := range methods {
+		args := make([]string, len(m.params))
+		for i, p := range m.params {
+			args[i] = p.name
+		}
+
+		w("\n// %v implements %v.\n", m.name, iface)
+		w("func (m *%v) %v(%v) %v {\n", typeName, m.name, m.paramList(), m.resultList(true))
+		w("m.record(%q", m.name)
+		for _, arg := range args {
+			w(", %v", arg)
+		}
+		w(")\n\n")
+		w("if m.%vFunc == nil {\nreturn\n}\n\n", m.name)
+
+		call := strings.Join(args, ", ")
+		if m.variadic {
+			call += "..."
+		}
+		if len(m.results) == 0 {
+			w("m.%vFunc(%v)\n}\n", m.name, call)
+		} else {
+			w("return m.%vFunc(%v)\n}\n", m.name, call)
+		}
+	}
+
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	return code, nil
+}
+
+// interfaceMethods returns the methods of an interface, including the methods
+// of embedded interfaces, in declaration order.
+func interfaceMethods(fset *token.FileSet, interfaces map[string]*ast.InterfaceType, name string) ([]method, error) {
+	it, ok := interfaces[name]
+	if !ok {
+		return nil, fmt.Errorf("interface %q not found", name)
+	}
+
+	var methods []method
+
+	for _, field := range it.Methods.List {
+		switch t := field.Type.(type) {
+		case *ast.Ident:
+			embedded, err := interfaceMethods(fset, interfaces, t.Name)
+			if err != nil {
+				return nil, err
+			}
+			methods = append(methods, embedded...)
+		case *ast.FuncType:
+			for _, n := range field.Names {
+				methods = append(methods, newMethod(fset, n.Name, t))
+			}
+		default:
+			return nil, fmt.Errorf("unsupported interface element in %v: %v", name, nodeString(fset, field.Type))
+		}
+	}
+
+	return methods, nil
+}
+
+func newMethod(fset *token.FileSet, name string, ft *ast.FuncType) method {
+	m := method{name: name}
+
+	for _, field := range ft.Params.List {
+		typ := nodeString(fset, field.Type)
+		if _, ok := field.Type.(*ast.Ellipsis); ok {
+			m.variadic = true
+		}
+
+		if len(field.Names) == 0 {
+			m.params = append(m.params, param{name: fmt.Sprintf("p%v", len(m.params)), typ: typ})
+			continue
+		}
+		for _, n := range field.Names {
+			m.params = append(m.params, param{name: n.Name, typ: typ})
+		}
+	}
+
+	if ft.Results != nil {
+		for _, field := range ft.Results.List {
+			typ := nodeString(fset, field.Type)
+			n := len(field.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				m.results = append(m.results, typ)
+			}
+		}
+	}
+
+	return m
+}
+
+func (m method) paramList() string {
+	params := make([]string, len(m.params))
+	for i, p := range m.params {
+		params[i] = p.name + " " + p.typ
+	}
+	return strings.Join(params, ", ")
+}
+
+// resultList returns the results, named r0, r1, etc. if named is true, so a
+// bare return yields zero values.
+func (m method) resultList(named bool) string {
+	if len(m.results) == 0 {
+		return ""
+	}
+
+	results := make([]string, len(m.results))
+	for i, typ := range m.results {
+		if named {
+			results[i] = fmt.Sprintf("r%v %v", i, typ)
+		} else {
+			results[i] = typ
+		}
+	}
+
+	if len(results) == 1 && !named {
+		return results[0]
+	}
+
+	return "(" + strings.Join(results, ", ") + ")"
+}
+
+func nodeString(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	_ = printer.Fprint(&buf, fset, node)
+	return buf.String()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestGeneratedMock fails when the generated mock of the Notion API is out of
+// date; run `go generate` in the root package to update it.
+func TestGeneratedMock(t *testing.T) {
+	t.Parallel()
+
+	src, err := os.ReadFile("../../../api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp, err := os.ReadFile("../../../mock_api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := generate("api.go", src, "API", "MockClient")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(string(exp), string(got)); diff != "" {
+		t.Fatalf("generated mock not up to date (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	src := `package foo
+
+import "context"
+
+type Getter interface {
+	Get(ctx context.Context, key string) (string, bool, error)
+}
+
+type Store interface {
+	Getter
+	Set(key, value string)
+	Keys(prefix string, limit ...int) []string
+}
+`
+
+	got, err := generate("foo.go", []byte(src), "Store", "MockStore")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, exp := range []string{
+		"func (m *MockStore) Get(ctx context.Context, key string) (r0 string, r1 bool, r2 error) {",
+		"func (m *MockStore) Set(key string, value string) {",
+		"\tm.SetFunc(key, value)\n",
+		"func (m *MockStore) Keys(prefix string, limit ...int) (r0 []string) {",
+		"\treturn m.KeysFunc(prefix, limit...)\n",
+		"\tKeysFunc func(prefix string, limit ...int) []string\n",
+	} {
+		if !strings.Contains(string(got), exp) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", exp, got)
+		}
+	}
+
+	if _, err := generate("foo.go", []byte(src), "Missing", "MockMissing"); err == nil {
+		t.Fatal("expected error for missing interface, got nil")
+	}
+}
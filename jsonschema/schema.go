@@ -0,0 +1,297 @@
+// Package jsonschema derives JSON Schema (draft 2020-12) documents from Notion
+// database schemas, describing the JSON shape of page property values (as used
+// for the `properties` object when creating or updating a page), and validates
+// JSON and page properties against them.
+//
+// Number properties are described as JSON numbers, with their number format in
+// the description; Notion doesn't constrain the range of numbers, so no minimum
+// or maximum is set.
+//
+// See: https://json-schema.org/draft/2020-12/json-schema-core.html
+package jsonschema
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/skedida/go-notion"
+)
+
+// Draft is the JSON Schema dialect of generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// idPattern matches Notion IDs, which are UUIDs with optional dashes.
+const idPattern = `^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`
+
+// Schema is a JSON Schema document, supporting the subset of keywords used for
+// describing Notion property values.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	ID          string             `json:"$id,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+
+	Type  Types         `json:"type,omitempty"`
+	Enum  []interface{} `json:"enum,omitempty"`
+	Const interface{}   `json:"const,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+
+	Items    *Schema `json:"items,omitempty"`
+	MaxItems *int    `json:"maxItems,omitempty"`
+
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	Format    string `json:"format,omitempty"`
+
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// Types are the allowed JSON types of a value. A single type is encoded as
+// string, as is conventional.
+type Types []string
+
+// MarshalJSON implements json.Marshaler.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Types) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+
+	var types []string
+	if err := json.Unmarshal(b, &types); err != nil {
+		return err
+	}
+	*t = types
+
+	return nil
+}
+
+// FromDatabase returns a schema for the page properties of a database: an
+// object with a property value object per database property. Select, status and
+// multi-select values are restricted to the database options. Properties that
+// can't be set (formula, rollup, created and last edited time/by) are marked as
+// read-only.
+func FromDatabase(db notion.Database) *Schema {
+	schema := &Schema{
+		Schema:               Draft,
+		Title:                notion.PlainText(db.Title),
+		Description:          notion.PlainText(db.Description),
+		Type:                 Types{"object"},
+		Properties:           make(map[string]*Schema, len(db.Properties)),
+		AdditionalProperties: boolPtr(false),
+		Defs: map[string]*Schema{
+			"richText": richTextSchema(),
+			"id": {
+				Type:    Types{"string"},
+				Pattern: idPattern,
+			},
+			"dateTime": {
+				Type: Types{"string"},
+				AnyOf: []*Schema{
+					{Format: "date"},
+					{Format: "date-time"},
+					// Wall clock time, for dates with a time zone.
+					{Pattern: `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}(:\d{2}(\.\d+)?)?$`},
+				},
+			},
+		},
+	}
+
+	if db.URL != "" {
+		schema.ID = db.URL
+	}
+
+	for name, prop := range db.Properties {
+		schema.Properties[name] = PropertySchema(prop)
+	}
+
+	return schema
+}
+
+// PropertySchema returns a schema for the value of a database property. The
+// schema references definitions (`$defs`) of the schema returned by
+// FromDatabase.
+func PropertySchema(prop notion.DatabaseProperty) *Schema {
+	schema := &Schema{
+		Type: Types{"object"},
+		Properties: map[string]*Schema{
+			"id":   {Type: Types{"string"}},
+			"name": {Type: Types{"string"}},
+			"type": {Const: string(prop.Type)},
+		},
+		AdditionalProperties: boolPtr(false),
+	}
+
+	var value *Schema
+
+	switch prop.Type {
+	case notion.DBPropTypeTitle, notion.DBPropTypeRichText:
+		value = &Schema{
+			Type:     Types{"array"},
+			Items:    &Schema{Ref: "#/$defs/richText"},
+			MaxItems: intPtr(notion.MaxRichTextElements),
+		}
+	case notion.DBPropTypeNumber:
+		value = &Schema{Type: Types{"number", "null"}}
+		if prop.Number != nil && prop.Number.Format != "" {
+			value.Description = "Number format: " + string(prop.Number.Format)
+		}
+	case notion.DBPropTypeSelect:
+		value = optionSchema(prop.Select, true)
+	case notion.DBPropTypeStatus:
+		var metadata *notion.SelectMetadata
+		if prop.Status != nil {
+			metadata = &notion.SelectMetadata{Options: prop.Status.Options}
+		}
+		value = optionSchema(metadata, true)
+	case notion.DBPropTypeMultiSelect:
+		value = &Schema{
+			Type:  Types{"array"},
+			Items: optionSchema(prop.MultiSelect, false),
+		}
+	case notion.DBPropTypeDate:
+		value = &Schema{
+			Type: Types{"object", "null"},
+			Properties: map[string]*Schema{
+				"start":     {Ref: "#/$defs/dateTime"},
+				"end":       {AnyOf: []*Schema{{Ref: "#/$defs/dateTime"}, {Type: Types{"null"}}}},
+				"time_zone": {Type: Types{"string", "null"}},
+			},
+			Required:             []string{"start"},
+			AdditionalProperties: boolPtr(false),
+		}
+	case notion.DBPropTypeCheckbox:
+		value = &Schema{Type: Types{"boolean"}}
+	case notion.DBPropTypeURL:
+		value = &Schema{Type: Types{"string", "null"}, Format: "uri"}
+	case notion.DBPropTypeEmail:
+		value = &Schema{Type: Types{"string", "null"}, Format: "email"}
+	case notion.DBPropTypePhoneNumber:
+		value = &Schema{Type: Types{"string", "null"}}
+	case notion.DBPropTypePeople, notion.DBPropTypeRelation:
+		value = &Schema{
+			Type: Types{"array"},
+			Items: &Schema{
+				Type:       Types{"object"},
+				Properties: map[string]*Schema{"id": {Ref: "#/$defs/id"}},
+				Required:   []string{"id"},
+			},
+		}
+	case notion.DBPropTypeFiles:
+		value = &Schema{
+			Type: Types{"array"},
+			Items: &Schema{
+				Type: Types{"object"},
+				Properties: map[string]*Schema{
+					"name": {Type: Types{"string"}, MaxLength: intPtr(100)},
+					"type": {Enum: []interface{}{string(notion.FileTypeExternal), string(notion.FileTypeFile), string(notion.FileTypeFileUpload)}},
+					"external": {
+						Type:       Types{"object"},
+						Properties: map[string]*Schema{"url": {Type: Types{"string"}, Format: "uri"}},
+						Required:   []string{"url"},
+					},
+					"file_upload": {
+						Type:       Types{"object"},
+						Properties: map[string]*Schema{"id": {Type: Types{"string"}}},
+						Required:   []string{"id"},
+					},
+				},
+				Required: []string{"name"},
+			},
+		}
+	default:
+		// Formula, rollup, created and last edited time/by, and unknown types.
+		// Their values aren't described, as they can only be read.
+		schema.ReadOnly = true
+		schema.AdditionalProperties = nil
+		return schema
+	}
+
+	schema.Properties[string(prop.Type)] = value
+
+	return schema
+}
+
+func optionSchema(metadata *notion.SelectMetadata, nullable bool) *Schema {
+	schema := &Schema{
+		Type: Types{"object"},
+		Properties: map[string]*Schema{
+			"id":    {Type: Types{"string"}},
+			"name":  {Type: Types{"string"}},
+			"color": {Type: Types{"string"}},
+		},
+		AdditionalProperties: boolPtr(false),
+	}
+
+	if nullable {
+		schema.Type = append(schema.Type, "null")
+	}
+
+	if metadata != nil && len(metadata.Options) > 0 {
+		names := make([]string, len(metadata.Options))
+		for i, option := range metadata.Options {
+			names[i] = option.Name
+		}
+		sort.Strings(names)
+
+		enum := make([]interface{}, len(names))
+		for i, name := range names {
+			enum[i] = name
+		}
+		schema.Properties["name"].Enum = enum
+	}
+
+	return schema
+}
+
+func richTextSchema() *Schema {
+	return &Schema{
+		Type: Types{"object"},
+		Properties: map[string]*Schema{
+			"type": {Enum: []interface{}{
+				string(notion.RichTextTypeText),
+				string(notion.RichTextTypeMention),
+				string(notion.RichTextTypeEquation),
+			}},
+			"text": {
+				Type: Types{"object"},
+				Properties: map[string]*Schema{
+					"content": {Type: Types{"string"}, MaxLength: intPtr(notion.MaxRichTextLength)},
+					"link": {
+						Type:       Types{"object", "null"},
+						Properties: map[string]*Schema{"url": {Type: Types{"string"}, Format: "uri"}},
+					},
+				},
+				Required: []string{"content"},
+			},
+		},
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,174 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/jsonschema"
+)
+
+func testDatabase() notion.Database {
+	return notion.Database{
+		Title: []notion.RichText{{PlainText: "Tasks"}},
+		Properties: notion.DatabaseProperties{
+			"Name":   {Type: notion.DBPropTypeTitle, Title: &notion.EmptyMetadata{}},
+			"Price":  {Type: notion.DBPropTypeNumber, Number: &notion.NumberMetadata{Format: notion.NumberFormatEuro}},
+			"Status": {Type: notion.DBPropTypeSelect, Select: &notion.SelectMetadata{Options: []notion.SelectOptions{{Name: "Open"}, {Name: "Done"}}}},
+			"Tags":   {Type: notion.DBPropTypeMultiSelect, MultiSelect: &notion.SelectMetadata{Options: []notion.SelectOptions{{Name: "A"}}}},
+			"Due":    {Type: notion.DBPropTypeDate, Date: &notion.EmptyMetadata{}},
+			"Email":  {Type: notion.DBPropTypeEmail, Email: &notion.EmptyMetadata{}},
+			"Link":   {Type: notion.DBPropTypeURL, URL: &notion.EmptyMetadata{}},
+			"Owner":  {Type: notion.DBPropTypePeople, People: &notion.EmptyMetadata{}},
+			"Total":  {Type: notion.DBPropTypeFormula, Formula: &notion.FormulaMetadata{Expression: "1"}},
+		},
+	}
+}
+
+func TestFromDatabase(t *testing.T) {
+	t.Parallel()
+
+	schema := jsonschema.FromDatabase(testDatabase())
+
+	b, err := json.Marshal(schema.Properties["Status"])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := `{"type":"object","properties":{` +
+		`"id":{"type":"string"},` +
+		`"name":{"type":"string"},` +
+		`"select":{"type":["object","null"],"properties":{"color":{"type":"string"},"id":{"type":"string"},"name":{"type":"string","enum":["Done","Open"]}},"additionalProperties":false},` +
+		`"type":{"const":"select"}},` +
+		`"additionalProperties":false}`
+
+	if diff := cmp.Diff(exp, string(b)); diff != "" {
+		t.Fatalf("schema not equal (-exp, +got):\n%v", diff)
+	}
+
+	if !schema.Properties["Total"].ReadOnly {
+		t.Fatal("expected formula property to be read-only")
+	}
+
+	// Round trip.
+	var decoded jsonschema.Schema
+	b, err = json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(schema, &decoded); diff != "" {
+		t.Fatalf("decoded schema not equal (-exp, +got):\n%v", diff)
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	t.Parallel()
+
+	schema := jsonschema.FromDatabase(testDatabase())
+
+	tests := []struct {
+		name      string
+		json      string
+		opts      jsonschema.ValidateOptions
+		expErrors []string
+	}{
+		{
+			name: "valid",
+			json: `{
+				"Name": {"title": [{"text": {"content": "Foo"}}]},
+				"Price": {"number": 12.5},
+				"Status": {"select": {"name": "Open"}},
+				"Tags": {"multi_select": [{"name": "A"}]},
+				"Due": {"date": {"start": "2021-05-23T09:00:00", "time_zone": "Europe/Amsterdam"}},
+				"Email": {"email": "foo@example.com"},
+				"Link": {"url": null},
+				"Owner": {"people": [{"id": "be6bb2a0-5d0e-4a86-8e40-0a7b6d4f0c01"}]},
+				"Total": {"formula": {"type": "number", "number": 1}}
+			}`,
+		},
+		{
+			name: "invalid",
+			json: `{
+				"Name": {"title": [{"text": {}}]},
+				"Price": {"number": "12"},
+				"Status": {"select": {"name": "Closed"}},
+				"Due": {"date": {"start": "tomorrow"}},
+				"Email": {"email": "foo"},
+				"Owner": {"people": [{"id": "foo"}]},
+				"Foo": {}
+			}`,
+			expErrors: []string{
+				"/Due/date/start: value doesn't match any allowed schema",
+				`/Email/email: "foo" is not a valid email`,
+				`/Foo: unknown property "Foo"`,
+				`/Name/title/0/text: missing required property "content"`,
+				`/Owner/people/0/id: "foo" doesn't match pattern "^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$"`,
+				"/Price/number: expected number or null, got string",
+				`/Status/select/name: "Closed" is not one of ["Done","Open"]`,
+			},
+		},
+		{
+			name:      "read-only",
+			json:      `{"Total": {"formula": {}}}`,
+			opts:      jsonschema.ValidateOptions{Write: true},
+			expErrors: []string{"/Total: value is read-only"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := schema.ValidateJSON([]byte(tt.json), tt.opts)
+
+			var got []string
+			var verrs jsonschema.ValidationErrors
+			if errors.As(err, &verrs) {
+				for _, verr := range verrs {
+					got = append(got, verr.Error())
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.expErrors, got); diff != "" {
+				t.Fatalf("errors not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestValidateProperties(t *testing.T) {
+	t.Parallel()
+
+	price := 10.0
+	date, err := notion.NewDateInTimeZone(time.Date(2021, 5, 23, 9, 0, 0, 0, time.UTC), nil, "Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	props := notion.DatabasePageProperties{
+		"Name":  {Title: []notion.RichText{{Text: &notion.Text{Content: "Foo"}}}},
+		"Price": {Number: &price},
+		"Due":   {Date: &date},
+		"Tags":  {MultiSelect: []notion.SelectOptions{{Name: "A"}}},
+	}
+
+	if err := jsonschema.ValidateProperties(testDatabase(), props); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	props["Tags"] = notion.DatabasePageProperty{MultiSelect: []notion.SelectOptions{{Name: "B"}}}
+
+	err = jsonschema.ValidateProperties(testDatabase(), props)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,344 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/skedida/go-notion"
+)
+
+// ValidationError is a violation of a schema keyword.
+type ValidationError struct {
+	// Path is the JSON Pointer (RFC 6901) to the invalid value, e.g.
+	// `/Tags/multi_select/0/name`.
+	Path    string
+	Keyword string
+	Message string
+}
+
+// Error implements error.
+func (e ValidationError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%v: %v", path, e.Message)
+}
+
+// ValidationErrors are all violations found when validating a value.
+type ValidationErrors []ValidationError
+
+// Error implements error.
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return "jsonschema: invalid value: " + strings.Join(msgs, "; ")
+}
+
+// ValidateOptions configure validation.
+type ValidateOptions struct {
+	// Write rejects values for read-only schemas, as is appropriate for values
+	// that are sent to the Notion API.
+	Write bool
+}
+
+// Validate validates a decoded JSON value (as returned by json.Unmarshal into
+// an empty interface) against the schema. It returns ValidationErrors if the
+// value is invalid.
+//
+// Supported keywords: $ref (to $defs of the root schema), type, enum, const,
+// properties, required, additionalProperties, items, maxItems, minLength,
+// maxLength, pattern, format (date, date-time, email, uri and uuid), minimum,
+// maximum, anyOf and readOnly.
+func (s *Schema) Validate(v interface{}, opts ValidateOptions) error {
+	vd := validator{root: s, opts: opts}
+	vd.validate(s, v, "")
+
+	if len(vd.errs) > 0 {
+		return vd.errs
+	}
+
+	return nil
+}
+
+// ValidateJSON validates JSON against the schema. See Validate.
+func (s *Schema) ValidateJSON(data []byte, opts ValidateOptions) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("jsonschema: invalid JSON: %w", err)
+	}
+
+	return s.Validate(v, opts)
+}
+
+// ValidateProperties validates page properties against the schema of db, as
+// values to be written (see ValidateOptions.Write), e.g. before creating a page.
+func ValidateProperties(db notion.Database, props notion.DatabasePageProperties) error {
+	b, err := json.Marshal(props)
+	if err != nil {
+		return fmt.Errorf("jsonschema: failed to encode properties: %w", err)
+	}
+
+	return FromDatabase(db).ValidateJSON(b, ValidateOptions{Write: true})
+}
+
+type validator struct {
+	root *Schema
+	opts ValidateOptions
+	errs ValidationErrors
+}
+
+func (vd *validator) fail(path, keyword, format string, args ...interface{}) {
+	vd.errs = append(vd.errs, ValidationError{
+		Path:    path,
+		Keyword: keyword,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (vd *validator) validate(s *Schema, v interface{}, path string) {
+	if s.Ref != "" {
+		ref, ok := vd.resolve(s.Ref)
+		if !ok {
+			vd.fail(path, "$ref", "unresolvable reference %q", s.Ref)
+			return
+		}
+		vd.validate(ref, v, path)
+	}
+
+	if s.ReadOnly && vd.opts.Write {
+		vd.fail(path, "readOnly", "value is read-only")
+		return
+	}
+
+	if len(s.Type) > 0 && !matchesType(s.Type, v) {
+		vd.fail(path, "type", "expected %v, got %v", strings.Join(s.Type, " or "), typeOf(v))
+		return
+	}
+
+	if s.Const != nil && !equal(s.Const, v) {
+		vd.fail(path, "const", "expected %v", toJSON(s.Const))
+	}
+
+	if len(s.Enum) > 0 && v != nil {
+		found := false
+		for _, e := range s.Enum {
+			if equal(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			vd.fail(path, "enum", "%v is not one of %v", toJSON(v), toJSON(s.Enum))
+		}
+	}
+
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			sv := validator{root: vd.root, opts: vd.opts}
+			sv.validate(sub, v, path)
+			if len(sv.errs) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			vd.fail(path, "anyOf", "value doesn't match any allowed schema")
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		vd.validateObject(s, v, path)
+	case []interface{}:
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			vd.fail(path, "maxItems", "expected at most %v items, got %v", *s.MaxItems, len(v))
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				vd.validate(s.Items, item, path+"/"+strconv.Itoa(i))
+			}
+		}
+	case string:
+		vd.validateString(s, v, path)
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			vd.fail(path, "minimum", "expected at least %v, got %v", *s.Minimum, v)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			vd.fail(path, "maximum", "expected at most %v, got %v", *s.Maximum, v)
+		}
+	}
+}
+
+func (vd *validator) validateObject(s *Schema, v map[string]interface{}, path string) {
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			vd.fail(path, "required", "missing required property %q", name)
+		}
+	}
+
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propPath := path + "/" + escapePointer(name)
+
+		sub, ok := s.Properties[name]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				vd.fail(propPath, "additionalProperties", "unknown property %q", name)
+			}
+			continue
+		}
+
+		vd.validate(sub, v[name], propPath)
+	}
+}
+
+func (vd *validator) validateString(s *Schema, v string, path string) {
+	n := utf8.RuneCountInString(v)
+	if s.MinLength != nil && n < *s.MinLength {
+		vd.fail(path, "minLength", "expected at least %v characters, got %v", *s.MinLength, n)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		vd.fail(path, "maxLength", "expected at most %v characters, got %v", *s.MaxLength, n)
+	}
+
+	if s.Pattern != "" {
+		re, err := compilePattern(s.Pattern)
+		if err != nil {
+			vd.fail(path, "pattern", "invalid pattern %q", s.Pattern)
+		} else if !re.MatchString(v) {
+			vd.fail(path, "pattern", "%q doesn't match pattern %q", v, s.Pattern)
+		}
+	}
+
+	if s.Format != "" && !validFormat(s.Format, v) {
+		vd.fail(path, "format", "%q is not a valid %v", v, s.Format)
+	}
+}
+
+// resolve resolves references to definitions of the root schema, e.g.
+// `#/$defs/richText`.
+func (vd *validator) resolve(ref string) (*Schema, bool) {
+	name := strings.TrimPrefix(ref, "#/$defs/")
+	if name == ref {
+		return nil, false
+	}
+	s, ok := vd.root.Defs[name]
+	return s, ok
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func validFormat(format, v string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse("2006-01-02", v)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, v)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(v)
+		return err == nil && addr.Address == v
+	case "uri":
+		u, err := url.Parse(v)
+		return err == nil && u.Scheme != ""
+	case "uuid":
+		return uuidRegexp.MatchString(v)
+	}
+
+	// Unknown formats are annotations only.
+	return true
+}
+
+var patterns sync.Map
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+
+	return re, nil
+}
+
+func matchesType(types Types, v interface{}) bool {
+	actual := typeOf(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// equal compares a schema value (e.g. an enum value) and a decoded JSON value.
+func equal(a, b interface{}) bool {
+	var na, nb interface{}
+	if err := json.Unmarshal([]byte(toJSON(a)), &na); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(toJSON(b)), &nb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
+
+func toJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,361 @@
+// Code generated by mockgen; DO NOT EDIT.
+
+package notion
+
+import (
+	"context"
+	"sync"
+)
+
+// MockClientCall is a method call recorded by MockClient.
+type MockClientCall struct {
+	Method string
+	Args   []interface{}
+}
+
+// MockClient implements API for tests. Each method records its call, and
+// returns the results of the corresponding func field, or zero values if the
+// field is nil.
+type MockClient struct {
+	FindDatabaseByIDFunc      func(ctx context.Context, id string) (Database, error)
+	QueryDatabaseFunc         func(ctx context.Context, id string, query *DatabaseQuery) (DatabaseQueryResponse, error)
+	CreateDatabaseFunc        func(ctx context.Context, params CreateDatabaseParams) (Database, error)
+	UpdateDatabaseFunc        func(ctx context.Context, databaseID string, params UpdateDatabaseParams) (Database, error)
+	FindPageByIDFunc          func(ctx context.Context, id string) (Page, error)
+	CreatePageFunc            func(ctx context.Context, params CreatePageParams) (Page, error)
+	UpdatePageFunc            func(ctx context.Context, pageID string, params UpdatePageParams) (Page, error)
+	FindPagePropertyByIDFunc  func(ctx context.Context, pageID string, propID string, query *PaginationQuery) (PagePropResponse, error)
+	FindBlockChildrenByIDFunc func(ctx context.Context, blockID string, query *PaginationQuery) (BlockChildrenResponse, error)
+	AppendBlockChildrenFunc   func(ctx context.Context, blockID string, children []Block) (BlockChildrenResponse, error)
+	FindBlockByIDFunc         func(ctx context.Context, blockID string) (Block, error)
+	UpdateBlockFunc           func(ctx context.Context, blockID string, block Block) (Block, error)
+	DeleteBlockFunc           func(ctx context.Context, blockID string) (Block, error)
+	FindUserByIDFunc          func(ctx context.Context, id string) (User, error)
+	FindCurrentUserFunc       func(ctx context.Context) (User, error)
+	ListUsersFunc             func(ctx context.Context, query *PaginationQuery) (ListUsersResponse, error)
+	SearchFunc                func(ctx context.Context, opts *SearchOpts) (SearchResponse, error)
+	SearchAllFunc             func(ctx context.Context, opts *SearchAllOpts) (SearchResults, error)
+	CreateCommentFunc         func(ctx context.Context, params CreateCommentParams) (Comment, error)
+	FindCommentsByBlockIDFunc func(ctx context.Context, query FindCommentsByBlockIDQuery) (FindCommentsResponse, error)
+	CreateFileUploadFunc      func(ctx context.Context, params CreateFileUploadParams) (FileUpload, error)
+	SendFileUploadFunc        func(ctx context.Context, fileUploadID string, params SendFileUploadParams) (FileUpload, error)
+	CompleteFileUploadFunc    func(ctx context.Context, fileUploadID string) (FileUpload, error)
+	FindFileUploadByIDFunc    func(ctx context.Context, fileUploadID string) (FileUpload, error)
+	FindFileUploadsFunc       func(ctx context.Context, query *FindFileUploadsQuery) (FindFileUploadsResponse, error)
+
+	mu    sync.Mutex
+	calls []MockClientCall
+}
+
+// Calls returns the recorded calls, in order.
+func (m *MockClient) Calls() []MockClientCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]MockClientCall(nil), m.calls...)
+}
+
+// CallsTo returns the recorded calls of a method, in order.
+func (m *MockClient) CallsTo(method string) []MockClientCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []MockClientCall
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// Reset removes the recorded calls.
+func (m *MockClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+}
+
+func (m *MockClient) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, MockClientCall{Method: method, Args: args})
+}
+
+// FindDatabaseByID implements API.
+func (m *MockClient) FindDatabaseByID(ctx context.Context, id string) (r0 Database, r1 error) {
+	m.record("FindDatabaseByID", ctx, id)
+
+	if m.FindDatabaseByIDFunc == nil {
+		return
+	}
+
+	return m.FindDatabaseByIDFunc(ctx, id)
+}
+
+// QueryDatabase implements API.
+func (m *MockClient) QueryDatabase(ctx context.Context, id string, query *DatabaseQuery) (r0 DatabaseQueryResponse, r1 error) {
+	m.record("QueryDatabase", ctx, id, query)
+
+	if m.QueryDatabaseFunc == nil {
+		return
+	}
+
+	return m.QueryDatabaseFunc(ctx, id, query)
+}
+
+// CreateDatabase implements API.
+func (m *MockClient) CreateDatabase(ctx context.Context, params CreateDatabaseParams) (r0 Database, r1 error) {
+	m.record("CreateDatabase", ctx, params)
+
+	if m.CreateDatabaseFunc == nil {
+		return
+	}
+
+	return m.CreateDatabaseFunc(ctx, params)
+}
+
+// UpdateDatabase implements API.
+func (m *MockClient) UpdateDatabase(ctx context.Context, databaseID string, params UpdateDatabaseParams) (r0 Database, r1 error) {
+	m.record("UpdateDatabase", ctx, databaseID, params)
+
+	if m.UpdateDatabaseFunc == nil {
+		return
+	}
+
+	return m.UpdateDatabaseFunc(ctx, databaseID, params)
+}
+
+// FindPageByID implements API.
+func (m *MockClient) FindPageByID(ctx context.Context, id string) (r0 Page, r1 error) {
+	m.record("FindPageByID", ctx, id)
+
+	if m.FindPageByIDFunc == nil {
+		return
+	}
+
+	return m.FindPageByIDFunc(ctx, id)
+}
+
+// CreatePage implements API.
+func (m *MockClient) CreatePage(ctx context.Context, params CreatePageParams) (r0 Page, r1 error) {
+	m.record("CreatePage", ctx, params)
+
+	if m.CreatePageFunc == nil {
+		return
+	}
+
+	return m.CreatePageFunc(ctx, params)
+}
+
+// UpdatePage implements API.
+func (m *MockClient) UpdatePage(ctx context.Context, pageID string, params UpdatePageParams) (r0 Page, r1 error) {
+	m.record("UpdatePage", ctx, pageID, params)
+
+	if m.UpdatePageFunc == nil {
+		return
+	}
+
+	return m.UpdatePageFunc(ctx, pageID, params)
+}
+
+// FindPagePropertyByID implements API.
+func (m *MockClient) FindPagePropertyByID(ctx context.Context, pageID string, propID string, query *PaginationQuery) (r0 PagePropResponse, r1 error) {
+	m.record("FindPagePropertyByID", ctx, pageID, propID, query)
+
+	if m.FindPagePropertyByIDFunc == nil {
+		return
+	}
+
+	return m.FindPagePropertyByIDFunc(ctx, pageID, propID, query)
+}
+
+// FindBlockChildrenByID implements API.
+func (m *MockClient) FindBlockChildrenByID(ctx context.Context, blockID string, query *PaginationQuery) (r0 BlockChildrenResponse, r1 error) {
+	m.record("FindBlockChildrenByID", ctx, blockID, query)
+
+	if m.FindBlockChildrenByIDFunc == nil {
+		return
+	}
+
+	return m.FindBlockChildrenByIDFunc(ctx, blockID, query)
+}
+
+// AppendBlockChildren implements API.
+func (m *MockClient) AppendBlockChildren(ctx context.Context, blockID string, children []Block) (r0 BlockChildrenResponse, r1 error) {
+	m.record("AppendBlockChildren", ctx, blockID, children)
+
+	if m.AppendBlockChildrenFunc == nil {
+		return
+	}
+
+	return m.AppendBlockChildrenFunc(ctx, blockID, children)
+}
+
+// FindBlockByID implements API.
+func (m *MockClient) FindBlockByID(ctx context.Context, blockID string) (r0 Block, r1 error) {
+	m.record("FindBlockByID", ctx, blockID)
+
+	if m.FindBlockByIDFunc == nil {
+		return
+	}
+
+	return m.FindBlockByIDFunc(ctx, blockID)
+}
+
+// UpdateBlock implements API.
+func (m *MockClient) UpdateBlock(ctx context.Context, blockID string, block Block) (r0 Block, r1 error) {
+	m.record("UpdateBlock", ctx, blockID, block)
+
+	if m.UpdateBlockFunc == nil {
+		return
+	}
+
+	return m.UpdateBlockFunc(ctx, blockID, block)
+}
+
+// DeleteBlock implements API.
+func (m *MockClient) DeleteBlock(ctx context.Context, blockID string) (r0 Block, r1 error) {
+	m.record("DeleteBlock", ctx, blockID)
+
+	if m.DeleteBlockFunc == nil {
+		return
+	}
+
+	return m.DeleteBlockFunc(ctx, blockID)
+}
+
+// FindUserByID implements API.
+func (m *MockClient) FindUserByID(ctx context.Context, id string) (r0 User, r1 error) {
+	m.record("FindUserByID", ctx, id)
+
+	if m.FindUserByIDFunc == nil {
+		return
+	}
+
+	return m.FindUserByIDFunc(ctx, id)
+}
+
+// FindCurrentUser implements API.
+func (m *MockClient) FindCurrentUser(ctx context.Context) (r0 User, r1 error) {
+	m.record("FindCurrentUser", ctx)
+
+	if m.FindCurrentUserFunc == nil {
+		return
+	}
+
+	return m.FindCurrentUserFunc(ctx)
+}
+
+// ListUsers implements API.
+func (m *MockClient) ListUsers(ctx context.Context, query *PaginationQuery) (r0 ListUsersResponse, r1 error) {
+	m.record("ListUsers", ctx, query)
+
+	if m.ListUsersFunc == nil {
+		return
+	}
+
+	return m.ListUsersFunc(ctx, query)
+}
+
+// Search implements API.
+func (m *MockClient) Search(ctx context.Context, opts *SearchOpts) (r0 SearchResponse, r1 error) {
+	m.record("Search", ctx, opts)
+
+	if m.SearchFunc == nil {
+		return
+	}
+
+	return m.SearchFunc(ctx, opts)
+}
+
+// SearchAll implements API.
+func (m *MockClient) SearchAll(ctx context.Context, opts *SearchAllOpts) (r0 SearchResults, r1 error) {
+	m.record("SearchAll", ctx, opts)
+
+	if m.SearchAllFunc == nil {
+		return
+	}
+
+	return m.SearchAllFunc(ctx, opts)
+}
+
+// CreateComment implements API.
+func (m *MockClient) CreateComment(ctx context.Context, params CreateCommentParams) (r0 Comment, r1 error) {
+	m.record("CreateComment", ctx, params)
+
+	if m.CreateCommentFunc == nil {
+		return
+	}
+
+	return m.CreateCommentFunc(ctx, params)
+}
+
+// FindCommentsByBlockID implements API.
+func (m *MockClient) FindCommentsByBlockID(ctx context.Context, query FindCommentsByBlockIDQuery) (r0 FindCommentsResponse, r1 error) {
+	m.record("FindCommentsByBlockID", ctx, query)
+
+	if m.FindCommentsByBlockIDFunc == nil {
+		return
+	}
+
+	return m.FindCommentsByBlockIDFunc(ctx, query)
+}
+
+// CreateFileUpload implements API.
+func (m *MockClient) CreateFileUpload(ctx context.Context, params CreateFileUploadParams) (r0 FileUpload, r1 error) {
+	m.record("CreateFileUpload", ctx, params)
+
+	if m.CreateFileUploadFunc == nil {
+		return
+	}
+
+	return m.CreateFileUploadFunc(ctx, params)
+}
+
+// SendFileUpload implements API.
+func (m *MockClient) SendFileUpload(ctx context.Context, fileUploadID string, params SendFileUploadParams) (r0 FileUpload, r1 error) {
+	m.record("SendFileUpload", ctx, fileUploadID, params)
+
+	if m.SendFileUploadFunc == nil {
+		return
+	}
+
+	return m.SendFileUploadFunc(ctx, fileUploadID, params)
+}
+
+// CompleteFileUpload implements API.
+func (m *MockClient) CompleteFileUpload(ctx context.Context, fileUploadID string) (r0 FileUpload, r1 error) {
+	m.record("CompleteFileUpload", ctx, fileUploadID)
+
+	if m.CompleteFileUploadFunc == nil {
+		return
+	}
+
+	return m.CompleteFileUploadFunc(ctx, fileUploadID)
+}
+
+// FindFileUploadByID implements API.
+func (m *MockClient) FindFileUploadByID(ctx context.Context, fileUploadID string) (r0 FileUpload, r1 error) {
+	m.record("FindFileUploadByID", ctx, fileUploadID)
+
+	if m.FindFileUploadByIDFunc == nil {
+		return
+	}
+
+	return m.FindFileUploadByIDFunc(ctx, fileUploadID)
+}
+
+// FindFileUploads implements API.
+func (m *MockClient) FindFileUploads(ctx context.Context, query *FindFileUploadsQuery) (r0 FindFileUploadsResponse, r1 error) {
+	m.record("FindFileUploads", ctx, query)
+
+	if m.FindFileUploadsFunc == nil {
+		return
+	}
+
+	return m.FindFileUploadsFunc(ctx, query)
+}
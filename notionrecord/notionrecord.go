@@ -0,0 +1,407 @@
+// Package notionrecord records HTTP interactions with the Notion API into
+// cassette files, and replays them, for deterministic tests that don't need
+// network access or an API key.
+//
+// A Recorder is an http.RoundTripper, to be used as transport of the client's
+// http.Client:
+//
+//	rec, err := notionrecord.New("testdata/query_database.json", notionrecord.Options{})
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//
+//	client := notion.NewClient(os.Getenv("NOTION_API_KEY"), notion.WithHTTPClient(rec.HTTPClient()))
+package notionrecord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// multipartBoundary replaces the (random) boundary of recorded multipart
+// request bodies, so they can be matched when replaying.
+const multipartBoundary = "notionrecord-boundary"
+
+// Redacted replaces redacted header and field values.
+const Redacted = "REDACTED"
+
+// ErrNoInteraction is returned when replaying a request that isn't in the
+// cassette, or of which all recorded interactions were replayed already.
+var ErrNoInteraction = errors.New("notionrecord: no matching interaction in cassette")
+
+// Mode determines whether interactions are recorded or replayed.
+type Mode int
+
+const (
+	// ModeAuto replays the cassette if the file exists, and records it
+	// otherwise.
+	ModeAuto Mode = iota
+	// ModeReplay replays the cassette, which must exist. No requests are sent.
+	ModeReplay
+	// ModeRecord sends requests, and records them into the cassette, replacing
+	// it if it exists.
+	ModeRecord
+)
+
+// Options configure a Recorder.
+type Options struct {
+	Mode Mode
+
+	// Transport sends requests when recording. Defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// RedactHeaders are request and response headers of which values are
+	// redacted, in addition to the Authorization header.
+	RedactHeaders []string
+
+	// RedactFields are names of JSON object fields, at any depth, of which values
+	// are redacted in request and response bodies, e.g. "email".
+	RedactFields []string
+}
+
+// Cassette contains recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request. JSON bodies are stored as is, other
+// bodies as text.
+type Request struct {
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	Query    string          `json:"query,omitempty"`
+	Header   http.Header     `json:"header,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	BodyText string          `json:"body_text,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	StatusCode int             `json:"status_code"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	BodyText   string          `json:"body_text,omitempty"`
+}
+
+// Recorder records or replays interactions. It implements http.RoundTripper.
+type Recorder struct {
+	path string
+	mode Mode
+	opts Options
+
+	mu       sync.Mutex
+	cassette Cassette
+	replayed []bool
+}
+
+// New returns a Recorder for the cassette file at path.
+func New(path string, opts Options) (*Recorder, error) {
+	if opts.Transport == nil {
+		opts.Transport = http.DefaultTransport
+	}
+
+	r := &Recorder{path: path, mode: opts.Mode, opts: opts}
+
+	if r.mode == ModeAuto {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+
+	if r.mode == ModeReplay {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("notionrecord: failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(b, &r.cassette); err != nil {
+			return nil, fmt.Errorf("notionrecord: failed to parse cassette: %w", err)
+		}
+		r.replayed = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the mode of the recorder, which is ModeReplay or ModeRecord.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// HTTPClient returns an http.Client that uses the recorder as transport.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Cassette returns a copy of the recorded or replayed interactions.
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Stop saves the cassette, when recording.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("notionrecord: failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("notionrecord: failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("notionrecord: failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recReq, err := r.request(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recReq)
+	}
+
+	return r.record(req, recReq)
+}
+
+func (r *Recorder) replay(req *http.Request, recReq Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] || !matches(interaction.Request, recReq) {
+			continue
+		}
+		r.replayed[i] = true
+
+		return interaction.Response.httpResponse(req), nil
+	}
+
+	return nil, fmt.Errorf("%w: %v %v", ErrNoInteraction, req.Method, req.URL.RequestURI())
+}
+
+func (r *Recorder) record(req *http.Request, recReq Request) (*http.Response, error) {
+	res, err := r.opts.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("notionrecord: failed to read response body: %w", err)
+	}
+
+	recRes := Response{
+		StatusCode: res.StatusCode,
+		Header:     r.redactHeader(res.Header),
+	}
+	// Normalized bodies can differ in length.
+	recRes.Header.Del("Content-Length")
+	recRes.Body, recRes.BodyText = r.redactBody(body)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{Request: recReq, Response: recRes})
+	r.mu.Unlock()
+
+	// The caller gets the response as sent, without redactions.
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	return res, nil
+}
+
+// request returns the (redacted) recording of a request, restoring its body.
+func (r *Recorder) request(req *http.Request) (Request, error) {
+	recReq := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+		Header: r.redactHeader(req.Header),
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return Request{}, fmt.Errorf("notionrecord: failed to read request body: %w", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		if boundary := multipartBoundaryOf(req.Header.Get("Content-Type")); boundary != "" {
+			body = bytes.ReplaceAll(body, []byte(boundary), []byte(multipartBoundary))
+			recReq.Header.Set("Content-Type", strings.Replace(recReq.Header.Get("Content-Type"), boundary, multipartBoundary, 1))
+		}
+
+		recReq.Body, recReq.BodyText = r.redactBody(body)
+	}
+
+	return recReq, nil
+}
+
+// multipartBoundaryOf returns the boundary of a multipart content type, or an
+// empty string for other content types.
+func multipartBoundaryOf(contentType string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return ""
+	}
+	return params["boundary"]
+}
+
+func (r *Recorder) redactHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	redacted := header.Clone()
+	for _, name := range append([]string{"Authorization"}, r.opts.RedactHeaders...) {
+		if redacted.Get(name) == "" {
+			continue
+		}
+		if strings.EqualFold(name, "Authorization") {
+			redacted.Set(name, "Bearer "+Redacted)
+		} else {
+			redacted.Set(name, Redacted)
+		}
+	}
+
+	return redacted
+}
+
+// redactBody returns a JSON body with redacted fields, normalized (compact, with
+// sorted object keys), or returns other bodies as text.
+func (r *Recorder) redactBody(body []byte) (json.RawMessage, string) {
+	if len(body) == 0 {
+		return nil, ""
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, string(body)
+	}
+
+	if len(r.opts.RedactFields) > 0 {
+		fields := make(map[string]bool, len(r.opts.RedactFields))
+		for _, name := range r.opts.RedactFields {
+			fields[name] = true
+		}
+		v = redact(v, fields)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, string(body)
+	}
+
+	return b, ""
+}
+
+func redact(v interface{}, fields map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for name, value := range v {
+			if fields[name] && value != nil {
+				v[name] = Redacted
+			} else {
+				v[name] = redact(value, fields)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redact(value, fields)
+		}
+	}
+
+	return v
+}
+
+// matches reports whether a recorded request matches a request by method, path,
+// query and body. JSON bodies are compared after normalization, so differences
+// in whitespace and object key order don't matter. Multipart bodies are
+// recorded with a fixed boundary, so they match too.
+func matches(recorded, req Request) bool {
+	// Queries are encoded with sorted keys, so they can be compared as is.
+	if recorded.Method != req.Method ||
+		recorded.Path != req.Path ||
+		recorded.Query != req.Query ||
+		recorded.BodyText != req.BodyText {
+		return false
+	}
+
+	if len(recorded.Body) == 0 || len(req.Body) == 0 {
+		return len(recorded.Body) == len(req.Body)
+	}
+
+	return equalJSON(recorded.Body, req.Body)
+}
+
+func equalJSON(a, b []byte) bool {
+	var va, vb interface{}
+	if err := json.Unmarshal(a, &va); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		return false
+	}
+
+	na, _ := json.Marshal(va)
+	nb, _ := json.Marshal(vb)
+
+	return bytes.Equal(na, nb)
+}
+
+func (res Response) httpResponse(req *http.Request) *http.Response {
+	body := []byte(res.BodyText)
+	if len(res.Body) > 0 {
+		body = res.Body
+	}
+
+	header := res.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%v %v", res.StatusCode, http.StatusText(res.StatusCode)),
+		StatusCode:    res.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
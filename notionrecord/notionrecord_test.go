@@ -0,0 +1,202 @@
+package notionrecord_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/notionrecord"
+)
+
+type mockRoundtripper struct {
+	fn func(*http.Request) (*http.Response, error)
+}
+
+func (m *mockRoundtripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	return m.fn(r)
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	rec, err := notionrecord.New("testdata/cassette.json", notionrecord.Options{Mode: notionrecord.ModeReplay})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := notion.NewClient("secret-api-key", notion.WithHTTPClient(rec.HTTPClient()))
+
+	result, err := client.QueryDatabase(context.Background(), "39ddfc9d-33c9-404c-89cf-79f01c42dd0c", &notion.DatabaseQuery{
+		Filter: &notion.DatabaseQueryFilter{
+			Property: "Done",
+			DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{
+				Checkbox: &notion.CheckboxDatabaseQueryFilter{Equals: notion.BoolPtr(true)},
+			},
+		},
+		PageSize: 10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Results) != 1 {
+		t.Fatalf("results not equal (expected: 1, got: %v)", len(result.Results))
+	}
+	if exp, got := "Foobar", result.Results[0].Title(); exp != got {
+		t.Fatalf("title not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	children, err := client.FindBlockChildrenByID(context.Background(), "b0668f48-8d66-4733-9bdb-2f82215707f7", &notion.PaginationQuery{PageSize: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp, got := "Lorem ipsum dolor sit amet.", notion.PlainText(notion.BlockRichText(children.Results[0])); exp != got {
+		t.Fatalf("block text not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	// Each interaction is replayed once.
+	_, err = client.FindBlockChildrenByID(context.Background(), "b0668f48-8d66-4733-9bdb-2f82215707f7", &notion.PaginationQuery{PageSize: 10})
+	if !errors.Is(err, notionrecord.ErrNoInteraction) {
+		t.Fatalf("expected notionrecord.ErrNoInteraction, got: %v", err)
+	}
+
+	// Requests with a different body don't match.
+	_, err = client.QueryDatabase(context.Background(), "39ddfc9d-33c9-404c-89cf-79f01c42dd0c", &notion.DatabaseQuery{PageSize: 20})
+	if !errors.Is(err, notionrecord.ErrNoInteraction) {
+		t.Fatalf("expected notionrecord.ErrNoInteraction, got: %v", err)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	var requests int
+	transport := &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     http.StatusText(http.StatusOK),
+			Header:     http.Header{"Content-Type": {"application/json"}, "X-Request-Id": {"foobar"}},
+			Body: ioutil.NopCloser(strings.NewReader(`{
+				"object": "user",
+				"id": "be32af0c-8f4e-4a5e-8f4b-0fe5f6d3f19d",
+				"type": "person",
+				"name": "John Doe",
+				"person": {"email": "john@example.com"}
+			}`)),
+		}, nil
+	}}
+
+	rec, err := notionrecord.New(path, notionrecord.Options{
+		Transport:     transport,
+		RedactHeaders: []string{"X-Request-Id"},
+		RedactFields:  []string{"email"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp, got := notionrecord.ModeRecord, rec.Mode(); exp != got {
+		t.Fatalf("mode not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	client := notion.NewClient("secret-api-key", notion.WithHTTPClient(rec.HTTPClient()))
+
+	user, err := client.FindUserByID(context.Background(), "be32af0c-8f4e-4a5e-8f4b-0fe5f6d3f19d")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The response isn't redacted while recording.
+	if exp, got := "john@example.com", user.Person.Email; exp != got {
+		t.Fatalf("email not equal (expected: %v, got: %v)", exp, got)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret-api-key", "john@example.com", "foobar"} {
+		if strings.Contains(string(b), secret) {
+			t.Fatalf("expected %q to be redacted, got:\n%s", secret, b)
+		}
+	}
+
+	rec, err = notionrecord.New(path, notionrecord.Options{Transport: transport})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp, got := notionrecord.ModeReplay, rec.Mode(); exp != got {
+		t.Fatalf("mode not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	client = notion.NewClient("other-api-key", notion.WithHTTPClient(rec.HTTPClient()))
+
+	replayed, err := client.FindUserByID(context.Background(), "be32af0c-8f4e-4a5e-8f4b-0fe5f6d3f19d")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user.Person.Email = notionrecord.Redacted
+	if diff := cmp.Diff(user, replayed); diff != "" {
+		t.Fatalf("user not equal (-exp, +got):\n%v", diff)
+	}
+	if requests != 1 {
+		t.Fatalf("requests not equal (expected: 1, got: %v)", requests)
+	}
+}
+
+func TestRecordMultipart(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	transport := &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     http.StatusText(http.StatusOK),
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"object": "file_upload", "id": "43833259-72ae-404e-8441-b6577f3159b4", "status": "uploaded"}`)),
+		}, nil
+	}}
+
+	send := func(rec *notionrecord.Recorder) (notion.FileUpload, error) {
+		client := notion.NewClient("secret-api-key", notion.WithHTTPClient(rec.HTTPClient()))
+		return client.SendFileUpload(context.Background(), "43833259-72ae-404e-8441-b6577f3159b4", notion.SendFileUploadParams{
+			Content:     strings.NewReader("Hello, world!"),
+			Filename:    "hello.txt",
+			ContentType: "text/plain",
+		})
+	}
+
+	rec, err := notionrecord.New(path, notionrecord.Options{Transport: transport})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := send(rec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The replayed request has another (random) boundary.
+	rec, err = notionrecord.New(path, notionrecord.Options{Mode: notionrecord.ModeReplay})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	upload, err := send(rec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp, got := notion.FileUploadStatusUploaded, upload.Status; exp != got {
+		t.Fatalf("status not equal (expected: %v, got: %v)", exp, got)
+	}
+}
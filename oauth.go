@@ -0,0 +1,283 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// tokenExpiryDelta is how long before their expiry OAuth tokens are refreshed.
+const tokenExpiryDelta = time.Minute
+
+// TokenSource provides the bearer token for each request of a Client, e.g. to
+// use OAuth access tokens that are refreshed or rotated.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc is an adapter to use a func as TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token implements TokenSource.
+func (fn TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return fn(ctx)
+}
+
+// WithTokenSource makes the client get the bearer token of each request from
+// ts, instead of using the API key passed to NewClient.
+func WithTokenSource(ts TokenSource) ClientOption {
+	return func(c *Client) {
+		c.tokenSource = ts
+	}
+}
+
+// OAuthConfig is the configuration of a public integration, used for the OAuth
+// authorization flow.
+// See: https://developers.notion.com/docs/authorization#public-integration-auth-flow-set-up
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
+
+	// HTTPClient is used for requests to the OAuth endpoints. Defaults to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// OAuthToken is the response of exchanging an authorization code or refreshing
+// a token.
+// See: https://developers.notion.com/reference/create-a-token
+type OAuthToken struct {
+	AccessToken          string   `json:"access_token"`
+	TokenType            string   `json:"token_type"`
+	RefreshToken         string   `json:"refresh_token,omitempty"`
+	ExpiresIn            int64    `json:"expires_in,omitempty"`
+	BotID                string   `json:"bot_id"`
+	WorkspaceID          string   `json:"workspace_id"`
+	WorkspaceName        *string  `json:"workspace_name"`
+	WorkspaceIcon        *string  `json:"workspace_icon"`
+	Owner                BotOwner `json:"owner"`
+	DuplicatedTemplateID *string  `json:"duplicated_template_id"`
+	RequestID            string   `json:"request_id,omitempty"`
+
+	// Expiry is when the access token expires, derived from ExpiresIn when the
+	// token is received. It's zero for tokens that don't expire.
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// Expired returns true if the token expires within a minute. Tokens without
+// expiry never expire.
+func (t OAuthToken) Expired() bool {
+	return !t.Expiry.IsZero() && time.Now().Add(tokenExpiryDelta).After(t.Expiry)
+}
+
+// TokenIntrospection describes an OAuth token.
+// See: https://developers.notion.com/reference/introspect-token
+type TokenIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// AuthCodeURL returns the URL of the authorization page, to which users are
+// sent to add the integration to their workspace. The state is passed back to
+// the redirect URI, and should be verified to prevent CSRF attacks.
+// See: https://developers.notion.com/docs/authorization#step-1-navigate-the-user-to-the-integrations-authorization-url
+func (cfg OAuthConfig) AuthCodeURL(state string) string {
+	q := url.Values{}
+	q.Set("client_id", cfg.ClientID)
+	q.Set("response_type", "code")
+	q.Set("owner", "user")
+	if cfg.RedirectURI != "" {
+		q.Set("redirect_uri", cfg.RedirectURI)
+	}
+	if state != "" {
+		q.Set("state", state)
+	}
+
+	return baseURL + "/oauth/authorize?" + q.Encode()
+}
+
+// Exchange exchanges an authorization code, as passed to the redirect URI, for
+// an access token.
+// See: https://developers.notion.com/reference/create-a-token
+func (cfg OAuthConfig) Exchange(ctx context.Context, code string) (OAuthToken, error) {
+	params := map[string]string{
+		"grant_type": "authorization_code",
+		"code":       code,
+	}
+	if cfg.RedirectURI != "" {
+		params["redirect_uri"] = cfg.RedirectURI
+	}
+
+	var token OAuthToken
+	if err := cfg.post(ctx, "/oauth/token", params, &token); err != nil {
+		return OAuthToken{}, fmt.Errorf("notion: failed to exchange authorization code: %w", err)
+	}
+	token.setExpiry()
+
+	return token, nil
+}
+
+// Refresh returns a new access token for a refresh token.
+// See: https://developers.notion.com/reference/refresh-a-token
+func (cfg OAuthConfig) Refresh(ctx context.Context, refreshToken string) (OAuthToken, error) {
+	params := map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": refreshToken,
+	}
+
+	var token OAuthToken
+	if err := cfg.post(ctx, "/oauth/token", params, &token); err != nil {
+		return OAuthToken{}, fmt.Errorf("notion: failed to refresh token: %w", err)
+	}
+	token.setExpiry()
+
+	return token, nil
+}
+
+// Revoke revokes an access token.
+// See: https://developers.notion.com/reference/revoke-token
+func (cfg OAuthConfig) Revoke(ctx context.Context, token string) error {
+	if err := cfg.post(ctx, "/oauth/revoke", map[string]string{"token": token}, nil); err != nil {
+		return fmt.Errorf("notion: failed to revoke token: %w", err)
+	}
+
+	return nil
+}
+
+// Introspect returns whether a token is active, and its scope.
+// See: https://developers.notion.com/reference/introspect-token
+func (cfg OAuthConfig) Introspect(ctx context.Context, token string) (TokenIntrospection, error) {
+	var result TokenIntrospection
+	if err := cfg.post(ctx, "/oauth/introspect", map[string]string{"token": token}, &result); err != nil {
+		return TokenIntrospection{}, fmt.Errorf("notion: failed to introspect token: %w", err)
+	}
+
+	return result, nil
+}
+
+// TokenSource returns a TokenSource that provides the access token of token,
+// and refreshes it when it expires, if it has a refresh token. The optional
+// onRefresh func is called with each refreshed token, e.g. to persist it.
+func (cfg OAuthConfig) TokenSource(token OAuthToken, onRefresh func(OAuthToken)) TokenSource {
+	return &oauthTokenSource{cfg: cfg, token: token, onRefresh: onRefresh}
+}
+
+type oauthTokenSource struct {
+	cfg       OAuthConfig
+	onRefresh func(OAuthToken)
+
+	mu    sync.Mutex
+	token OAuthToken
+}
+
+// Token implements TokenSource.
+func (ts *oauthTokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if !ts.token.Expired() || ts.token.RefreshToken == "" {
+		return ts.token.AccessToken, nil
+	}
+
+	token, err := ts.cfg.Refresh(ctx, ts.token.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+	ts.token = token
+
+	if ts.onRefresh != nil {
+		ts.onRefresh(token)
+	}
+
+	return token.AccessToken, nil
+}
+
+func (t *OAuthToken) setExpiry() {
+	if t.ExpiresIn > 0 {
+		t.Expiry = time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+}
+
+// post sends a request to an OAuth endpoint, authenticated with the client ID
+// and secret, and decodes the response into result, if not nil.
+func (cfg OAuthConfig) post(ctx context.Context, path string, params map[string]string, result interface{}) error {
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return errors.New("client ID and secret are required")
+	}
+
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(params); err != nil {
+		return fmt.Errorf("failed to encode body params to JSON: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+
+	req.SetBasicAuth(cfg.ClientID, cfg.ClientSecret)
+	req.Header.Set("Notion-Version", apiVersion)
+	req.Header.Set("User-Agent", "go-notion/"+clientVersion)
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make HTTP request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return parseOAuthErrorResponse(res)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to parse HTTP response: %w", err)
+	}
+
+	return nil
+}
+
+// parseOAuthErrorResponse parses an error response of an OAuth endpoint. These
+// are either API errors, or OAuth errors as described by RFC 6749, which are
+// mapped onto APIError: `error` as Code and `error_description` as Message.
+// See: https://www.rfc-editor.org/rfc/rfc6749#section-5.2
+func parseOAuthErrorResponse(res *http.Response) error {
+	var errResp struct {
+		APIError
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&errResp); err != nil {
+		return &APIError{Status: res.StatusCode}
+	}
+
+	apiErr := errResp.APIError
+	if apiErr.Code == "" {
+		apiErr.Code = errResp.Error
+		apiErr.Message = errResp.ErrorDescription
+	}
+	if apiErr.Status == 0 {
+		apiErr.Status = res.StatusCode
+	}
+
+	return &apiErr
+}
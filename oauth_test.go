@@ -0,0 +1,214 @@
+package notion_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/skedida/go-notion"
+)
+
+func TestOAuthConfigAuthCodeURL(t *testing.T) {
+	t.Parallel()
+
+	cfg := notion.OAuthConfig{
+		ClientID:    "client-id",
+		RedirectURI: "https://example.com/callback",
+	}
+
+	exp := "https://api.notion.com/v1/oauth/authorize?client_id=client-id&owner=user&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&state=foobar"
+	if got := cfg.AuthCodeURL("foobar"); exp != got {
+		t.Fatalf("URL not equal (expected: %v, got: %v)", exp, got)
+	}
+}
+
+func TestOAuthConfigExchange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		respBody    string
+		respStatus  int
+		expToken    notion.OAuthToken
+		expExpiry   bool
+		expErr      error
+		expPostBody map[string]string
+	}{
+		{
+			name:       "successful response",
+			respStatus: http.StatusOK,
+			respBody: `{
+				"access_token": "secret_foo",
+				"token_type": "bearer",
+				"refresh_token": "refresh_foo",
+				"expires_in": 3600,
+				"bot_id": "b3414d65-1224-4c65-a0f4-66f1d6b1f8c5",
+				"workspace_id": "c3a1ec3c-4c5f-4c9b-8a41-a2b4bb3a0ad4",
+				"workspace_name": "Acme",
+				"workspace_icon": "🚀",
+				"owner": {"type": "user", "user": {"object": "user", "id": "be32af0c-8f4e-4a5e-8f4b-0fe5f6d3f19d"}},
+				"duplicated_template_id": null,
+				"request_id": "foobar"
+			}`,
+			expToken: notion.OAuthToken{
+				AccessToken:   "secret_foo",
+				TokenType:     "bearer",
+				RefreshToken:  "refresh_foo",
+				ExpiresIn:     3600,
+				BotID:         "b3414d65-1224-4c65-a0f4-66f1d6b1f8c5",
+				WorkspaceID:   "c3a1ec3c-4c5f-4c9b-8a41-a2b4bb3a0ad4",
+				WorkspaceName: notion.StringPtr("Acme"),
+				WorkspaceIcon: notion.StringPtr("🚀"),
+				Owner: notion.BotOwner{
+					Type: notion.BotOwnerTypeUser,
+					User: &notion.User{BaseUser: notion.BaseUser{ID: "be32af0c-8f4e-4a5e-8f4b-0fe5f6d3f19d"}},
+				},
+				RequestID: "foobar",
+			},
+			expExpiry: true,
+			expPostBody: map[string]string{
+				"grant_type":   "authorization_code",
+				"code":         "code",
+				"redirect_uri": "https://example.com/callback",
+			},
+		},
+		{
+			name:       "error response",
+			respStatus: http.StatusBadRequest,
+			respBody: `{
+				"object": "error",
+				"status": 400,
+				"code": "invalid_grant",
+				"message": "Invalid code."
+			}`,
+			expErr: &notion.APIError{Object: "error", Status: 400, Code: "invalid_grant", Message: "Invalid code."},
+		},
+		{
+			name:       "OAuth error response",
+			respStatus: http.StatusBadRequest,
+			respBody: `{
+				"error": "invalid_grant",
+				"error_description": "Invalid code."
+			}`,
+			expErr: &notion.APIError{Status: 400, Code: "invalid_grant", Message: "Invalid code."},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := notion.OAuthConfig{
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				RedirectURI:  "https://example.com/callback",
+				HTTPClient: &http.Client{
+					Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+						if exp, got := "https://api.notion.com/v1/oauth/token", r.URL.String(); exp != got {
+							t.Fatalf("URL not equal (expected: %v, got: %v)", exp, got)
+						}
+						if user, pass, ok := r.BasicAuth(); !ok || user != "client-id" || pass != "client-secret" {
+							t.Fatalf("expected basic auth with client credentials, got: %v:%v", user, pass)
+						}
+
+						if tt.expPostBody != nil {
+							var postBody map[string]string
+							if err := json.NewDecoder(r.Body).Decode(&postBody); err != nil {
+								t.Fatal(err)
+							}
+							if diff := cmp.Diff(tt.expPostBody, postBody); diff != "" {
+								t.Fatalf("post body not equal (-exp, +got):\n%v", diff)
+							}
+						}
+
+						return &http.Response{
+							StatusCode: tt.respStatus,
+							Status:     http.StatusText(tt.respStatus),
+							Body:       ioutil.NopCloser(strings.NewReader(tt.respBody)),
+						}, nil
+					}},
+				},
+			}
+
+			token, err := cfg.Exchange(context.Background(), "code")
+
+			if tt.expErr != nil {
+				var apiErr *notion.APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("expected *notion.APIError, got: %v", err)
+				}
+				if diff := cmp.Diff(tt.expErr, apiErr); diff != "" {
+					t.Fatalf("error not equal (-exp, +got):\n%v", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.expToken, token, cmpopts.IgnoreFields(notion.OAuthToken{}, "Expiry")); diff != "" {
+				t.Fatalf("token not equal (-exp, +got):\n%v", diff)
+			}
+			if tt.expExpiry && !token.Expiry.After(time.Now().Add(59*time.Minute)) {
+				t.Fatalf("expected expiry in an hour, got: %v", token.Expiry)
+			}
+		})
+	}
+}
+
+func TestOAuthTokenSource(t *testing.T) {
+	t.Parallel()
+
+	var authHeaders []string
+
+	httpClient := &http.Client{
+		Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+			body := `{"object": "user", "id": "be32af0c-8f4e-4a5e-8f4b-0fe5f6d3f19d", "type": "bot", "bot": {}}`
+			if r.URL.Path == "/v1/oauth/token" {
+				body = `{"access_token": "secret_new", "refresh_token": "refresh_new", "expires_in": 3600}`
+			} else {
+				authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Status:     http.StatusText(http.StatusOK),
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+			}, nil
+		}},
+	}
+
+	cfg := notion.OAuthConfig{ClientID: "client-id", ClientSecret: "client-secret", HTTPClient: httpClient}
+
+	var refreshed []notion.OAuthToken
+	ts := cfg.TokenSource(notion.OAuthToken{
+		AccessToken:  "secret_old",
+		RefreshToken: "refresh_old",
+		Expiry:       time.Now().Add(10 * time.Second),
+	}, func(token notion.OAuthToken) {
+		refreshed = append(refreshed, token)
+	})
+
+	client := notion.NewClient("", notion.WithHTTPClient(httpClient), notion.WithTokenSource(ts))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.FindCurrentUser(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if diff := cmp.Diff([]string{"Bearer secret_new", "Bearer secret_new"}, authHeaders); diff != "" {
+		t.Fatalf("authorization headers not equal (-exp, +got):\n%v", diff)
+	}
+	if len(refreshed) != 1 || refreshed[0].RefreshToken != "refresh_new" {
+		t.Fatalf("expected one refreshed token, got: %#v", refreshed)
+	}
+}
@@ -64,8 +64,15 @@ type DatabasePageProperty struct {
 	CreatedBy      *User           `json:"created_by,omitempty"`
 	LastEditedTime *time.Time      `json:"last_edited_time,omitempty"`
 	LastEditedBy   *User           `json:"last_edited_by,omitempty"`
+
+	// Raw holds the original JSON of a property with a type that is unknown to
+	// this library. It is returned as is by MarshalJSON.
+	Raw json.RawMessage `json:"-"`
 }
 
+// ErrUnknownPropertyType is used when encountering an unknown property type.
+var ErrUnknownPropertyType = errors.New("unknown property type")
+
 // CreatePageParams are the params used for creating a page.
 type CreatePageParams struct {
 	ParentType ParentType
@@ -190,6 +197,65 @@ func (prop DatabasePageProperty) Value() interface{} {
 	}
 }
 
+// UnmarshalJSON implements json.Unmarshaler. When the property type is unknown
+// to this library, the original JSON is retained in the Raw field.
+func (prop *DatabasePageProperty) UnmarshalJSON(b []byte) error {
+	type propAlias DatabasePageProperty
+
+	var alias propAlias
+
+	if err := json.Unmarshal(b, &alias); err != nil {
+		return err
+	}
+
+	*prop = DatabasePageProperty(alias)
+
+	if prop.Type != "" && !prop.Type.known() {
+		prop.Raw = append(json.RawMessage(nil), b...)
+	}
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (prop DatabasePageProperty) MarshalJSON() ([]byte, error) {
+	if prop.Raw != nil {
+		return prop.Raw, nil
+	}
+
+	type propAlias DatabasePageProperty
+
+	return json.Marshal(propAlias(prop))
+}
+
+// unknownPropertyError returns an error for the first property with an unknown
+// type, including properties nested in rollup arrays, or nil if there is none.
+func (props DatabasePageProperties) unknownPropertyError() error {
+	for name, prop := range props {
+		if err := prop.unknownTypeError(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (prop DatabasePageProperty) unknownTypeError(name string) error {
+	if prop.Raw != nil {
+		return fmt.Errorf("notion: failed to parse property (name: %q, type: %q): %w", name, prop.Type, ErrUnknownPropertyType)
+	}
+
+	if prop.Rollup != nil {
+		for _, item := range prop.Rollup.Array {
+			if err := item.unknownTypeError(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (p CreatePageParams) Validate() error {
 	if p.ParentType == "" {
 		return errors.New("parent type is required")
@@ -213,7 +279,6 @@ func (p CreatePageParams) Validate() error {
 			return err
 		}
 	}
-
 	return nil
 }
 
@@ -303,6 +368,176 @@ func (p *Page) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// DatabaseProperties returns the properties of a page whose parent is a
+// database. The boolean is false for other pages.
+func (p Page) DatabaseProperties() (DatabasePageProperties, bool) {
+	props, ok := p.Properties.(DatabasePageProperties)
+	return props, ok
+}
+
+// TitleRichText returns the title of the page, regardless of its parent type.
+func (p Page) TitleRichText() []RichText {
+	switch props := p.Properties.(type) {
+	case PageProperties:
+		return props.Title.Title
+	case DatabasePageProperties:
+		if name, ok := props.titleName(); ok {
+			return props[name].Title
+		}
+	}
+
+	return nil
+}
+
+// Title returns the plain text title of the page.
+func (p Page) Title() string {
+	return PlainText(p.TitleRichText())
+}
+
+// Property returns a page property by name. For pages whose parent is not a
+// database, only the `title` property exists. The Name field of the returned
+// property is set to name.
+func (p Page) Property(name string) (DatabasePageProperty, bool) {
+	var (
+		prop DatabasePageProperty
+		ok   bool
+	)
+
+	switch props := p.Properties.(type) {
+	case PageProperties:
+		if name == "title" {
+			prop, ok = props.property(), true
+		}
+	case DatabasePageProperties:
+		prop, ok = props[name]
+	}
+
+	if ok {
+		prop.Name = name
+	}
+
+	return prop, ok
+}
+
+// PropertyByID returns a page property by ID. The Name field of the returned
+// property is set to the property name.
+func (p Page) PropertyByID(id string) (DatabasePageProperty, bool) {
+	name, ok := p.propertyName(id)
+	if !ok {
+		return DatabasePageProperty{}, false
+	}
+	return p.Property(name)
+}
+
+// SetTitle sets the title of the page, regardless of its parent type, and
+// returns params for persisting the change with `Client.UpdatePage`. An error is
+// returned for a database page without a title property.
+func (p *Page) SetTitle(title []RichText) (UpdatePageParams, error) {
+	if props, ok := p.DatabaseProperties(); ok {
+		name, ok := props.titleName()
+		if !ok {
+			return UpdatePageParams{}, errors.New("page has no title property")
+		}
+		return p.SetProperty(name, DatabasePageProperty{
+			Type:  DBPropTypeTitle,
+			Title: title,
+		})
+	}
+
+	props := PageProperties{
+		Title: PageTitle{
+			Title: title,
+		},
+	}
+	p.Properties = props
+
+	return UpdatePageParams{
+		DatabasePageProperties: DatabasePageProperties{
+			"title": props.property(),
+		},
+	}, nil
+}
+
+// SetProperty sets a property of a page whose parent is a database, and returns
+// params for persisting the change with `Client.UpdatePage`. The property must
+// exist on the page; its ID and type are used when not set on prop.
+func (p *Page) SetProperty(name string, prop DatabasePageProperty) (UpdatePageParams, error) {
+	props, ok := p.DatabaseProperties()
+	if !ok {
+		return UpdatePageParams{}, errors.New("page parent is not a database")
+	}
+
+	existing, ok := props[name]
+	if !ok {
+		return UpdatePageParams{}, fmt.Errorf("page has no property named %q", name)
+	}
+
+	if prop.ID == "" {
+		prop.ID = existing.ID
+	}
+	if prop.Type == "" {
+		prop.Type = existing.Type
+	}
+	prop.Name = ""
+
+	updated := make(DatabasePageProperties, len(props))
+	for k, v := range props {
+		updated[k] = v
+	}
+	updated[name] = prop
+	p.Properties = updated
+
+	return UpdatePageParams{
+		DatabasePageProperties: DatabasePageProperties{
+			name: prop,
+		},
+	}, nil
+}
+
+// SetPropertyByID is like SetProperty, but looks up the property by ID.
+func (p *Page) SetPropertyByID(id string, prop DatabasePageProperty) (UpdatePageParams, error) {
+	name, ok := p.propertyName(id)
+	if !ok {
+		return UpdatePageParams{}, fmt.Errorf("page has no property with ID %q", id)
+	}
+	return p.SetProperty(name, prop)
+}
+
+// propertyName returns the name of a page property by ID.
+func (p Page) propertyName(id string) (string, bool) {
+	switch props := p.Properties.(type) {
+	case PageProperties:
+		return "title", id == "title"
+	case DatabasePageProperties:
+		for name, prop := range props {
+			if prop.ID == id {
+				return name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// titleName returns the name of the title property.
+func (props DatabasePageProperties) titleName() (string, bool) {
+	for name, prop := range props {
+		if prop.Type == DBPropTypeTitle {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// property returns the title as a page property.
+func (props PageProperties) property() DatabasePageProperty {
+	return DatabasePageProperty{
+		ID:    "title",
+		Type:  DBPropTypeTitle,
+		Title: props.Title.Title,
+	}
+}
+
 func (p UpdatePageParams) Validate() error {
 	// At least one of the params must be set.
 	if p.DatabasePageProperties == nil && p.Archived == nil && p.Icon == nil && p.Cover == nil {
@@ -0,0 +1,172 @@
+package notion_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+)
+
+func mustUnmarshalPage(data string) notion.Page {
+	var page notion.Page
+	if err := json.Unmarshal([]byte(data), &page); err != nil {
+		panic(err)
+	}
+	return page
+}
+
+func TestPageTitle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		page notion.Page
+	}{
+		{
+			name: "page parent",
+			page: mustUnmarshalPage(`{
+				"id": "1",
+				"parent": {"type": "page_id", "page_id": "2"},
+				"properties": {
+					"title": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "Foobar"}, "plain_text": "Foobar"}]}
+				}
+			}`),
+		},
+		{
+			name: "database parent",
+			page: mustUnmarshalPage(`{
+				"id": "1",
+				"parent": {"type": "database_id", "database_id": "2"},
+				"properties": {
+					"Done": {"id": "a%3Ab", "type": "checkbox", "checkbox": true},
+					"Name": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "Foobar"}, "plain_text": "Foobar"}]}
+				}
+			}`),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if exp, got := "Foobar", tt.page.Title(); exp != got {
+				t.Fatalf("title not equal (expected: %v, got: %v)", exp, got)
+			}
+
+			prop, ok := tt.page.PropertyByID("title")
+			if !ok {
+				t.Fatal("expected title property to be found")
+			}
+			if exp, got := "Foobar", notion.PlainText(prop.Title); exp != got {
+				t.Fatalf("title not equal (expected: %v, got: %v)", exp, got)
+			}
+
+			page := tt.page
+			params, err := page.SetTitle(richText("Baz"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if exp, got := "Baz", page.Title(); exp != got {
+				t.Fatalf("title not equal (expected: %v, got: %v)", exp, got)
+			}
+			if exp, got := "Foobar", tt.page.Title(); exp != got {
+				t.Fatalf("expected original page to be unchanged, got title %q", got)
+			}
+
+			body, err := json.Marshal(params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var decoded map[string]map[string]map[string]interface{}
+			if err := json.Unmarshal(body, &decoded); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(decoded["properties"]) != 1 {
+				t.Fatalf("expected a single property, got: %s", body)
+			}
+			for _, prop := range decoded["properties"] {
+				if prop["title"] == nil {
+					t.Fatalf("expected title property, got: %s", body)
+				}
+			}
+		})
+	}
+}
+
+func TestPageSetTitleWithoutTitleProperty(t *testing.T) {
+	t.Parallel()
+
+	page := mustUnmarshalPage(`{
+		"id": "1",
+		"parent": {"type": "database_id", "database_id": "2"},
+		"properties": {
+			"Done": {"id": "a%3Ab", "type": "checkbox", "checkbox": true}
+		}
+	}`)
+
+	if _, err := page.SetTitle(richText("Baz")); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	props, ok := page.DatabaseProperties()
+	if !ok {
+		t.Fatalf("expected database properties to be kept, got: %T", page.Properties)
+	}
+	if _, ok := props["Done"]; !ok {
+		t.Fatal("expected property to be kept")
+	}
+}
+
+func TestPageSetProperty(t *testing.T) {
+	t.Parallel()
+
+	page := mustUnmarshalPage(`{
+		"id": "1",
+		"parent": {"type": "database_id", "database_id": "2"},
+		"properties": {
+			"Done": {"id": "a%3Ab", "type": "checkbox", "checkbox": false},
+			"Name": {"id": "title", "type": "title", "title": []}
+		}
+	}`)
+
+	params, err := page.SetPropertyByID("a%3Ab", notion.DatabasePageProperty{
+		Checkbox: notion.BoolPtr(true),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := notion.UpdatePageParams{
+		DatabasePageProperties: notion.DatabasePageProperties{
+			"Done": notion.DatabasePageProperty{
+				ID:       "a%3Ab",
+				Type:     notion.DBPropTypeCheckbox,
+				Checkbox: notion.BoolPtr(true),
+			},
+		},
+	}
+	if diff := cmp.Diff(exp, params); diff != "" {
+		t.Fatalf("params not equal (-exp, +got):\n%v", diff)
+	}
+
+	prop, ok := page.Property("Done")
+	if !ok || prop.Checkbox == nil || !*prop.Checkbox {
+		t.Fatalf("expected property to be updated, got: %+v", prop)
+	}
+	if exp, got := "Done", prop.Name; exp != got {
+		t.Fatalf("name not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	if _, err := page.SetProperty("Foobar", notion.DatabasePageProperty{}); err == nil {
+		t.Fatal("expected error for unknown property, got nil")
+	}
+
+	workspacePage := mustUnmarshalPage(`{"id": "1", "parent": {"type": "workspace", "workspace": true}, "properties": {"title": {"title": []}}}`)
+	if _, err := workspacePage.SetProperty("title", notion.DatabasePageProperty{}); err == nil {
+		t.Fatal("expected error for page without database parent, got nil")
+	}
+}
@@ -0,0 +1,399 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRequestInterval corresponds to the average rate limit of the
+	// Notion API: three requests per second, per integration.
+	defaultRequestInterval = time.Second / 3
+
+	defaultMaxRetries  = 3
+	defaultRetryBudget = 10
+	defaultRetryDelay  = time.Second
+)
+
+// ClientPoolOptions configure a ClientPool.
+type ClientPoolOptions struct {
+	// TokenSource returns the token source of a workspace, e.g. using a stored
+	// OAuth token (see OAuthConfig.TokenSource). It's called when the client of
+	// a workspace is created. Required.
+	TokenSource func(ctx context.Context, key string) (TokenSource, error)
+
+	// HTTPClient is used for sending requests. Its transport is wrapped per
+	// client for rate limiting and retries. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// ClientOptions are applied to each created client.
+	ClientOptions []ClientOption
+
+	// RequestInterval is the minimum interval between requests of a client.
+	// Defaults to a third of a second.
+	RequestInterval time.Duration
+
+	// MaxRetries is the maximum number of retries of a request that was rate
+	// limited or failed with a 502, 503 or 504 status. Defaults to 3; use a
+	// negative value to disable retries.
+	MaxRetries int
+
+	// RetryBudget is the maximum number of retries per minute of a client,
+	// shared by its requests, so a failing workspace can't cause a retry storm.
+	// Defaults to 10.
+	RetryBudget int
+
+	// IdleTimeout evicts clients that weren't used for a while. Zero keeps
+	// clients until they're evicted explicitly.
+	IdleTimeout time.Duration
+}
+
+// PoolMetrics are aggregate metrics of the clients of a ClientPool.
+type PoolMetrics struct {
+	// Clients is the number of cached clients.
+	Clients int
+
+	Created int64
+	Evicted int64
+
+	// Requests is the number of HTTP requests sent, including retries.
+	Requests    int64
+	Retries     int64
+	RateLimited int64
+	// Errors is the number of requests that failed without a response.
+	Errors int64
+
+	// RateLimitWait is the total time requests waited for the rate limiter.
+	RateLimitWait time.Duration
+}
+
+// ClientPool lazily creates and caches a Client per workspace, e.g. for
+// public integrations with an OAuth token per workspace. Each client has its
+// own rate limiter and retry budget. Clients are keyed by a string of choice,
+// typically the workspace or bot ID. Clients that receive a 401 Unauthorized
+// response are evicted, so their token source is requested again.
+type ClientPool struct {
+	opts ClientPoolOptions
+	now  func() time.Time
+
+	mu      sync.Mutex
+	clients map[string]*pooledClient
+	metrics PoolMetrics
+}
+
+type pooledClient struct {
+	client   *Client
+	lastUsed time.Time
+}
+
+// NewClientPool returns a new ClientPool.
+func NewClientPool(opts ClientPoolOptions) *ClientPool {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	if opts.RequestInterval <= 0 {
+		opts.RequestInterval = defaultRequestInterval
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultMaxRetries
+	}
+	if opts.RetryBudget <= 0 {
+		opts.RetryBudget = defaultRetryBudget
+	}
+
+	return &ClientPool{
+		opts:    opts,
+		now:     time.Now,
+		clients: make(map[string]*pooledClient),
+	}
+}
+
+// Client returns the client of a workspace, creating it if needed.
+func (p *ClientPool) Client(ctx context.Context, key string) (*Client, error) {
+	p.mu.Lock()
+	p.evictIdle()
+	if pc, ok := p.clients[key]; ok {
+		pc.lastUsed = p.now()
+		p.mu.Unlock()
+		return pc.client, nil
+	}
+	p.mu.Unlock()
+
+	if p.opts.TokenSource == nil {
+		return nil, errors.New("notion: client pool token source is required")
+	}
+
+	ts, err := p.opts.TokenSource(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("notion: failed to get token source: %w", err)
+	}
+
+	transport := p.opts.HTTPClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	pt := &poolTransport{
+		pool:    p,
+		key:     key,
+		base:    transport,
+		limiter: &rateLimiter{interval: p.opts.RequestInterval},
+	}
+	httpClient := *p.opts.HTTPClient
+	httpClient.Transport = pt
+
+	opts := append(append([]ClientOption(nil), p.opts.ClientOptions...),
+		WithHTTPClient(&httpClient),
+		WithTokenSource(ts),
+	)
+	client := NewClient("", opts...)
+	pt.client = client
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Another goroutine may have created a client in the meantime.
+	if pc, ok := p.clients[key]; ok {
+		pc.lastUsed = p.now()
+		return pc.client, nil
+	}
+
+	p.clients[key] = &pooledClient{client: client, lastUsed: p.now()}
+	p.metrics.Created++
+
+	return client, nil
+}
+
+// Evict removes the client of a workspace, e.g. after its access was revoked.
+// A new client is created when it's requested again.
+func (p *ClientPool) Evict(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.evict(key)
+}
+
+// Revoke revokes the token of a workspace client, and evicts the client.
+func (p *ClientPool) Revoke(ctx context.Context, cfg OAuthConfig, key string) error {
+	p.mu.Lock()
+	pc, ok := p.clients[key]
+	p.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("notion: no client for %q in pool", key)
+	}
+
+	token, err := pc.client.tokenSource.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("notion: failed to get token: %w", err)
+	}
+	if err := cfg.Revoke(ctx, token); err != nil {
+		return err
+	}
+
+	p.Evict(key)
+
+	return nil
+}
+
+// Metrics returns aggregate metrics of the clients of the pool.
+func (p *ClientPool) Metrics() PoolMetrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	metrics := p.metrics
+	metrics.Clients = len(p.clients)
+
+	return metrics
+}
+
+// evictClient evicts the client of a workspace, if it's still the given client
+// and not a newer client that replaced it.
+func (p *ClientPool) evictClient(key string, client *Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pc, ok := p.clients[key]; ok && pc.client == client {
+		p.evict(key)
+	}
+}
+
+func (p *ClientPool) evict(key string) {
+	if _, ok := p.clients[key]; ok {
+		delete(p.clients, key)
+		p.metrics.Evicted++
+	}
+}
+
+func (p *ClientPool) evictIdle() {
+	if p.opts.IdleTimeout <= 0 {
+		return
+	}
+
+	for key, pc := range p.clients {
+		if p.now().Sub(pc.lastUsed) > p.opts.IdleTimeout {
+			p.evict(key)
+		}
+	}
+}
+
+// poolTransport rate limits and retries requests of a pooled client.
+type poolTransport struct {
+	pool *ClientPool
+	key  string
+	// client is the client using the transport.
+	client  *Client
+	base    http.RoundTripper
+	limiter *rateLimiter
+
+	mu      sync.Mutex
+	retries []time.Time
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *poolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		wait, err := t.limiter.wait(req.Context())
+		t.pool.record(func(m *PoolMetrics) { m.RateLimitWait += wait })
+		if err != nil {
+			return nil, err
+		}
+
+		// Retries send a clone with a fresh body, as RoundTrip must not modify
+		// the request.
+		send := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			send = req.Clone(req.Context())
+			send.Body = body
+		}
+
+		res, err := t.base.RoundTrip(send)
+		t.pool.record(func(m *PoolMetrics) {
+			m.Requests++
+			if err != nil {
+				m.Errors++
+			} else if res.StatusCode == http.StatusTooManyRequests {
+				m.RateLimited++
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if res.StatusCode == http.StatusUnauthorized {
+			t.pool.evictClient(t.key, t.client)
+			return res, nil
+		}
+
+		// Requests with a body can only be retried if it can be read again.
+		replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+		if !retryable(res.StatusCode) || attempt >= t.pool.opts.MaxRetries || !replayable || !t.takeRetry() {
+			return res, nil
+		}
+
+		delay := retryDelay(res, attempt)
+		res.Body.Close()
+
+		t.pool.record(func(m *PoolMetrics) { m.Retries++ })
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// takeRetry returns true if the retry budget allows another retry.
+func (t *poolTransport) takeRetry() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.pool.now()
+
+	recent := t.retries[:0]
+	for _, at := range t.retries {
+		if now.Sub(at) < time.Minute {
+			recent = append(recent, at)
+		}
+	}
+	t.retries = recent
+
+	if len(t.retries) >= t.pool.opts.RetryBudget {
+		return false
+	}
+	t.retries = append(t.retries, now)
+
+	return true
+}
+
+func (p *ClientPool) record(fn func(m *PoolMetrics)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fn(&p.metrics)
+}
+
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns the delay of the Retry-After header (in seconds), or an
+// exponential backoff.
+func retryDelay(res *http.Response, attempt int) time.Duration {
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	return defaultRetryDelay << attempt
+}
+
+// rateLimiter spaces events at least an interval apart.
+type rateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the next event is allowed, and returns how long it waited.
+func (l *rateLimiter) wait(ctx context.Context) (time.Duration, error) {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-timer.C:
+		return delay, nil
+	}
+}
@@ -0,0 +1,174 @@
+package notion_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/skedida/go-notion"
+)
+
+func TestClientPool(t *testing.T) {
+	t.Parallel()
+
+	userJSON := `{"object": "user", "id": "be32af0c-8f4e-4a5e-8f4b-0fe5f6d3f19d", "type": "bot", "bot": {}}`
+
+	newPool := func(fn func(r *http.Request) (*http.Response, error), tokenSources *[]string) *notion.ClientPool {
+		var mu sync.Mutex
+
+		return notion.NewClientPool(notion.ClientPoolOptions{
+			TokenSource: func(ctx context.Context, key string) (notion.TokenSource, error) {
+				mu.Lock()
+				*tokenSources = append(*tokenSources, key)
+				mu.Unlock()
+
+				return notion.TokenSourceFunc(func(ctx context.Context) (string, error) {
+					return "secret_" + key, nil
+				}), nil
+			},
+			HTTPClient:      &http.Client{Transport: &mockRoundtripper{fn: fn}},
+			RequestInterval: time.Millisecond,
+		})
+	}
+
+	response := func(status int, body string) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Header:     http.Header{"Retry-After": {"0"}},
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	t.Run("caches clients per key", func(t *testing.T) {
+		t.Parallel()
+
+		var tokenSources []string
+		var authHeaders []string
+
+		pool := newPool(func(r *http.Request) (*http.Response, error) {
+			authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+			return response(http.StatusOK, userJSON), nil
+		}, &tokenSources)
+
+		for _, key := range []string{"a", "b", "a"} {
+			client, err := pool.Client(context.Background(), key)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := client.FindCurrentUser(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if diff := cmp.Diff([]string{"a", "b"}, tokenSources); diff != "" {
+			t.Fatalf("token sources not equal (-exp, +got):\n%v", diff)
+		}
+		if diff := cmp.Diff([]string{"Bearer secret_a", "Bearer secret_b", "Bearer secret_a"}, authHeaders); diff != "" {
+			t.Fatalf("authorization headers not equal (-exp, +got):\n%v", diff)
+		}
+
+		pool.Evict("a")
+		if _, err := pool.Client(context.Background(), "a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		exp := notion.PoolMetrics{Clients: 2, Created: 3, Evicted: 1, Requests: 3}
+		if diff := cmp.Diff(exp, pool.Metrics(), cmpopts.IgnoreFields(notion.PoolMetrics{}, "RateLimitWait")); diff != "" {
+			t.Fatalf("metrics not equal (-exp, +got):\n%v", diff)
+		}
+	})
+
+	t.Run("retries rate limited requests", func(t *testing.T) {
+		t.Parallel()
+
+		var tokenSources []string
+		var bodies []string
+
+		pool := newPool(func(r *http.Request) (*http.Response, error) {
+			b, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bodies = append(bodies, string(b))
+
+			if len(bodies) < 3 {
+				return response(http.StatusTooManyRequests, `{"object": "error", "status": 429, "code": "rate_limited", "message": "Slow down."}`), nil
+			}
+			return response(http.StatusOK, `{"object": "list", "results": [], "has_more": false}`), nil
+		}, &tokenSources)
+
+		client, err := pool.Client(context.Background(), "a")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := client.Search(context.Background(), &notion.SearchOpts{Query: "foobar"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(bodies) != 3 || bodies[0] != bodies[2] || !strings.Contains(bodies[2], "foobar") {
+			t.Fatalf("expected request body to be sent three times, got: %q", bodies)
+		}
+
+		metrics := pool.Metrics()
+		if metrics.Requests != 3 || metrics.Retries != 2 || metrics.RateLimited != 2 {
+			t.Fatalf("unexpected metrics: %+v", metrics)
+		}
+	})
+
+	t.Run("evicts unauthorized clients", func(t *testing.T) {
+		t.Parallel()
+
+		var tokenSources []string
+
+		pool := newPool(func(r *http.Request) (*http.Response, error) {
+			return response(http.StatusUnauthorized, `{"object": "error", "status": 401, "code": "unauthorized", "message": "API token is invalid."}`), nil
+		}, &tokenSources)
+
+		client, err := pool.Client(context.Background(), "a")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := client.FindCurrentUser(context.Background()); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if exp, got := 0, pool.Metrics().Clients; exp != got {
+			t.Fatalf("clients not equal (expected: %v, got: %v)", exp, got)
+		}
+	})
+
+	t.Run("doesn't evict newer clients", func(t *testing.T) {
+		t.Parallel()
+
+		var tokenSources []string
+
+		pool := newPool(func(r *http.Request) (*http.Response, error) {
+			return response(http.StatusUnauthorized, `{"object": "error", "status": 401, "code": "unauthorized", "message": "API token is invalid."}`), nil
+		}, &tokenSources)
+
+		stale, err := pool.Client(context.Background(), "a")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pool.Evict("a")
+		if _, err := pool.Client(context.Background(), "a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// A late 401 of the evicted client leaves its replacement in the pool.
+		if _, err := stale.FindCurrentUser(context.Background()); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if exp, got := 1, pool.Metrics().Clients; exp != got {
+			t.Fatalf("clients not equal (expected: %v, got: %v)", exp, got)
+		}
+	})
+}
@@ -1,5 +1,7 @@
 package notion
 
+import "strings"
+
 type RichText struct {
 	// Custom metadata that can be used by clients of go-notion. It is not part of the Notion API and ignored when sent to the API.
 	CustomMetadata CustomMetadata `json:"-"`
@@ -118,3 +120,45 @@ const (
 	ColorPinkBg   Color = "pink_background"
 	ColorRedBg    Color = "red_background"
 )
+
+// PlainText returns the concatenated plain text of rich text elements. For
+// elements without a `plain_text` value (e.g. when constructed for a request),
+// the text content or equation expression is used.
+func PlainText(richText []RichText) string {
+	var sb strings.Builder
+
+	for _, rt := range richText {
+		switch {
+		case rt.PlainText != "":
+			sb.WriteString(rt.PlainText)
+		case rt.Text != nil:
+			sb.WriteString(rt.Text.Content)
+		case rt.Equation != nil:
+			sb.WriteString(rt.Equation.Expression)
+		}
+	}
+
+	return sb.String()
+}
+
+// SplitRichText returns text rich text elements for s, split into multiple
+// elements if s exceeds MaxRichTextLength characters. Nil is returned for an
+// empty string.
+func SplitRichText(s string) []RichText {
+	var richText []RichText
+
+	runes := []rune(s)
+	for len(runes) > 0 {
+		n := len(runes)
+		if n > MaxRichTextLength {
+			n = MaxRichTextLength
+		}
+		richText = append(richText, RichText{
+			Type: RichTextTypeText,
+			Text: &Text{Content: string(runes[:n])},
+		})
+		runes = runes[n:]
+	}
+
+	return richText
+}
@@ -0,0 +1,61 @@
+package notion_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+)
+
+func TestSplitRichText(t *testing.T) {
+	t.Parallel()
+
+	text := func(content string) notion.RichText {
+		return notion.RichText{Type: notion.RichTextTypeText, Text: &notion.Text{Content: content}}
+	}
+
+	tests := []struct {
+		name string
+		s    string
+		exp  []notion.RichText
+	}{
+		{
+			name: "empty",
+			s:    "",
+			exp:  nil,
+		},
+		{
+			name: "short",
+			s:    "Foobar",
+			exp:  []notion.RichText{text("Foobar")},
+		},
+		{
+			name: "maximum length",
+			s:    strings.Repeat("a", notion.MaxRichTextLength),
+			exp:  []notion.RichText{text(strings.Repeat("a", notion.MaxRichTextLength))},
+		},
+		{
+			name: "split by characters",
+			s:    strings.Repeat("é", notion.MaxRichTextLength) + "ü",
+			exp: []notion.RichText{
+				text(strings.Repeat("é", notion.MaxRichTextLength)),
+				text("ü"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.exp, notion.SplitRichText(tt.s)); diff != "" {
+				t.Fatalf("rich text not equal (-exp, +got):\n%v", diff)
+			}
+			if err := notion.ValidateBlocks([]notion.Block{notion.ParagraphBlock{RichText: notion.SplitRichText(tt.s)}}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors for page properties that don't match a database schema.
+// See: Database.ValidatePageProperties.
+var (
+	ErrUnknownProperty      = errors.New("property doesn't exist in database")
+	ErrReadOnlyProperty     = errors.New("property is read-only")
+	ErrPropertyTypeMismatch = errors.New("property value doesn't match property type")
+	ErrInvalidSelectOption  = errors.New("option doesn't exist")
+)
+
+// PropertyError is an error for a page property.
+type PropertyError struct {
+	Property string
+	Err      error
+}
+
+// Error implements `error`.
+func (err *PropertyError) Error() string {
+	return fmt.Sprintf("property %q: %v", err.Property, err.Err)
+}
+
+func (err *PropertyError) Unwrap() error {
+	return err.Err
+}
+
+// MultiError contains multiple errors, e.g. an error for each invalid page
+// property. Use errors.Is and errors.As to match the individual errors.
+type MultiError []error
+
+// Error implements `error`.
+func (errs MultiError) Error() string {
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%v errors: %v", len(errs), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors, for use with errors.Is and errors.As.
+func (errs MultiError) Unwrap() []error {
+	return errs
+}
+
+// Is reports whether any of the errors matches target, so that errors.Is works
+// with MultiError on Go versions without multi-error support.
+func (errs MultiError) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error that matches target, so that errors.As works with
+// MultiError on Go versions without multi-error support.
+func (errs MultiError) As(target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePageProperties checks page properties against the database schema:
+// each property must exist (by name or ID), must not be read-only (formula,
+// rollup, created and last edited time/by), must have a value of the property
+// type, and select, multi-select and status values must be existing options.
+// All invalid properties are returned as MultiError of *PropertyError values.
+func (db Database) ValidatePageProperties(props DatabasePageProperties) error {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs MultiError
+
+	for _, name := range names {
+		if err := db.validatePageProperty(name, props[name]); err != nil {
+			errs = append(errs, &PropertyError{Property: name, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (db Database) validatePageProperty(name string, prop DatabasePageProperty) error {
+	if prop.Raw != nil {
+		// Properties of a type unknown to this library are passed as is.
+		return nil
+	}
+
+	schema, ok := db.Properties[name]
+	if !ok {
+		for _, p := range db.Properties {
+			if p.ID != "" && p.ID == name {
+				schema, ok = p, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return ErrUnknownProperty
+	}
+
+	if schema.Type.readOnly() {
+		return fmt.Errorf("%w (%v)", ErrReadOnlyProperty, schema.Type)
+	}
+
+	if prop.Type != "" && prop.Type != schema.Type {
+		return fmt.Errorf("%w: expected %v, got %v", ErrPropertyTypeMismatch, schema.Type, prop.Type)
+	}
+	for _, t := range prop.valueTypes() {
+		if t != schema.Type {
+			return fmt.Errorf("%w: expected %v, got %v", ErrPropertyTypeMismatch, schema.Type, t)
+		}
+	}
+
+	switch schema.Type {
+	case DBPropTypeSelect:
+		if prop.Select != nil && schema.Select != nil {
+			return validateOption(*prop.Select, schema.Select.Options)
+		}
+	case DBPropTypeStatus:
+		if prop.Status != nil && schema.Status != nil {
+			return validateOption(*prop.Status, schema.Status.Options)
+		}
+	case DBPropTypeMultiSelect:
+		if schema.MultiSelect != nil {
+			for _, option := range prop.MultiSelect {
+				if err := validateOption(option, schema.MultiSelect.Options); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateOption(option SelectOptions, options []SelectOptions) error {
+	for _, o := range options {
+		if (option.ID != "" && option.ID == o.ID) || (option.ID == "" && option.Name == o.Name) {
+			return nil
+		}
+	}
+
+	if option.ID != "" {
+		return fmt.Errorf("%w: %q", ErrInvalidSelectOption, option.ID)
+	}
+
+	return fmt.Errorf("%w: %q", ErrInvalidSelectOption, option.Name)
+}
+
+// readOnly returns true for property types with values computed by Notion.
+func (t DatabasePropertyType) readOnly() bool {
+	switch t {
+	case DBPropTypeFormula,
+		DBPropTypeRollup,
+		DBPropTypeCreatedTime,
+		DBPropTypeCreatedBy,
+		DBPropTypeLastEditedTime,
+		DBPropTypeLastEditedBy:
+		return true
+	}
+	return false
+}
+
+// valueTypes returns the property types of the non-empty value fields.
+func (prop DatabasePageProperty) valueTypes() []DatabasePropertyType {
+	var types []DatabasePropertyType
+
+	add := func(set bool, t DatabasePropertyType) {
+		if set {
+			types = append(types, t)
+		}
+	}
+
+	add(prop.Title != nil, DBPropTypeTitle)
+	add(prop.RichText != nil, DBPropTypeRichText)
+	add(prop.Number != nil, DBPropTypeNumber)
+	add(prop.Select != nil, DBPropTypeSelect)
+	add(prop.MultiSelect != nil, DBPropTypeMultiSelect)
+	add(prop.Date != nil, DBPropTypeDate)
+	add(prop.Formula != nil, DBPropTypeFormula)
+	add(prop.Relation != nil, DBPropTypeRelation)
+	add(prop.Rollup != nil, DBPropTypeRollup)
+	add(prop.People != nil, DBPropTypePeople)
+	add(prop.Files != nil, DBPropTypeFiles)
+	add(prop.Checkbox != nil, DBPropTypeCheckbox)
+	add(prop.URL != nil, DBPropTypeURL)
+	add(prop.Email != nil, DBPropTypeEmail)
+	add(prop.PhoneNumber != nil, DBPropTypePhoneNumber)
+	add(prop.Status != nil, DBPropTypeStatus)
+	add(prop.CreatedTime != nil, DBPropTypeCreatedTime)
+	add(prop.CreatedBy != nil, DBPropTypeCreatedBy)
+	add(prop.LastEditedTime != nil, DBPropTypeLastEditedTime)
+	add(prop.LastEditedBy != nil, DBPropTypeLastEditedBy)
+
+	return types
+}
+
+// schemaCache caches database schemas, and the parent databases of pages, for
+// client-side validation of page properties.
+type schemaCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	databases map[string]cachedDatabase
+	parents   map[string]string
+}
+
+// maxCachedParents is the maximum number of page parents in a schema cache.
+// When reached, the cached page parents are cleared.
+const maxCachedParents = 10000
+
+type cachedDatabase struct {
+	db        Database
+	fetchedAt time.Time
+}
+
+func newSchemaCache(ttl time.Duration) *schemaCache {
+	return &schemaCache{
+		ttl:       ttl,
+		now:       time.Now,
+		databases: make(map[string]cachedDatabase),
+		parents:   make(map[string]string),
+	}
+}
+
+func (sc *schemaCache) database(id string) (Database, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	cached, ok := sc.databases[normalizeID(id)]
+	if !ok || (sc.ttl > 0 && sc.now().Sub(cached.fetchedAt) > sc.ttl) {
+		return Database{}, false
+	}
+
+	return cached.db, true
+}
+
+func (sc *schemaCache) storeDatabase(id string, db Database) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.databases[normalizeID(id)] = cachedDatabase{db: db, fetchedAt: sc.now()}
+}
+
+func (sc *schemaCache) invalidate(id string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	delete(sc.databases, normalizeID(id))
+}
+
+func (sc *schemaCache) parent(pageID string) (string, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	id, ok := sc.parents[normalizeID(pageID)]
+	return id, ok
+}
+
+func (sc *schemaCache) storeParent(pageID, databaseID string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if len(sc.parents) >= maxCachedParents {
+		sc.parents = make(map[string]string)
+	}
+	sc.parents[normalizeID(pageID)] = databaseID
+}
+
+// normalizeID removes dashes from an ID, as the API accepts IDs with and
+// without dashes.
+func normalizeID(id string) string {
+	return strings.ReplaceAll(id, "-", "")
+}
+
+// WithSchemaValidation makes the client validate page properties against the
+// schema of their database before creating or updating a page. Database schemas
+// are fetched when first needed, and cached for ttl (or indefinitely, if ttl is
+// zero). Updating a page requires finding its parent database first, which is
+// cached indefinitely for up to 10000 pages, after which the cached parents are
+// cleared. Validation errors are returned as MultiError.
+// See: Database.ValidatePageProperties.
+func WithSchemaValidation(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.schemaCache = newSchemaCache(ttl)
+	}
+}
+
+// InvalidateSchema removes a database schema from the cache used for schema
+// validation, so it's fetched again when needed. Schemas of databases updated
+// with UpdateDatabase are invalidated automatically.
+func (c *Client) InvalidateSchema(databaseID string) {
+	if c.schemaCache != nil {
+		c.schemaCache.invalidate(databaseID)
+	}
+}
+
+// cachedDatabase returns a database schema, from cache if possible.
+func (c *Client) cachedDatabase(ctx context.Context, id string) (Database, error) {
+	if db, ok := c.schemaCache.database(id); ok {
+		return db, nil
+	}
+
+	db, err := c.FindDatabaseByID(ctx, id)
+	if err != nil {
+		return Database{}, err
+	}
+	c.schemaCache.storeDatabase(id, db)
+
+	return db, nil
+}
+
+// validateCreatePage validates page properties of new database pages, if schema
+// validation is enabled.
+func (c *Client) validateCreatePage(ctx context.Context, params CreatePageParams) error {
+	if c.schemaCache == nil || params.DatabasePageProperties == nil {
+		return nil
+	}
+	if params.ParentType != ParentTypeDatabase {
+		// Pages that aren't in a database only have a title property.
+		return nil
+	}
+
+	db, err := c.cachedDatabase(ctx, params.ParentID)
+	if err != nil {
+		return fmt.Errorf("notion: failed to find database schema: %w", err)
+	}
+
+	if err := db.ValidatePageProperties(*params.DatabasePageProperties); err != nil {
+		return fmt.Errorf("notion: invalid page params: %w", err)
+	}
+
+	return nil
+}
+
+// validateUpdatePage validates updated page properties of database pages, if
+// schema validation is enabled.
+func (c *Client) validateUpdatePage(ctx context.Context, pageID string, params UpdatePageParams) error {
+	if c.schemaCache == nil || len(params.DatabasePageProperties) == 0 {
+		return nil
+	}
+
+	databaseID, ok := c.schemaCache.parent(pageID)
+	if !ok {
+		page, err := c.FindPageByID(ctx, pageID)
+		if err != nil {
+			return fmt.Errorf("notion: failed to find page parent: %w", err)
+		}
+		databaseID = page.Parent.DatabaseID
+		c.schemaCache.storeParent(pageID, databaseID)
+	}
+
+	if databaseID == "" {
+		// Pages that aren't in a database only have a title property.
+		return nil
+	}
+
+	db, err := c.cachedDatabase(ctx, databaseID)
+	if err != nil {
+		return fmt.Errorf("notion: failed to find database schema: %w", err)
+	}
+
+	if err := db.ValidatePageProperties(params.DatabasePageProperties); err != nil {
+		return fmt.Errorf("notion: invalid page params: %w", err)
+	}
+
+	return nil
+}
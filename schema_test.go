@@ -0,0 +1,239 @@
+package notion_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+)
+
+const schemaTestDatabaseJSON = `{
+	"object": "database",
+	"id": "39ddfc9d-33c9-404c-89cf-79f01c42dd0c",
+	"properties": {
+		"Name": {"id": "title", "type": "title", "title": {}},
+		"Tags": {"id": "tags", "type": "multi_select", "multi_select": {"options": [{"id": "1", "name": "foo"}, {"id": "2", "name": "bar"}]}},
+		"Priority": {"id": "prio", "type": "select", "select": {"options": [{"id": "3", "name": "High"}]}},
+		"Score": {"id": "score", "type": "number", "number": {"format": "number"}},
+		"Total": {"id": "total", "type": "formula", "formula": {"expression": "prop(\"Score\")"}}
+	}
+}`
+
+func TestValidatePageProperties(t *testing.T) {
+	t.Parallel()
+
+	var db notion.Database
+	if err := json.Unmarshal([]byte(schemaTestDatabaseJSON), &db); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		props      notion.DatabasePageProperties
+		expErrors  []string
+		expTargets []error
+	}{
+		{
+			name: "valid",
+			props: notion.DatabasePageProperties{
+				"Name":     {Title: []notion.RichText{{Text: &notion.Text{Content: "Foobar"}}}},
+				"tags":     {MultiSelect: []notion.SelectOptions{{Name: "foo"}, {ID: "2"}}},
+				"Priority": {Select: &notion.SelectOptions{Name: "High"}},
+				"Score":    {Type: notion.DBPropTypeNumber, Number: notion.Float64Ptr(42)},
+			},
+		},
+		{
+			name: "invalid",
+			props: notion.DatabasePageProperties{
+				"Unknown":  {Checkbox: notion.BoolPtr(true)},
+				"Tags":     {MultiSelect: []notion.SelectOptions{{Name: "baz"}}},
+				"Priority": {Select: &notion.SelectOptions{ID: "4"}},
+				"Score":    {RichText: []notion.RichText{}},
+				"Total":    {Formula: &notion.FormulaResult{}},
+			},
+			expErrors: []string{
+				`property "Priority": option doesn't exist: "4"`,
+				`property "Score": property value doesn't match property type: expected number, got rich_text`,
+				`property "Tags": option doesn't exist: "baz"`,
+				`property "Total": property is read-only (formula)`,
+				`property "Unknown": property doesn't exist in database`,
+			},
+			expTargets: []error{
+				notion.ErrUnknownProperty,
+				notion.ErrReadOnlyProperty,
+				notion.ErrPropertyTypeMismatch,
+				notion.ErrInvalidSelectOption,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := db.ValidatePageProperties(tt.props)
+			if len(tt.expErrors) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var multiErr notion.MultiError
+			if !errors.As(err, &multiErr) {
+				t.Fatalf("expected notion.MultiError, got: %#v", err)
+			}
+
+			var msgs []string
+			for _, err := range multiErr {
+				var propErr *notion.PropertyError
+				if !errors.As(err, &propErr) {
+					t.Fatalf("expected *notion.PropertyError, got: %#v", err)
+				}
+				msgs = append(msgs, propErr.Error())
+			}
+			if diff := cmp.Diff(tt.expErrors, msgs); diff != "" {
+				t.Fatalf("errors not equal (-exp, +got):\n%v", diff)
+			}
+
+			for _, target := range tt.expTargets {
+				if !errors.Is(err, target) {
+					t.Errorf("expected error to match %v", target)
+				}
+			}
+
+			var propErr *notion.PropertyError
+			if !multiErr.As(&propErr) {
+				t.Errorf("expected notion.MultiError to match *notion.PropertyError")
+			}
+		})
+	}
+}
+
+func TestSchemaValidation(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(requests *int32, pageParent string) *notion.Client {
+		httpClient := &http.Client{
+			Transport: &mockRoundtripper{fn: func(r *http.Request) (*http.Response, error) {
+				atomic.AddInt32(requests, 1)
+
+				body := `{"object": "page", "id": "cb261dc5-6c85-4767-8585-3852382fb466", "parent": ` + pageParent + `, "properties": {}}`
+				if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/databases/") {
+					body = schemaTestDatabaseJSON
+				}
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     http.StatusText(http.StatusOK),
+					Body:       ioutil.NopCloser(strings.NewReader(body)),
+				}, nil
+			}},
+		}
+
+		return notion.NewClient("secret-api-key", notion.WithHTTPClient(httpClient), notion.WithSchemaValidation(0))
+	}
+
+	databaseParent := `{"type": "database_id", "database_id": "39ddfc9d-33c9-404c-89cf-79f01c42dd0c"}`
+
+	t.Run("create page", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		client := newClient(&requests, databaseParent)
+
+		params := notion.CreatePageParams{
+			ParentType: notion.ParentTypeDatabase,
+			ParentID:   "39ddfc9d-33c9-404c-89cf-79f01c42dd0c",
+			DatabasePageProperties: &notion.DatabasePageProperties{
+				"Name": {Title: []notion.RichText{{Text: &notion.Text{Content: "Foobar"}}}},
+			},
+		}
+		if _, err := client.CreatePage(context.Background(), params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		(*params.DatabasePageProperties)["Total"] = notion.DatabasePageProperty{Formula: &notion.FormulaResult{}}
+		_, err := client.CreatePage(context.Background(), params)
+		if !errors.Is(err, notion.ErrReadOnlyProperty) {
+			t.Fatalf("expected notion.ErrReadOnlyProperty, got: %v", err)
+		}
+
+		// One request for the schema, and one for creating the page.
+		if requests != 2 {
+			t.Fatalf("requests not equal (expected: 2, got: %v)", requests)
+		}
+	})
+
+	t.Run("create page without database", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		client := newClient(&requests, `{"type": "page_id", "page_id": "b0668f48-8d66-4733-9bdb-2f82215707f7"}`)
+
+		params := notion.CreatePageParams{
+			ParentType: notion.ParentTypePage,
+			ParentID:   "b0668f48-8d66-4733-9bdb-2f82215707f7",
+			Title:      []notion.RichText{{Text: &notion.Text{Content: "Foobar"}}},
+			DatabasePageProperties: &notion.DatabasePageProperties{
+				"title": {Title: []notion.RichText{{Text: &notion.Text{Content: "Foobar"}}}},
+			},
+		}
+		if _, err := client.CreatePage(context.Background(), params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// No schema is requested for a page parent.
+		if requests != 1 {
+			t.Fatalf("requests not equal (expected: 1, got: %v)", requests)
+		}
+	})
+
+	t.Run("update database page", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		client := newClient(&requests, databaseParent)
+
+		params := notion.UpdatePageParams{
+			DatabasePageProperties: notion.DatabasePageProperties{
+				"Tags": {MultiSelect: []notion.SelectOptions{{Name: "baz"}}},
+			},
+		}
+		for i := 0; i < 2; i++ {
+			_, err := client.UpdatePage(context.Background(), "cb261dc5-6c85-4767-8585-3852382fb466", params)
+			if !errors.Is(err, notion.ErrInvalidSelectOption) {
+				t.Fatalf("expected notion.ErrInvalidSelectOption, got: %v", err)
+			}
+		}
+
+		// The page parent and the schema are requested once.
+		if requests != 2 {
+			t.Fatalf("requests not equal (expected: 2, got: %v)", requests)
+		}
+	})
+
+	t.Run("update page without database", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		client := newClient(&requests, `{"type": "page_id", "page_id": "b0668f48-8d66-4733-9bdb-2f82215707f7"}`)
+
+		params := notion.UpdatePageParams{
+			DatabasePageProperties: notion.DatabasePageProperties{
+				"title": {Title: []notion.RichText{{Text: &notion.Text{Content: "Foobar"}}}},
+			},
+		}
+		if _, err := client.UpdatePage(context.Background(), "cb261dc5-6c85-4767-8585-3852382fb466", params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -1,9 +1,6 @@
 package notion
 
-import (
-	"encoding/json"
-	"fmt"
-)
+import "encoding/json"
 
 type SearchOpts struct {
 	Query       string        `json:"query,omitempty"`
@@ -26,57 +23,206 @@ type SearchFilter struct {
 }
 
 type SearchResponse struct {
-	// Results are either pages or databases. See `SearchResponse.UnmarshalJSON`.
 	Results    SearchResults `json:"results"`
 	HasMore    bool          `json:"has_more"`
 	NextCursor *string       `json:"next_cursor"`
 }
 
-type SearchResults []interface{}
+// SearchResults are pages and databases returned by a search request.
+type SearchResults []SearchResult
+
+// SearchResult is either a page or a database, denoted by its object type.
+// Use the Page and Database methods to access the underlying value.
+type SearchResult struct {
+	object   SearchResultObject
+	page     *Page
+	database *Database
+
+	// Raw holds the original JSON of a result with an object type that is
+	// unknown to this library.
+	Raw json.RawMessage
+}
+
+// SearchResultObject is the object type of a search result.
+type SearchResultObject string
+
+const (
+	SearchResultObjectPage     SearchResultObject = "page"
+	SearchResultObjectDatabase SearchResultObject = "database"
+)
 
 const SearchSortTimestampLastEditedTime SearchSortTimestamp = "last_edited_time"
 
-func (sr *SearchResults) UnmarshalJSON(b []byte) error {
-	rawResults := []json.RawMessage{}
-	err := json.Unmarshal(b, &rawResults)
-	if err != nil {
-		return err
+// Search filter enums.
+const (
+	SearchFilterPropertyObject = "object"
+	SearchFilterValuePage      = "page"
+	SearchFilterValueDatabase  = "database"
+)
+
+// SearchFilterPages returns a search filter that only matches pages.
+func SearchFilterPages() *SearchFilter {
+	return &SearchFilter{
+		Property: SearchFilterPropertyObject,
+		Value:    SearchFilterValuePage,
+	}
+}
+
+// SearchFilterDatabases returns a search filter that only matches databases.
+func SearchFilterDatabases() *SearchFilter {
+	return &SearchFilter{
+		Property: SearchFilterPropertyObject,
+		Value:    SearchFilterValueDatabase,
+	}
+}
+
+// SearchSortByLastEditedTime returns a search sort on last edited time.
+func SearchSortByLastEditedTime(direction SortDirection) *SearchSort {
+	return &SearchSort{
+		Direction: direction,
+		Timestamp: SearchSortTimestampLastEditedTime,
+	}
+}
+
+// NewPageSearchResult returns a search result holding a page.
+func NewPageSearchResult(page Page) SearchResult {
+	return SearchResult{object: SearchResultObjectPage, page: &page}
+}
+
+// NewDatabaseSearchResult returns a search result holding a database.
+func NewDatabaseSearchResult(db Database) SearchResult {
+	return SearchResult{object: SearchResultObjectDatabase, database: &db}
+}
+
+// Object returns the object type of the result, e.g. `page` or `database`.
+func (r SearchResult) Object() SearchResultObject {
+	return r.object
+}
+
+// Page returns the page held by the result. The boolean is false if the
+// result is not a page.
+func (r SearchResult) Page() (Page, bool) {
+	if r.page == nil {
+		return Page{}, false
+	}
+	return *r.page, true
+}
+
+// Database returns the database held by the result. The boolean is false if
+// the result is not a database.
+func (r SearchResult) Database() (Database, bool) {
+	if r.database == nil {
+		return Database{}, false
+	}
+	return *r.database, true
+}
+
+// ID returns the ID of the page or database. For unknown objects, an empty
+// string is returned.
+func (r SearchResult) ID() string {
+	switch {
+	case r.page != nil:
+		return r.page.ID
+	case r.database != nil:
+		return r.database.ID
+	default:
+		return ""
+	}
+}
+
+// Title returns the plain text title of the page or database.
+func (r SearchResult) Title() string {
+	switch {
+	case r.page != nil:
+		return r.page.Title()
+	case r.database != nil:
+		return PlainText(r.database.Title)
+	default:
+		return ""
 	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r SearchResult) MarshalJSON() ([]byte, error) {
+	switch {
+	case r.page != nil:
+		return json.Marshal(r.page)
+	case r.database != nil:
+		return json.Marshal(r.database)
+	case r.Raw != nil:
+		return r.Raw, nil
+	default:
+		return []byte("null"), nil
+	}
+}
 
-	type Object struct {
-		Object string `json:"object"`
+// UnmarshalJSON implements json.Unmarshaler. Results with an unknown object
+// type only have their Raw field set.
+func (r *SearchResult) UnmarshalJSON(b []byte) error {
+	var obj struct {
+		Object SearchResultObject `json:"object"`
+	}
+
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
 	}
 
-	results := make(SearchResults, len(rawResults))
+	*r = SearchResult{object: obj.Object}
 
-	for i, rawResult := range rawResults {
-		obj := Object{}
-		err := json.Unmarshal(rawResult, &obj)
-		if err != nil {
+	switch obj.Object {
+	case SearchResultObjectDatabase:
+		var db Database
+		if err := json.Unmarshal(b, &db); err != nil {
 			return err
 		}
+		r.database = &db
+	case SearchResultObjectPage:
+		var page Page
+		if err := json.Unmarshal(b, &page); err != nil {
+			return err
+		}
+		r.page = &page
+	default:
+		r.Raw = append(json.RawMessage(nil), b...)
+	}
 
-		switch obj.Object {
-		case "database":
-			var db Database
-			err := json.Unmarshal(rawResult, &db)
-			if err != nil {
-				return err
-			}
-			results[i] = db
-		case "page":
-			var page Page
-			err := json.Unmarshal(rawResult, &page)
-			if err != nil {
-				return err
-			}
-			results[i] = page
-		default:
-			return fmt.Errorf("unsupported result object %q", obj.Object)
+	return nil
+}
+
+// Pages returns the results that are pages.
+func (sr SearchResults) Pages() []Page {
+	var pages []Page
+	for _, result := range sr {
+		if page, ok := result.Page(); ok {
+			pages = append(pages, page)
 		}
 	}
+	return pages
+}
 
-	*sr = results
+// Databases returns the results that are databases.
+func (sr SearchResults) Databases() []Database {
+	var dbs []Database
+	for _, result := range sr {
+		if db, ok := result.Database(); ok {
+			dbs = append(dbs, db)
+		}
+	}
+	return dbs
+}
 
-	return nil
+// SearchAllOpts are the options used for SearchAll.
+type SearchAllOpts struct {
+	Query    string
+	Sort     *SearchSort
+	Filter   *SearchFilter
+	PageSize int
+
+	// TitlePrefix, if set, only keeps results with a title that starts with
+	// it, ignoring case.
+	TitlePrefix string
+
+	// MaxResults, if non-zero, stops the search once this many results were
+	// collected.
+	MaxResults int
 }
@@ -3,6 +3,7 @@ package notion
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -19,13 +20,27 @@ type DateTime struct {
 	hasTime bool
 }
 
+// localDateTimeFormat is used for date values with a time zone, which Notion
+// expects as wall clock time without UTC offset.
+const localDateTimeFormat = "2006-01-02T15:04:05.999"
+
+// utcDateTimeFormat is used for date values with a time zone that can't be
+// loaded. Milliseconds are always included, so the value can be parsed again.
+const utcDateTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
 // ParseDateTime parses an RFC3339 formatted string with optional time.
 func ParseDateTime(value string) (DateTime, error) {
+	return ParseDateTimeInLocation(value, time.UTC)
+}
+
+// ParseDateTimeInLocation is like ParseDateTime, but a value without UTC
+// offset (e.g. `2021-05-23T09:11:50`) is interpreted as wall clock time in loc.
+func ParseDateTimeInLocation(value string, loc *time.Location) (DateTime, error) {
 	if len(value) > len(DateTimeFormat) {
 		return DateTime{}, errors.New("invalid datetime string")
 	}
 
-	t, err := time.Parse(DateTimeFormat[:len(value)], value)
+	t, err := time.ParseInLocation(DateTimeFormat[:len(value)], value, loc)
 	if err != nil {
 		return DateTime{}, err
 	}
@@ -93,3 +108,234 @@ func (dt DateTime) Equal(value DateTime) bool {
 	}
 	return dt.hasTime == value.hasTime
 }
+
+// In returns dt with its time converted to loc. A date without time keeps its
+// calendar date, at midnight in loc.
+func (dt DateTime) In(loc *time.Location) DateTime {
+	if dt.hasTime {
+		return DateTime{Time: dt.Time.In(loc), hasTime: true}
+	}
+
+	return DateTime{
+		Time: time.Date(dt.Year(), dt.Month(), dt.Day(), 0, 0, 0, 0, loc),
+	}
+}
+
+// NewDateInTimeZone returns a Date with time for a named IANA time zone (e.g.
+// `Europe/Amsterdam`), for use in page properties. Start and end are converted
+// to the time zone and encoded as wall clock time, as required by Notion.
+// The end time is optional.
+func NewDateInTimeZone(start time.Time, end *time.Time, timeZone string) (Date, error) {
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid time zone: %w", err)
+	}
+
+	date := Date{
+		Start:    NewDateTime(start.In(loc), true),
+		TimeZone: &timeZone,
+	}
+
+	if end != nil {
+		endDT := NewDateTime(end.In(loc), true)
+		date.End = &endDT
+	}
+
+	return date, nil
+}
+
+// Location returns the location of the date's TimeZone, loaded from the IANA
+// time zone database. If TimeZone is nil, the location of Start is returned.
+func (d Date) Location() (*time.Location, error) {
+	if d.TimeZone == nil {
+		return d.Start.Location(), nil
+	}
+
+	loc, err := time.LoadLocation(*d.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone: %w", err)
+	}
+
+	return loc, nil
+}
+
+// HasTime returns true if the start of the date includes time, i.e. the date
+// isn't an all-day date (range).
+func (d Date) HasTime() bool {
+	return d.Start.HasTime()
+}
+
+// In returns the date with start and end converted to loc. See DateTime.In.
+func (d Date) In(loc *time.Location) Date {
+	converted := Date{
+		Start:    d.Start.In(loc),
+		TimeZone: d.TimeZone,
+	}
+
+	if d.End != nil {
+		end := d.End.In(loc)
+		converted.End = &end
+	}
+
+	return converted
+}
+
+// Bounds returns the half-open interval [start, end) covered by the date. An
+// all-day date covers whole calendar days, from midnight in loc (or UTC, if loc
+// is nil) on the start date up to midnight after the end date. A date with time
+// and without end covers a single instant, so start and end are equal.
+func (d Date) Bounds(loc *time.Location) (start, end time.Time) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if !d.HasTime() {
+		start = d.Start.In(loc).Time
+		end = start
+		if d.End != nil {
+			end = d.End.In(loc).Time
+		}
+		return start, end.AddDate(0, 0, 1)
+	}
+
+	start = d.Start.Time
+	end = start
+	if d.End != nil {
+		end = d.End.Time
+		if !d.End.HasTime() {
+			end = d.End.In(loc).AddDate(0, 0, 1)
+		}
+	}
+
+	return start, end
+}
+
+// Contains returns true if t falls within the date's bounds. See Bounds.
+func (d Date) Contains(t time.Time, loc *time.Location) bool {
+	start, end := d.Bounds(loc)
+	if start.Equal(end) {
+		return t.Equal(start)
+	}
+	return !t.Before(start) && t.Before(end)
+}
+
+// Overlaps returns true if the bounds of both dates overlap, so an all-day date
+// overlaps with times on that day in loc. See Bounds.
+func (d Date) Overlaps(other Date, loc *time.Location) bool {
+	start, end := d.Bounds(loc)
+	otherStart, otherEnd := other.Bounds(loc)
+
+	if start.Equal(end) {
+		return other.Contains(start, loc)
+	}
+	if otherStart.Equal(otherEnd) {
+		return d.Contains(otherStart, loc)
+	}
+
+	return start.Before(otherEnd) && otherStart.Before(end)
+}
+
+// Compare compares the bounds of both dates, with all-day dates interpreted in
+// loc. It returns -1 if d starts before other (or ends before other, when both
+// start at the same instant), +1 if the reverse is true, and 0 if both cover the
+// same interval. See Bounds.
+func (d Date) Compare(other Date, loc *time.Location) int {
+	start, end := d.Bounds(loc)
+	otherStart, otherEnd := other.Bounds(loc)
+
+	switch {
+	case start.Before(otherStart):
+		return -1
+	case start.After(otherStart):
+		return 1
+	case end.Before(otherEnd):
+		return -1
+	case end.After(otherEnd):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler. If a time zone is set, start and
+// end values without UTC offset are interpreted as wall clock time in it.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var dto struct {
+		Start    string  `json:"start"`
+		End      *string `json:"end"`
+		TimeZone *string `json:"time_zone"`
+	}
+
+	if err := json.Unmarshal(b, &dto); err != nil {
+		return err
+	}
+
+	loc := time.UTC
+	if dto.TimeZone != nil {
+		if tzLoc, err := time.LoadLocation(*dto.TimeZone); err == nil {
+			loc = tzLoc
+		}
+	}
+
+	start, err := ParseDateTimeInLocation(dto.Start, loc)
+	if err != nil {
+		return err
+	}
+
+	date := Date{
+		Start:    start,
+		TimeZone: dto.TimeZone,
+	}
+
+	if dto.End != nil {
+		end, err := ParseDateTimeInLocation(*dto.End, loc)
+		if err != nil {
+			return err
+		}
+		date.End = &end
+	}
+
+	*d = date
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. If a time zone is set, start and end
+// times are encoded as wall clock time in it, without UTC offset. If the time
+// zone can't be loaded, times are encoded with UTC offset instead, so dates that
+// were decoded with an unknown time zone can be encoded again.
+func (d Date) MarshalJSON() ([]byte, error) {
+	type dateAlias Date
+
+	if d.TimeZone == nil {
+		return json.Marshal(dateAlias(d))
+	}
+
+	loc, err := d.Location()
+
+	format := func(dt DateTime) string {
+		if !dt.hasTime {
+			return dt.Format(DateTimeFormat[:dateLength])
+		}
+		if err != nil {
+			return dt.Time.UTC().Format(utcDateTimeFormat)
+		}
+		return dt.Time.In(loc).Format(localDateTimeFormat)
+	}
+
+	dto := struct {
+		Start    string  `json:"start"`
+		End      *string `json:"end,omitempty"`
+		TimeZone string  `json:"time_zone"`
+	}{
+		Start:    format(d.Start),
+		TimeZone: *d.TimeZone,
+	}
+
+	if d.End != nil {
+		end := format(*d.End)
+		dto.End = &end
+	}
+
+	return json.Marshal(dto)
+}
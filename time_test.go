@@ -113,3 +113,178 @@ func TestTimeUnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestDateTimeZone(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unmarshal wall clock time in time zone", func(t *testing.T) {
+		t.Parallel()
+
+		var date notion.Date
+		err := json.Unmarshal([]byte(`{"start":"2021-05-23T09:00:00","end":"2021-05-23T10:00:00","time_zone":"Europe/Amsterdam"}`), &date)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		exp := time.Date(2021, time.May, 23, 7, 0, 0, 0, time.UTC)
+		if !date.Start.Time.Equal(exp) {
+			t.Fatalf("start not equal (expected: %v, got: %v)", exp, date.Start.Time)
+		}
+		if !date.End.Time.Equal(exp.Add(time.Hour)) {
+			t.Fatalf("end not equal (expected: %v, got: %v)", exp.Add(time.Hour), date.End.Time)
+		}
+	})
+
+	t.Run("marshal with time zone", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.Date(2021, time.May, 23, 7, 0, 0, 0, time.UTC)
+		date, err := notion.NewDateInTimeZone(start, nil, "Europe/Amsterdam")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, err := json.Marshal(date)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		exp := `{"start":"2021-05-23T09:00:00","time_zone":"Europe/Amsterdam"}`
+		if diff := cmp.Diff(exp, string(got)); diff != "" {
+			t.Fatalf("encoded JSON not equal (-exp, +got):\n%v", diff)
+		}
+	})
+
+	t.Run("roundtrip with unknown time zone", func(t *testing.T) {
+		t.Parallel()
+
+		var date notion.Date
+		err := json.Unmarshal([]byte(`{"start":"2021-05-23T09:00:00","end":"2021-05-23T10:00:00","time_zone":"Foo/Bar"}`), &date)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, err := json.Marshal(date)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		exp := `{"start":"2021-05-23T09:00:00.000Z","end":"2021-05-23T10:00:00.000Z","time_zone":"Foo/Bar"}`
+		if diff := cmp.Diff(exp, string(got)); diff != "" {
+			t.Fatalf("encoded JSON not equal (-exp, +got):\n%v", diff)
+		}
+
+		var roundtrip notion.Date
+		if err := json.Unmarshal(got, &roundtrip); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !roundtrip.Start.Time.Equal(date.Start.Time) || !roundtrip.End.Time.Equal(date.End.Time) {
+			t.Fatalf("date not equal (expected: %v, got: %v)", date, roundtrip)
+		}
+	})
+
+	t.Run("invalid time zone", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := notion.NewDateInTimeZone(time.Now(), nil, "Foo/Bar")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+
+	t.Run("convert to location", func(t *testing.T) {
+		t.Parallel()
+
+		loc, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		date := notion.Date{Start: mustParseDateTime("2021-05-23")}
+		got := date.In(loc).Start
+		if got.Day() != 23 || got.Location() != loc || got.HasTime() {
+			t.Fatalf("unexpected date: %v", got)
+		}
+
+		date = notion.Date{Start: mustParseDateTime("2021-05-23T02:00:00.000Z")}
+		got = date.In(loc).Start
+		if got.Day() != 22 || got.Hour() != 22 {
+			t.Fatalf("unexpected date time: %v", got)
+		}
+	})
+}
+
+func TestDateOverlaps(t *testing.T) {
+	t.Parallel()
+
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	allDay := notion.Date{Start: mustParseDateTime("2021-05-23")}
+	end := mustParseDateTime("2021-05-25")
+	allDayRange := notion.Date{Start: mustParseDateTime("2021-05-23"), End: &end}
+
+	tests := []struct {
+		name  string
+		a, b  notion.Date
+		loc   *time.Location
+		exp   bool
+		order int
+	}{
+		{
+			name:  "time on all-day date",
+			a:     allDay,
+			b:     notion.Date{Start: mustParseDateTime("2021-05-23T23:30:00.000Z")},
+			exp:   true,
+			order: -1,
+		},
+		{
+			name:  "time after all-day date in other zone",
+			a:     allDay,
+			b:     notion.Date{Start: mustParseDateTime("2021-05-23T23:30:00.000Z")},
+			loc:   amsterdam,
+			exp:   false,
+			order: -1,
+		},
+		{
+			name:  "time within all-day range",
+			a:     allDayRange,
+			b:     notion.Date{Start: mustParseDateTime("2021-05-25T12:00:00.000Z")},
+			exp:   true,
+			order: -1,
+		},
+		{
+			name:  "adjacent all-day dates",
+			a:     notion.Date{Start: mustParseDateTime("2021-05-24")},
+			b:     allDay,
+			exp:   false,
+			order: 1,
+		},
+		{
+			name:  "equal dates",
+			a:     allDay,
+			b:     allDay,
+			exp:   true,
+			order: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.a.Overlaps(tt.b, tt.loc); got != tt.exp {
+				t.Fatalf("overlaps not equal (expected: %v, got: %v)", tt.exp, got)
+			}
+			if got := tt.b.Overlaps(tt.a, tt.loc); got != tt.exp {
+				t.Fatalf("reverse overlaps not equal (expected: %v, got: %v)", tt.exp, got)
+			}
+			if got := tt.a.Compare(tt.b, tt.loc); got != tt.order {
+				t.Fatalf("compare not equal (expected: %v, got: %v)", tt.order, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,419 @@
+package notion
+
+import "errors"
+
+// SkipChildren is used as a return value from BlockVisitor.Enter to indicate
+// that the children of the visited block should not be walked. It is not
+// returned as an error by any function.
+var SkipChildren = errors.New("skip children")
+
+// ErrChildrenUnsupported is used when setting children on a block type that
+// cannot have child blocks.
+var ErrChildrenUnsupported = errors.New("block type does not support children")
+
+// WalkInfo describes the position of a block visited by Walk or Inspect.
+type WalkInfo struct {
+	// Depth is zero for the blocks passed to Walk, one for their children, etc.
+	Depth int
+	// Path holds the index of the block and of each of its ancestors, starting
+	// at the root, e.g. `[]int{3, 0}` is the first child of the fourth block.
+	Path []int
+	// Parent is the block containing the visited block, or nil at depth zero.
+	Parent Block
+}
+
+// BlockVisitor is used by Walk. Enter is called for a block before its
+// children are walked (pre-order), Leave after they have been walked
+// (post-order).
+//
+// If Enter returns SkipChildren, the children of the block are skipped, but
+// Leave is still called. As the children have already been walked when Leave
+// is called, SkipChildren returned by Leave is ignored and the walk continues.
+// Any other non-nil error stops the walk and is returned by Walk.
+type BlockVisitor interface {
+	Enter(b Block, info WalkInfo) error
+	Leave(b Block, info WalkInfo) error
+}
+
+// BlockVisitorFuncs implements BlockVisitor with optional funcs. A nil func is
+// a no-op.
+type BlockVisitorFuncs struct {
+	EnterFunc func(b Block, info WalkInfo) error
+	LeaveFunc func(b Block, info WalkInfo) error
+}
+
+// Enter implements BlockVisitor.
+func (v BlockVisitorFuncs) Enter(b Block, info WalkInfo) error {
+	if v.EnterFunc == nil {
+		return nil
+	}
+	return v.EnterFunc(b, info)
+}
+
+// Leave implements BlockVisitor.
+func (v BlockVisitorFuncs) Leave(b Block, info WalkInfo) error {
+	if v.LeaveFunc == nil {
+		return nil
+	}
+	return v.LeaveFunc(b, info)
+}
+
+// Walk traverses blocks and their (nested) children depth-first, in order,
+// calling the visitor for each block. Children are read from the blocks'
+// `Children` fields, so blocks fetched from the API must have their children
+// populated (e.g. via `Client.FindBlockChildrenByID`) beforehand.
+func Walk(blocks []Block, v BlockVisitor) error {
+	return walk(blocks, v, nil, nil)
+}
+
+func walk(blocks []Block, v BlockVisitor, parent Block, path []int) error {
+	for i, b := range blocks {
+		if b == nil {
+			continue
+		}
+
+		info := WalkInfo{
+			Depth:  len(path),
+			Path:   append(append(make([]int, 0, len(path)+1), path...), i),
+			Parent: parent,
+		}
+
+		err := v.Enter(b, info)
+		if err != nil && err != SkipChildren {
+			return err
+		}
+
+		if err == nil {
+			if err := walk(BlockChildren(b), v, b, info.Path); err != nil {
+				return err
+			}
+		}
+
+		if err := v.Leave(b, info); err != nil && err != SkipChildren {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Inspect traverses blocks in pre-order, like Walk. If fn returns false, the
+// children of the block are skipped.
+func Inspect(blocks []Block, fn func(b Block, info WalkInfo) bool) {
+	_ = Walk(blocks, BlockVisitorFuncs{
+		EnterFunc: func(b Block, info WalkInfo) error {
+			if !fn(b, info) {
+				return SkipChildren
+			}
+			return nil
+		},
+	})
+}
+
+// BlockChildren returns the child blocks of b, or nil if its type cannot have
+// children. Both block values and pointers to blocks are supported.
+func BlockChildren(b Block) []Block {
+	if p, ok := blockValue(b).(interface{ GetChildren() []Block }); ok {
+		return p.GetChildren()
+	}
+	return nil
+}
+
+// SetBlockChildren replaces the child blocks of b. When b is a pointer, the
+// block it points to is updated and b is returned; otherwise an updated copy is
+// returned. For a `ColumnListBlock`, each child must be a `ColumnBlock`.
+// ErrChildrenUnsupported is returned for block types without children.
+func SetBlockChildren(b Block, children []Block) (Block, error) {
+	if p, ok := b.(Parenter); ok {
+		if err := p.SetChildren(children); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+
+	p, ok := blockPointer(b).(Parenter)
+	if !ok {
+		return nil, ErrChildrenUnsupported
+	}
+	if err := p.SetChildren(children); err != nil {
+		return nil, err
+	}
+
+	return blockValue(p), nil
+}
+
+// BlockRichText returns all rich text held by b: its body text, the cells of a
+// table row (row by row) and its caption, in that order. Nil is returned for
+// blocks without rich text.
+func BlockRichText(b Block) []RichText {
+	var body, caption []RichText
+
+	b = blockValue(b)
+
+	if rt, ok := b.(interface{ GetRichText() []RichText }); ok {
+		body = rt.GetRichText()
+	}
+	if c, ok := b.(interface{ GetCaption() []RichText }); ok {
+		caption = c.GetCaption()
+	}
+	if row, ok := b.(TableRowBlock); ok {
+		for _, cell := range row.Cells {
+			body = append(body, cell...)
+		}
+	}
+
+	if len(caption) == 0 {
+		return body
+	}
+
+	return append(append(make([]RichText, 0, len(body)+len(caption)), body...), caption...)
+}
+
+// blockPointer returns a pointer to a copy of the block struct held by b, so
+// its pointer receiver methods can be used. Pointers are returned as is.
+func blockPointer(b Block) Block {
+	switch v := b.(type) {
+	case ParagraphBlock:
+		return &v
+	case Heading1Block:
+		return &v
+	case Heading2Block:
+		return &v
+	case Heading3Block:
+		return &v
+	case BulletedListItemBlock:
+		return &v
+	case NumberedListItemBlock:
+		return &v
+	case ToDoBlock:
+		return &v
+	case ToggleBlock:
+		return &v
+	case ChildPageBlock:
+		return &v
+	case ChildDatabaseBlock:
+		return &v
+	case CalloutBlock:
+		return &v
+	case QuoteBlock:
+		return &v
+	case CodeBlock:
+		return &v
+	case EmbedBlock:
+		return &v
+	case ImageBlock:
+		return &v
+	case AudioBlock:
+		return &v
+	case VideoBlock:
+		return &v
+	case FileBlock:
+		return &v
+	case PDFBlock:
+		return &v
+	case BookmarkBlock:
+		return &v
+	case EquationBlock:
+		return &v
+	case DividerBlock:
+		return &v
+	case TableOfContentsBlock:
+		return &v
+	case BreadcrumbBlock:
+		return &v
+	case ColumnListBlock:
+		return &v
+	case ColumnBlock:
+		return &v
+	case TableBlock:
+		return &v
+	case TableRowBlock:
+		return &v
+	case LinkPreviewBlock:
+		return &v
+	case LinkToPageBlock:
+		return &v
+	case SyncedBlock:
+		return &v
+	case TemplateBlock:
+		return &v
+	case UnsupportedBlock:
+		return &v
+	default:
+		return b
+	}
+}
+
+// blockValue returns the block struct held by b, dereferencing pointers (as
+// returned when decoding API responses). A nil pointer yields nil.
+func blockValue(b Block) Block {
+	switch v := b.(type) {
+	case *ParagraphBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *Heading1Block:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *Heading2Block:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *Heading3Block:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *BulletedListItemBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *NumberedListItemBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *ToDoBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *ToggleBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *ChildPageBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *ChildDatabaseBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *CalloutBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *QuoteBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *CodeBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *EmbedBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *ImageBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *AudioBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *VideoBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *FileBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *PDFBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *BookmarkBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *EquationBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *DividerBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *TableOfContentsBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *BreadcrumbBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *ColumnListBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *ColumnBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *TableBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *TableRowBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *LinkPreviewBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *LinkToPageBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *SyncedBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *TemplateBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *UnsupportedBlock:
+		if v == nil {
+			return nil
+		}
+		return *v
+	default:
+		return b
+	}
+}
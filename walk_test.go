@@ -0,0 +1,305 @@
+package notion_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+)
+
+func richText(content string) []notion.RichText {
+	return []notion.RichText{
+		{
+			Type: notion.RichTextTypeText,
+			Text: &notion.Text{
+				Content: content,
+			},
+		},
+	}
+}
+
+func testBlockTree() []notion.Block {
+	return []notion.Block{
+		notion.ParagraphBlock{
+			RichText: richText("a"),
+			Children: []notion.Block{
+				&notion.ToDoBlock{
+					RichText: richText("a.0"),
+				},
+				notion.ToggleBlock{
+					RichText: richText("a.1"),
+					Children: []notion.Block{
+						notion.ParagraphBlock{RichText: richText("a.1.0")},
+					},
+				},
+			},
+		},
+		notion.ColumnListBlock{
+			Children: []notion.ColumnBlock{
+				{
+					Children: []notion.Block{
+						notion.ToDoBlock{RichText: richText("b.0.0")},
+					},
+				},
+			},
+		},
+		notion.DividerBlock{},
+	}
+}
+
+func blockLabel(b notion.Block) string {
+	rt := notion.BlockRichText(b)
+	if len(rt) == 0 {
+		return fmt.Sprintf("%T", b)
+	}
+	return rt[0].Text.Content
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	t.Run("visits blocks in pre and post-order", func(t *testing.T) {
+		t.Parallel()
+
+		var got []string
+
+		err := notion.Walk(testBlockTree(), notion.BlockVisitorFuncs{
+			EnterFunc: func(b notion.Block, info notion.WalkInfo) error {
+				got = append(got, fmt.Sprintf("enter %v %v %v", blockLabel(b), info.Depth, info.Path))
+				return nil
+			},
+			LeaveFunc: func(b notion.Block, info notion.WalkInfo) error {
+				got = append(got, "leave "+blockLabel(b))
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		exp := []string{
+			"enter a 0 [0]",
+			"enter a.0 1 [0 0]",
+			"leave a.0",
+			"enter a.1 1 [0 1]",
+			"enter a.1.0 2 [0 1 0]",
+			"leave a.1.0",
+			"leave a.1",
+			"leave a",
+			"enter notion.ColumnListBlock 0 [1]",
+			"enter notion.ColumnBlock 1 [1 0]",
+			"enter b.0.0 2 [1 0 0]",
+			"leave b.0.0",
+			"leave notion.ColumnBlock",
+			"leave notion.ColumnListBlock",
+			"enter notion.DividerBlock 0 [2]",
+			"leave notion.DividerBlock",
+		}
+
+		if diff := cmp.Diff(exp, got); diff != "" {
+			t.Fatalf("visited blocks not equal (-exp, +got):\n%v", diff)
+		}
+	})
+
+	t.Run("skips children", func(t *testing.T) {
+		t.Parallel()
+
+		var got []string
+
+		err := notion.Walk(testBlockTree(), notion.BlockVisitorFuncs{
+			EnterFunc: func(b notion.Block, info notion.WalkInfo) error {
+				got = append(got, blockLabel(b))
+				if _, ok := b.(notion.ParagraphBlock); ok {
+					return notion.SkipChildren
+				}
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		exp := []string{"a", "notion.ColumnListBlock", "notion.ColumnBlock", "b.0.0", "notion.DividerBlock"}
+
+		if diff := cmp.Diff(exp, got); diff != "" {
+			t.Fatalf("visited blocks not equal (-exp, +got):\n%v", diff)
+		}
+	})
+
+	t.Run("ignores skip children on leave", func(t *testing.T) {
+		t.Parallel()
+
+		var got []string
+
+		err := notion.Walk(testBlockTree(), notion.BlockVisitorFuncs{
+			EnterFunc: func(b notion.Block, info notion.WalkInfo) error {
+				got = append(got, blockLabel(b))
+				return nil
+			},
+			LeaveFunc: func(b notion.Block, info notion.WalkInfo) error {
+				return notion.SkipChildren
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		exp := []string{"a", "a.0", "a.1", "a.1.0", "notion.ColumnListBlock", "notion.ColumnBlock", "b.0.0", "notion.DividerBlock"}
+
+		if diff := cmp.Diff(exp, got); diff != "" {
+			t.Fatalf("visited blocks not equal (-exp, +got):\n%v", diff)
+		}
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		t.Parallel()
+
+		errStop := errors.New("stop")
+		count := 0
+
+		err := notion.Walk(testBlockTree(), notion.BlockVisitorFuncs{
+			EnterFunc: func(b notion.Block, info notion.WalkInfo) error {
+				count++
+				if info.Depth == 2 {
+					return errStop
+				}
+				return nil
+			},
+		})
+		if err != errStop {
+			t.Fatalf("error not equal (expected: %v, got: %v)", errStop, err)
+		}
+		if count != 4 {
+			t.Fatalf("expected 4 visited blocks, got %v", count)
+		}
+	})
+
+	t.Run("provides parent", func(t *testing.T) {
+		t.Parallel()
+
+		var parents []string
+
+		notion.Inspect(testBlockTree(), func(b notion.Block, info notion.WalkInfo) bool {
+			if _, ok := b.(*notion.ToDoBlock); ok {
+				parents = append(parents, blockLabel(info.Parent))
+			}
+			return true
+		})
+
+		if diff := cmp.Diff([]string{"a"}, parents); diff != "" {
+			t.Fatalf("parents not equal (-exp, +got):\n%v", diff)
+		}
+	})
+}
+
+func TestSetBlockChildren(t *testing.T) {
+	t.Parallel()
+
+	children := []notion.Block{notion.ParagraphBlock{RichText: richText("child")}}
+
+	t.Run("value", func(t *testing.T) {
+		t.Parallel()
+
+		orig := notion.ToggleBlock{RichText: richText("toggle")}
+
+		updated, err := notion.SetBlockChildren(orig, children)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(orig.Children) != 0 {
+			t.Fatal("expected original value to be unchanged")
+		}
+		if len(notion.BlockChildren(updated)) != 1 {
+			t.Fatalf("expected 1 child, got %v", len(notion.BlockChildren(updated)))
+		}
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		t.Parallel()
+
+		orig := &notion.ToggleBlock{RichText: richText("toggle")}
+
+		updated, err := notion.SetBlockChildren(orig, children)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if updated != notion.Block(orig) {
+			t.Fatal("expected pointer to be returned")
+		}
+		if len(orig.Children) != 1 {
+			t.Fatalf("expected 1 child, got %v", len(orig.Children))
+		}
+	})
+
+	t.Run("column list requires columns", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := notion.SetBlockChildren(notion.ColumnListBlock{}, children)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := notion.SetBlockChildren(notion.DividerBlock{}, children)
+		if !errors.Is(err, notion.ErrChildrenUnsupported) {
+			t.Fatalf("error not equal (expected: %v, got: %v)", notion.ErrChildrenUnsupported, err)
+		}
+	})
+}
+
+func TestBlockRichText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		block notion.Block
+		exp   []string
+	}{
+		{
+			name:  "paragraph",
+			block: notion.ParagraphBlock{RichText: richText("foo")},
+			exp:   []string{"foo"},
+		},
+		{
+			name:  "code with caption",
+			block: &notion.CodeBlock{RichText: richText("foo"), Caption: richText("bar")},
+			exp:   []string{"foo", "bar"},
+		},
+		{
+			name:  "table row",
+			block: notion.TableRowBlock{Cells: [][]notion.RichText{richText("foo"), richText("bar")}},
+			exp:   []string{"foo", "bar"},
+		},
+		{
+			name:  "image",
+			block: notion.ImageBlock{Caption: richText("foo")},
+			exp:   []string{"foo"},
+		},
+		{
+			name:  "divider",
+			block: notion.DividerBlock{},
+			exp:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, rt := range notion.BlockRichText(tt.block) {
+				got = append(got, rt.Text.Content)
+			}
+
+			if diff := cmp.Diff(tt.exp, got); diff != "" {
+				t.Fatalf("rich text not equal (-exp, +got):\n%v", diff)
+			}
+		})
+	}
+}
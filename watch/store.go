@@ -0,0 +1,145 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+// Checkpoint is the state of a watched database or page tree, as of its last
+// poll.
+type Checkpoint struct {
+	// Cursor is the last edited time of the most recently edited page seen.
+	Cursor time.Time `json:"cursor"`
+
+	// Pages are snapshots of the pages seen, by ID. Archived pages are
+	// removed.
+	Pages map[string]notion.Page `json:"pages"`
+}
+
+// clone returns a copy of the checkpoint that doesn't share its Pages map, as
+// a watcher updates the map of a loaded checkpoint while polling.
+func (cp Checkpoint) clone() Checkpoint {
+	pages := make(map[string]notion.Page, len(cp.Pages))
+	for id, page := range cp.Pages {
+		pages[id] = page
+	}
+	cp.Pages = pages
+
+	return cp
+}
+
+// Store persists checkpoints, so a restarted watcher continues where it left
+// off instead of seeing all pages as created.
+type Store interface {
+	// Load returns the checkpoint of a source, or nil if there is none. The
+	// returned checkpoint is modified by the watcher, so it must not share
+	// state (e.g. the Pages map) with the stored checkpoint.
+	Load(ctx context.Context, source string) (*Checkpoint, error)
+	// Save stores the checkpoint of a source.
+	Save(ctx context.Context, source string, cp Checkpoint) error
+}
+
+// MemoryStore is a Store that keeps checkpoints in memory. It's the default
+// store of a Watcher. Checkpoints are copied on Load and Save.
+type MemoryStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryStore returns a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{checkpoints: make(map[string]Checkpoint)}
+}
+
+// Load implements Store.
+func (s *MemoryStore) Load(_ context.Context, source string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cp, ok := s.checkpoints[source]
+	if !ok {
+		return nil, nil
+	}
+	cp = cp.clone()
+
+	return &cp, nil
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(_ context.Context, source string, cp Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[source] = cp.clone()
+
+	return nil
+}
+
+// FileStore is a Store that writes checkpoints as JSON files to a directory,
+// one per source.
+type FileStore struct {
+	Dir string
+}
+
+// Load implements Store.
+func (s FileStore) Load(_ context.Context, source string) (*Checkpoint, error) {
+	b, err := os.ReadFile(s.path(source))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("watch: failed to read checkpoint: %w", err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return nil, fmt.Errorf("watch: failed to parse checkpoint: %w", err)
+	}
+
+	return &cp, nil
+}
+
+// Save implements Store. The file is replaced atomically, so a crash can't
+// leave a partial checkpoint.
+func (s FileStore) Save(_ context.Context, source string, cp Checkpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("watch: failed to encode checkpoint: %w", err)
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("watch: failed to create checkpoint directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.Dir, ".checkpoint-*")
+	if err != nil {
+		return fmt.Errorf("watch: failed to write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("watch: failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("watch: failed to write checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(source)); err != nil {
+		return fmt.Errorf("watch: failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (s FileStore) path(source string) string {
+	return filepath.Join(s.Dir, source+".json")
+}
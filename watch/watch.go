@@ -0,0 +1,427 @@
+// Package watch polls Notion databases and page trees for changes, for
+// workspaces where webhooks are unavailable. A Watcher compares the pages of
+// each poll with snapshots of the previous poll, and delivers created, updated
+// and archived events over a channel.
+//
+//	w := watch.New(watch.Options{Client: client, Store: watch.FileStore{Dir: "checkpoints"}})
+//	w.WatchDatabase(dbID)
+//
+//	go func() {
+//		for e := range w.Events() {
+//			// ...
+//		}
+//	}()
+//
+//	err := w.Run(ctx)
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+const (
+	defaultInterval         = time.Minute
+	defaultFullScanInterval = 10
+)
+
+// Client is the subset of the Notion API used by a Watcher.
+type Client interface {
+	QueryDatabase(ctx context.Context, id string, query *notion.DatabaseQuery) (notion.DatabaseQueryResponse, error)
+	FindPageByID(ctx context.Context, id string) (notion.Page, error)
+	FindBlockChildrenByID(ctx context.Context, blockID string, query *notion.PaginationQuery) (notion.BlockChildrenResponse, error)
+}
+
+// EventType is the type of a change event.
+type EventType string
+
+const (
+	EventCreated  EventType = "created"
+	EventUpdated  EventType = "updated"
+	EventArchived EventType = "archived"
+)
+
+// Event is a change of a page of a watched database or page tree.
+type Event struct {
+	Type EventType
+	// Source is the ID of the watched database or root page.
+	Source string
+	// Page is the page as of the poll that saw the change. For pages that were
+	// deleted, it's the last snapshot.
+	Page notion.Page
+	// Previous is the snapshot of the page of the previous poll, for updated
+	// and archived events.
+	Previous *notion.Page
+	// Diff holds the changed properties of updated pages. It's empty if only
+	// the content of a page changed.
+	Diff notion.PageDiff
+}
+
+// Options configure a Watcher.
+type Options struct {
+	// Client is used to query databases and fetch pages. Required.
+	Client Client
+
+	// Interval is the time between polls. Defaults to a minute.
+	Interval time.Duration
+
+	// FullScanInterval is the number of polls of a database after which all its
+	// pages are queried, instead of only recently edited ones, to find archived
+	// pages, as queries don't return them. Defaults to 10. Page trees are
+	// always scanned in full.
+	FullScanInterval int
+
+	// Store persists checkpoints. Defaults to a MemoryStore.
+	Store Store
+
+	// Buffer is the size of the events channel. Polls block while the channel
+	// is full, so a slow consumer slows down polling instead of events piling
+	// up in memory.
+	Buffer int
+
+	// EmitInitial makes the first poll of a source without checkpoint emit
+	// created events for all its pages. By default, the first poll only takes
+	// snapshots.
+	EmitInitial bool
+
+	// OnError is called with errors of polls by Run, e.g. for logging.
+	OnError func(err error)
+}
+
+// Watcher polls databases and page trees for changes.
+type Watcher struct {
+	opts   Options
+	events chan Event
+
+	pollMu sync.Mutex
+
+	mu      sync.Mutex
+	sources []*source
+}
+
+type sourceKind int
+
+const (
+	sourceDatabase sourceKind = iota
+	sourcePageTree
+)
+
+type source struct {
+	id    string
+	kind  sourceKind
+	polls int
+}
+
+// New returns a new Watcher.
+func New(opts Options) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultInterval
+	}
+	if opts.FullScanInterval <= 0 {
+		opts.FullScanInterval = defaultFullScanInterval
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+
+	return &Watcher{
+		opts:   opts,
+		events: make(chan Event, opts.Buffer),
+	}
+}
+
+// WatchDatabase adds a database to watch.
+func (w *Watcher) WatchDatabase(id string) {
+	w.add(&source{id: id, kind: sourceDatabase})
+}
+
+// WatchPage adds a page to watch, including its descendant pages. Each poll
+// lists all blocks of the tree, and fetches the pages that were edited.
+func (w *Watcher) WatchPage(id string) {
+	w.add(&source{id: id, kind: sourcePageTree})
+}
+
+func (w *Watcher) add(src *source) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.sources = append(w.sources, src)
+}
+
+// Events returns the channel of events. It's closed when Run returns.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Run polls the watched sources until the context is done, and then closes the
+// events channel. Errors of polls are passed to Options.OnError; the failed
+// sources are polled again at the next interval.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.events)
+
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if w.opts.OnError != nil {
+				w.opts.OnError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll polls all watched sources once. The events of a source are sent before
+// its checkpoint is saved, so events of an interrupted poll are delivered
+// again by the next one.
+func (w *Watcher) Poll(ctx context.Context) error {
+	w.pollMu.Lock()
+	defer w.pollMu.Unlock()
+
+	w.mu.Lock()
+	sources := append([]*source(nil), w.sources...)
+	w.mu.Unlock()
+
+	var errs notion.MultiError
+	for _, src := range sources {
+		if err := w.poll(ctx, src); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			errs = append(errs, fmt.Errorf("watch: failed to poll %v: %w", src.id, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (w *Watcher) poll(ctx context.Context, src *source) error {
+	cp, err := w.opts.Store.Load(ctx, src.id)
+	if err != nil {
+		return err
+	}
+
+	initial := cp == nil
+	if initial {
+		cp = &Checkpoint{}
+	}
+	if cp.Pages == nil {
+		cp.Pages = make(map[string]notion.Page)
+	}
+
+	full := initial || src.kind == sourcePageTree || src.polls%w.opts.FullScanInterval == 0
+
+	var pages []notion.Page
+	switch src.kind {
+	case sourceDatabase:
+		pages, err = w.queryDatabase(ctx, src.id, cp, full)
+	case sourcePageTree:
+		pages, err = w.pageTree(ctx, src.id, cp)
+	}
+	if err != nil {
+		return err
+	}
+
+	events, err := w.diff(ctx, src.id, cp, pages, full)
+	if err != nil {
+		return err
+	}
+
+	if !initial || w.opts.EmitInitial {
+		for _, e := range events {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case w.events <- e:
+			}
+		}
+	}
+
+	if err := w.opts.Store.Save(ctx, src.id, *cp); err != nil {
+		return err
+	}
+	src.polls++
+
+	return nil
+}
+
+// queryDatabase returns the pages of a database that were edited since the
+// checkpoint cursor, or all pages for a full scan, sorted by last edited time.
+func (w *Watcher) queryDatabase(ctx context.Context, id string, cp *Checkpoint, full bool) ([]notion.Page, error) {
+	query := &notion.DatabaseQuery{
+		Sorts: []notion.DatabaseQuerySort{{
+			Timestamp: notion.TimestampLastEditedTime,
+			Direction: notion.SortDirAsc,
+		}},
+	}
+	if !full && !cp.Cursor.IsZero() {
+		// Last edited times are rounded to the minute, so pages of the cursor
+		// minute are queried again, and skipped if unchanged.
+		cursor := cp.Cursor
+		query.Filter = &notion.DatabaseQueryFilter{
+			Timestamp: notion.TimestampLastEditedTime,
+			DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{
+				LastEditedTime: &notion.DatePropertyFilter{OnOrAfter: &cursor},
+			},
+		}
+	}
+
+	var pages []notion.Page
+	for {
+		resp, err := w.opts.Client.QueryDatabase(ctx, id, query)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, resp.Results...)
+
+		if !resp.HasMore || resp.NextCursor == nil {
+			return pages, nil
+		}
+		query.StartCursor = *resp.NextCursor
+	}
+}
+
+// pageTree returns the root page and its descendant pages. Pages of which the
+// block wasn't edited since the snapshot aren't fetched again.
+func (w *Watcher) pageTree(ctx context.Context, rootID string, cp *Checkpoint) ([]notion.Page, error) {
+	root, err := w.opts.Client.FindPageByID(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := []notion.Page{root}
+	if root.Archived {
+		return pages, nil
+	}
+
+	var walk func(blockID string) error
+	walk = func(blockID string) error {
+		query := &notion.PaginationQuery{}
+		for {
+			resp, err := w.opts.Client.FindBlockChildrenByID(ctx, blockID, query)
+			if err != nil {
+				return err
+			}
+
+			for _, block := range resp.Results {
+				if _, ok := block.(*notion.ChildPageBlock); ok {
+					prev, seen := cp.Pages[block.ID()]
+					if seen && !block.LastEditedTime().After(prev.LastEditedTime) {
+						pages = append(pages, prev)
+					} else {
+						page, err := w.opts.Client.FindPageByID(ctx, block.ID())
+						if err != nil {
+							return err
+						}
+						pages = append(pages, page)
+					}
+				}
+
+				if block.HasChildren() {
+					if err := walk(block.ID()); err != nil {
+						return err
+					}
+				}
+			}
+
+			if !resp.HasMore || resp.NextCursor == nil {
+				return nil
+			}
+			query.StartCursor = *resp.NextCursor
+		}
+	}
+
+	if err := walk(rootID); err != nil {
+		return nil, err
+	}
+
+	return pages, nil
+}
+
+// diff returns the events of a poll, and updates the checkpoint. For full
+// scans, pages of the checkpoint that weren't seen are fetched, to find out if
+// they were archived.
+func (w *Watcher) diff(ctx context.Context, sourceID string, cp *Checkpoint, pages []notion.Page, full bool) ([]Event, error) {
+	var events []Event
+
+	seen := make(map[string]bool, len(pages))
+	for _, page := range pages {
+		seen[page.ID] = true
+
+		if page.LastEditedTime.After(cp.Cursor) {
+			cp.Cursor = page.LastEditedTime
+		}
+
+		prev, ok := cp.Pages[page.ID]
+
+		switch {
+		case page.Archived:
+			if ok {
+				delete(cp.Pages, page.ID)
+				events = append(events, Event{Type: EventArchived, Source: sourceID, Page: page, Previous: &prev})
+			}
+			continue
+		case !ok:
+			events = append(events, Event{Type: EventCreated, Source: sourceID, Page: page})
+		default:
+			diff := notion.DiffPages(prev, page)
+			if !diff.Empty() || page.LastEditedTime.After(prev.LastEditedTime) {
+				events = append(events, Event{Type: EventUpdated, Source: sourceID, Page: page, Previous: &prev, Diff: diff})
+			}
+		}
+
+		cp.Pages[page.ID] = page
+	}
+
+	if !full {
+		return events, nil
+	}
+
+	var missing []string
+	for id := range cp.Pages {
+		if !seen[id] {
+			missing = append(missing, id)
+		}
+	}
+	sort.Strings(missing)
+
+	for _, id := range missing {
+		prev := cp.Pages[id]
+		delete(cp.Pages, id)
+
+		page, err := w.opts.Client.FindPageByID(ctx, id)
+		switch {
+		case errors.Is(err, notion.ErrObjectNotFound):
+			page = prev
+			page.Archived = true
+		case err != nil:
+			return nil, err
+		case !page.Archived:
+			// The page was moved out of the source.
+			continue
+		}
+
+		events = append(events, Event{Type: EventArchived, Source: sourceID, Page: page, Previous: &prev})
+	}
+
+	return events, nil
+}
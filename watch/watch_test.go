@@ -0,0 +1,259 @@
+package watch_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/watch"
+)
+
+const databaseID = "39ddfc9d-33c9-404c-89cf-79f01c42dd0c"
+
+func mustPage(t *testing.T, id, title, status, lastEdited string) notion.Page {
+	t.Helper()
+
+	var page notion.Page
+	err := json.Unmarshal([]byte(`{
+		"id": "`+id+`",
+		"last_edited_time": "`+lastEdited+`",
+		"parent": {"type": "database_id", "database_id": "`+databaseID+`"},
+		"properties": {
+			"Name": {"id": "title", "type": "title", "title": [{"type": "text", "text": {"content": "`+title+`"}, "plain_text": "`+title+`"}]},
+			"Status": {"id": "a", "type": "select", "select": {"name": "`+status+`"}}
+		}
+	}`), &page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return page
+}
+
+func TestWatcherDatabase(t *testing.T) {
+	t.Parallel()
+
+	var results [][]notion.Page
+	var queries []*notion.DatabaseQuery
+
+	client := &notion.MockClient{
+		QueryDatabaseFunc: func(ctx context.Context, id string, query *notion.DatabaseQuery) (notion.DatabaseQueryResponse, error) {
+			q := *query
+			queries = append(queries, &q)
+			resp := notion.DatabaseQueryResponse{Results: results[0]}
+			results = results[1:]
+			return resp, nil
+		},
+		FindPageByIDFunc: func(ctx context.Context, id string) (notion.Page, error) {
+			return notion.Page{}, notion.ErrObjectNotFound
+		},
+	}
+
+	store := watch.NewMemoryStore()
+	w := watch.New(watch.Options{
+		Client:           client,
+		Store:            store,
+		Buffer:           10,
+		FullScanInterval: 2,
+	})
+	w.WatchDatabase(databaseID)
+
+	pageA := mustPage(t, "a", "A", "Todo", "2022-05-01T10:00:00.000Z")
+	pageB := mustPage(t, "b", "B", "Todo", "2022-05-01T11:00:00.000Z")
+	updatedA := mustPage(t, "a", "A", "Done", "2022-05-02T09:00:00.000Z")
+	pageC := mustPage(t, "c", "C", "Todo", "2022-05-02T10:00:00.000Z")
+
+	results = [][]notion.Page{
+		// Initial poll, a full scan.
+		{pageA, pageB},
+		// Incremental poll; B is unchanged, but edited in the cursor minute.
+		{pageB, updatedA, pageC},
+		// Full scan; B was deleted.
+		{updatedA, pageC},
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := w.Poll(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if queries[0].Filter != nil || queries[2].Filter != nil {
+		t.Error("expected full scans without filter")
+	}
+	cursor := pageB.LastEditedTime
+	expFilter := &notion.DatabaseQueryFilter{
+		Timestamp: notion.TimestampLastEditedTime,
+		DatabaseQueryPropertyFilter: notion.DatabaseQueryPropertyFilter{
+			LastEditedTime: &notion.DatePropertyFilter{OnOrAfter: &cursor},
+		},
+	}
+	if diff := cmp.Diff(expFilter, queries[1].Filter); diff != "" {
+		t.Errorf("filter not equal (-exp, +got):\n%v", diff)
+	}
+
+	type event struct {
+		Type    watch.EventType
+		PageID  string
+		Changes []string
+	}
+
+	var got []event
+	for len(w.Events()) > 0 {
+		e := <-w.Events()
+		var changes []string
+		for _, prop := range e.Diff.Properties {
+			changes = append(changes, prop.Name)
+		}
+		got = append(got, event{Type: e.Type, PageID: e.Page.ID, Changes: changes})
+	}
+
+	exp := []event{
+		{Type: watch.EventUpdated, PageID: "a", Changes: []string{"Status"}},
+		{Type: watch.EventCreated, PageID: "c"},
+		{Type: watch.EventArchived, PageID: "b"},
+	}
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Errorf("events not equal (-exp, +got):\n%v", diff)
+	}
+
+	cp, err := store.Load(ctx, databaseID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp, got := pageC.LastEditedTime, cp.Cursor; !exp.Equal(got) {
+		t.Errorf("cursor not equal (expected: %v, got: %v)", exp, got)
+	}
+	if exp, got := 2, len(cp.Pages); exp != got {
+		t.Errorf("snapshot count not equal (expected: %v, got: %v)", exp, got)
+	}
+}
+
+func TestWatcherBackpressure(t *testing.T) {
+	t.Parallel()
+
+	client := &notion.MockClient{
+		QueryDatabaseFunc: func(ctx context.Context, id string, query *notion.DatabaseQuery) (notion.DatabaseQueryResponse, error) {
+			return notion.DatabaseQueryResponse{Results: []notion.Page{
+				mustPage(t, "a", "A", "Todo", "2022-05-01T10:00:00.000Z"),
+			}}, nil
+		},
+	}
+
+	store := watch.NewMemoryStore()
+	w := watch.New(watch.Options{Client: client, Store: store, EmitInitial: true})
+	w.WatchDatabase(databaseID)
+
+	// Nobody consumes the unbuffered events channel, so the poll blocks until
+	// the context is done, without saving a checkpoint.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := w.Poll(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got: %v", err)
+	}
+
+	cp, err := store.Load(context.Background(), databaseID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cp != nil {
+		t.Errorf("expected no checkpoint, got: %+v", cp)
+	}
+}
+
+func TestWatcherCanceledDelivery(t *testing.T) {
+	t.Parallel()
+
+	results := [][]notion.Page{
+		{mustPage(t, "a", "A", "Todo", "2022-05-01T10:00:00.000Z")},
+	}
+	updated := mustPage(t, "a", "A", "Done", "2022-05-02T09:00:00.000Z")
+
+	client := &notion.MockClient{
+		QueryDatabaseFunc: func(ctx context.Context, id string, query *notion.DatabaseQuery) (notion.DatabaseQueryResponse, error) {
+			if len(results) > 0 {
+				resp := notion.DatabaseQueryResponse{Results: results[0]}
+				results = results[1:]
+				return resp, nil
+			}
+			return notion.DatabaseQueryResponse{Results: []notion.Page{updated}}, nil
+		},
+	}
+
+	w := watch.New(watch.Options{Client: client})
+	w.WatchDatabase(databaseID)
+
+	// The initial poll doesn't emit events, so it saves a checkpoint.
+	if err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The update isn't consumed, so the poll is canceled during delivery.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := w.Poll(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got: %v", err)
+	}
+
+	// The next poll delivers the update again, compared to the saved
+	// checkpoint.
+	errc := make(chan error, 1)
+	go func() {
+		errc <- w.Poll(context.Background())
+	}()
+
+	var e watch.Event
+	select {
+	case e = <-w.Events():
+	case err := <-errc:
+		t.Fatalf("expected event, poll returned: %v", err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if exp, got := watch.EventUpdated, e.Type; exp != got {
+		t.Errorf("event type not equal (expected: %v, got: %v)", exp, got)
+	}
+	if e.Previous == nil || e.Previous.Properties.(notion.DatabasePageProperties)["Status"].Select.Name != "Todo" {
+		t.Errorf("expected previous status Todo, got: %+v", e.Previous)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := watch.FileStore{Dir: t.TempDir()}
+
+	cp, err := store.Load(ctx, databaseID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cp != nil {
+		t.Fatalf("expected no checkpoint, got: %+v", cp)
+	}
+
+	page := mustPage(t, "a", "A", "Todo", "2022-05-01T10:00:00.000Z")
+	exp := watch.Checkpoint{
+		Cursor: page.LastEditedTime,
+		Pages:  map[string]notion.Page{page.ID: page},
+	}
+	if err := store.Save(ctx, databaseID, exp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cp, err = store.Load(ctx, databaseID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(exp, *cp); diff != "" {
+		t.Errorf("checkpoint not equal (-exp, +got):\n%v", diff)
+	}
+}
@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/skedida/go-notion"
+)
+
+// EventType is the type of a webhook event.
+// See: https://developers.notion.com/reference/webhooks-events-delivery
+type EventType string
+
+const (
+	EventTypePageCreated           EventType = "page.created"
+	EventTypePageContentUpdated    EventType = "page.content_updated"
+	EventTypePagePropertiesUpdated EventType = "page.properties_updated"
+	EventTypePageMoved             EventType = "page.moved"
+	EventTypePageDeleted           EventType = "page.deleted"
+	EventTypePageUndeleted         EventType = "page.undeleted"
+	EventTypePageLocked            EventType = "page.locked"
+	EventTypePageUnlocked          EventType = "page.unlocked"
+
+	EventTypeDatabaseCreated        EventType = "database.created"
+	EventTypeDatabaseContentUpdated EventType = "database.content_updated"
+	EventTypeDatabaseSchemaUpdated  EventType = "database.schema_updated"
+	EventTypeDatabaseMoved          EventType = "database.moved"
+	EventTypeDatabaseDeleted        EventType = "database.deleted"
+	EventTypeDatabaseUndeleted      EventType = "database.undeleted"
+
+	EventTypeCommentCreated EventType = "comment.created"
+	EventTypeCommentUpdated EventType = "comment.updated"
+	EventTypeCommentDeleted EventType = "comment.deleted"
+)
+
+// Resource returns the resource type of the event type, e.g. "page" for
+// `page.created`.
+func (t EventType) Resource() string {
+	resource, _, _ := strings.Cut(string(t), ".")
+	return resource
+}
+
+// deleted returns true for event types of which the entity can't be fetched.
+func (t EventType) deleted() bool {
+	return strings.HasSuffix(string(t), ".deleted")
+}
+
+// Event is a webhook event, as delivered. The typed events (PageEvent,
+// DatabaseEvent and CommentEvent) decode its Data.
+type Event struct {
+	ID             string          `json:"id"`
+	Type           EventType       `json:"type"`
+	Timestamp      time.Time       `json:"timestamp"`
+	WorkspaceID    string          `json:"workspace_id"`
+	WorkspaceName  string          `json:"workspace_name"`
+	SubscriptionID string          `json:"subscription_id"`
+	IntegrationID  string          `json:"integration_id"`
+	Authors        []Author        `json:"authors"`
+	AttemptNumber  int             `json:"attempt_number"`
+	Entity         Entity          `json:"entity"`
+	Data           json.RawMessage `json:"data,omitempty"`
+}
+
+// Author is a user or bot that caused an event.
+type Author struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// Entity references the object of an event, or a parent or updated block.
+type Entity struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// PageEvent is an event of type `page.*`.
+type PageEvent struct {
+	Event
+
+	Parent            Entity   `json:"-"`
+	UpdatedBlocks     []Entity `json:"-"`
+	UpdatedProperties []string `json:"-"`
+
+	// Page is the page the event is about, if the handler has a client, and
+	// the page wasn't deleted.
+	Page *notion.Page `json:"-"`
+}
+
+// PropertyChange is a change of a database property.
+type PropertyChange struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+// DatabaseEvent is an event of type `database.*`.
+type DatabaseEvent struct {
+	Event
+
+	Parent            Entity           `json:"-"`
+	UpdatedProperties []PropertyChange `json:"-"`
+
+	// Database is the database the event is about, if the handler has a
+	// client, and the database wasn't deleted.
+	Database *notion.Database `json:"-"`
+}
+
+// CommentEvent is an event of type `comment.*`.
+type CommentEvent struct {
+	Event
+
+	PageID string `json:"-"`
+	Parent Entity `json:"-"`
+
+	// Comment is the comment the event is about, if the handler has a client,
+	// and the comment wasn't deleted.
+	Comment *notion.Comment `json:"-"`
+}
+
+func (e *PageEvent) decodeData() error {
+	var data struct {
+		Parent            Entity   `json:"parent"`
+		UpdatedBlocks     []Entity `json:"updated_blocks"`
+		UpdatedProperties []string `json:"updated_properties"`
+	}
+	if err := decodeData(e.Data, &data); err != nil {
+		return err
+	}
+
+	e.Parent = data.Parent
+	e.UpdatedBlocks = data.UpdatedBlocks
+	e.UpdatedProperties = data.UpdatedProperties
+
+	return nil
+}
+
+func (e *DatabaseEvent) decodeData() error {
+	var data struct {
+		Parent            Entity           `json:"parent"`
+		UpdatedProperties []PropertyChange `json:"updated_properties"`
+	}
+	if err := decodeData(e.Data, &data); err != nil {
+		return err
+	}
+
+	e.Parent = data.Parent
+	e.UpdatedProperties = data.UpdatedProperties
+
+	return nil
+}
+
+func (e *CommentEvent) decodeData() error {
+	var data struct {
+		PageID string `json:"page_id"`
+		Parent Entity `json:"parent"`
+	}
+	if err := decodeData(e.Data, &data); err != nil {
+		return err
+	}
+
+	e.PageID = data.PageID
+	e.Parent = data.Parent
+
+	return nil
+}
+
+func decodeData(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,419 @@
+// Package webhook receives Notion webhook events: it handles the verification
+// handshake, verifies signatures, decodes events into typed structs,
+// deduplicates redeliveries and dispatches events to handlers.
+//
+// See: https://developers.notion.com/reference/webhooks
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/skedida/go-notion"
+)
+
+const (
+	// SignatureHeader is the header with the signature of a request body.
+	SignatureHeader = "X-Notion-Signature"
+
+	defaultDedupeSize = 1000
+	maxBodySize       = 1 << 20
+)
+
+// ErrInvalidSignature is used for requests with a missing or invalid signature.
+var ErrInvalidSignature = errors.New("webhook: invalid signature")
+
+// Client is the subset of the Notion API used to fetch the objects of events.
+type Client interface {
+	FindPageByID(ctx context.Context, id string) (notion.Page, error)
+	FindDatabaseByID(ctx context.Context, id string) (notion.Database, error)
+	FindCommentsByBlockID(ctx context.Context, query notion.FindCommentsByBlockIDQuery) (notion.FindCommentsResponse, error)
+}
+
+// Options configure a Handler.
+type Options struct {
+	// VerificationToken is the token received in the verification handshake,
+	// used to verify the signature of events. Events are rejected while it's
+	// empty. It can be changed later with Handler.SetVerificationToken.
+	VerificationToken string
+
+	// OnVerification is called with the token of a verification request, sent
+	// by Notion when a webhook subscription is created. The token must be
+	// entered in the integration settings, and set with
+	// Handler.SetVerificationToken (or as VerificationToken of a new Handler).
+	// Verification requests are only handled while the verification token is
+	// empty; afterwards, all requests must be signed.
+	OnVerification func(token string)
+
+	// Client is used to fetch the page, database or comment of events, if set.
+	// See PageEvent.Page, DatabaseEvent.Database and CommentEvent.Comment.
+	Client Client
+
+	// DedupeSize is the number of recent event IDs that are remembered, to
+	// ignore redeliveries of handled events. Defaults to 1000.
+	DedupeSize int
+
+	// OnError is called with errors of handlers and invalid requests, e.g. for
+	// logging.
+	OnError func(err error)
+}
+
+// Handler is an http.Handler for webhook requests. Handlers for events are
+// called in order of registration, in the request goroutine. If a handler
+// returns an error, the request fails with status 500, so Notion redelivers
+// the event.
+type Handler struct {
+	opts   Options
+	dedupe *dedupe
+
+	mu                sync.RWMutex
+	handlers          []handler
+	verificationToken string
+}
+
+type handler struct {
+	// resource is the event type resource handled by fn, e.g. "page", or empty
+	// for a handler of untyped events.
+	resource string
+	types    []EventType
+	fn       func(ctx context.Context, e interface{}) error
+}
+
+func (h handler) handles(t EventType) bool {
+	if h.resource != "" && h.resource != t.Resource() {
+		return false
+	}
+	if len(h.types) == 0 {
+		return true
+	}
+	for _, typ := range h.types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+// NewHandler returns a new Handler.
+func NewHandler(opts Options) *Handler {
+	if opts.DedupeSize <= 0 {
+		opts.DedupeSize = defaultDedupeSize
+	}
+
+	return &Handler{
+		opts:              opts,
+		dedupe:            newDedupe(opts.DedupeSize),
+		verificationToken: opts.VerificationToken,
+	}
+}
+
+// SetVerificationToken sets the token used to verify the signature of events,
+// e.g. with the token passed to Options.OnVerification. It's safe for
+// concurrent use with ServeHTTP.
+func (h *Handler) SetVerificationToken(token string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.verificationToken = token
+}
+
+// Handle registers a handler for all events.
+func (h *Handler) Handle(fn func(ctx context.Context, e Event) error) {
+	h.register(handler{fn: func(ctx context.Context, e interface{}) error {
+		return fn(ctx, e.(Event))
+	}})
+}
+
+// HandlePage registers a handler for page events of the given types, or all
+// page events if no types are given.
+func (h *Handler) HandlePage(fn func(ctx context.Context, e PageEvent) error, types ...EventType) {
+	h.register(handler{resource: "page", types: types, fn: func(ctx context.Context, e interface{}) error {
+		return fn(ctx, *e.(*PageEvent))
+	}})
+}
+
+// HandleDatabase registers a handler for database events of the given types,
+// or all database events if no types are given.
+func (h *Handler) HandleDatabase(fn func(ctx context.Context, e DatabaseEvent) error, types ...EventType) {
+	h.register(handler{resource: "database", types: types, fn: func(ctx context.Context, e interface{}) error {
+		return fn(ctx, *e.(*DatabaseEvent))
+	}})
+}
+
+// HandleComment registers a handler for comment events of the given types, or
+// all comment events if no types are given.
+func (h *Handler) HandleComment(fn func(ctx context.Context, e CommentEvent) error, types ...EventType) {
+	h.register(handler{resource: "comment", types: types, fn: func(ctx context.Context, e interface{}) error {
+		return fn(ctx, *e.(*CommentEvent))
+	}})
+}
+
+func (h *Handler) register(hd handler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.handlers = append(h.handlers, hd)
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		h.fail(w, http.StatusBadRequest, fmt.Errorf("webhook: failed to read body: %w", err))
+		return
+	}
+
+	h.mu.RLock()
+	token := h.verificationToken
+	h.mu.RUnlock()
+
+	// Once the verification token is known, verification requests are no
+	// longer accepted, so a forged (unsigned) one can't reach OnVerification.
+	if token == "" {
+		var verification struct {
+			VerificationToken string `json:"verification_token"`
+		}
+		if err := json.Unmarshal(body, &verification); err != nil {
+			h.fail(w, http.StatusBadRequest, fmt.Errorf("webhook: invalid JSON: %w", err))
+			return
+		}
+		if verification.VerificationToken != "" {
+			if h.opts.OnVerification != nil {
+				h.opts.OnVerification(verification.VerificationToken)
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	if !VerifySignature(token, body, r.Header.Get(SignatureHeader)) {
+		h.fail(w, http.StatusUnauthorized, ErrInvalidSignature)
+		return
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		h.fail(w, http.StatusBadRequest, fmt.Errorf("webhook: failed to decode event: %w", err))
+		return
+	}
+
+	if event.ID != "" && !h.dedupe.add(event.ID) {
+		// Redelivery of an event that is (being) handled.
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := h.dispatch(r.Context(), event); err != nil {
+		h.dedupe.remove(event.ID)
+		h.fail(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// VerifySignature reports whether signature, as sent in the X-Notion-Signature
+// header, is the HMAC-SHA256 of body with the verification token as key,
+// formatted as `sha256=<hex>`.
+func VerifySignature(verificationToken string, body []byte, signature string) bool {
+	if verificationToken == "" {
+		return false
+	}
+
+	hexSum := strings.TrimPrefix(signature, "sha256=")
+	if hexSum == signature {
+		return false
+	}
+	sum, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(sum, Sign(verificationToken, body))
+}
+
+// Sign returns the HMAC-SHA256 of body with the verification token as key.
+func Sign(verificationToken string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(verificationToken))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+func (h *Handler) dispatch(ctx context.Context, event Event) error {
+	h.mu.RLock()
+	handlers := h.handlers
+	h.mu.RUnlock()
+
+	// The typed event is decoded (and its object fetched) once, when needed.
+	var typed interface{}
+
+	for _, hd := range handlers {
+		if !hd.handles(event.Type) {
+			continue
+		}
+
+		var e interface{} = event
+		if hd.resource != "" {
+			if typed == nil {
+				var err error
+				if typed, err = h.typedEvent(ctx, event); err != nil {
+					return fmt.Errorf("webhook: failed to decode %v event %v: %w", event.Type, event.ID, err)
+				}
+			}
+			e = typed
+		}
+
+		if err := hd.fn(ctx, e); err != nil {
+			return fmt.Errorf("webhook: failed to handle %v event %v: %w", event.Type, event.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// typedEvent returns a *PageEvent, *DatabaseEvent or *CommentEvent, with the
+// object of the event if the handler has a client.
+func (h *Handler) typedEvent(ctx context.Context, event Event) (interface{}, error) {
+	fetch := h.opts.Client != nil && !event.Type.deleted()
+
+	switch event.Type.Resource() {
+	case "page":
+		e := &PageEvent{Event: event}
+		if err := e.decodeData(); err != nil {
+			return nil, err
+		}
+		if fetch {
+			page, err := h.opts.Client.FindPageByID(ctx, event.Entity.ID)
+			if err != nil {
+				return nil, err
+			}
+			e.Page = &page
+		}
+		return e, nil
+	case "database":
+		e := &DatabaseEvent{Event: event}
+		if err := e.decodeData(); err != nil {
+			return nil, err
+		}
+		if fetch {
+			db, err := h.opts.Client.FindDatabaseByID(ctx, event.Entity.ID)
+			if err != nil {
+				return nil, err
+			}
+			e.Database = &db
+		}
+		return e, nil
+	case "comment":
+		e := &CommentEvent{Event: event}
+		if err := e.decodeData(); err != nil {
+			return nil, err
+		}
+		if fetch {
+			comment, err := h.findComment(ctx, e)
+			if err != nil {
+				return nil, err
+			}
+			e.Comment = comment
+		}
+		return e, nil
+	default:
+		return nil, fmt.Errorf("unknown event type %q", event.Type)
+	}
+}
+
+// findComment finds a comment among the comments of its parent block or page,
+// as comments can't be fetched by ID.
+func (h *Handler) findComment(ctx context.Context, e *CommentEvent) (*notion.Comment, error) {
+	blockID := e.Parent.ID
+	if blockID == "" {
+		blockID = e.PageID
+	}
+
+	query := notion.FindCommentsByBlockIDQuery{BlockID: blockID}
+
+	for {
+		resp, err := h.opts.Client.FindCommentsByBlockID(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range resp.Results {
+			if resp.Results[i].ID == e.Entity.ID {
+				return &resp.Results[i], nil
+			}
+		}
+
+		if !resp.HasMore || resp.NextCursor == nil {
+			return nil, nil
+		}
+		query.StartCursor = *resp.NextCursor
+	}
+}
+
+func (h *Handler) fail(w http.ResponseWriter, status int, err error) {
+	if h.opts.OnError != nil {
+		h.opts.OnError(err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+// dedupe remembers a bounded number of recent event IDs.
+type dedupe struct {
+	mu   sync.Mutex
+	ids  map[string]bool
+	ring []string
+	next int
+}
+
+func newDedupe(size int) *dedupe {
+	return &dedupe{
+		ids:  make(map[string]bool, size),
+		ring: make([]string, size),
+	}
+}
+
+// add returns false if the ID was added already.
+func (d *dedupe) add(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.ids[id] {
+		return false
+	}
+
+	if old := d.ring[d.next]; old != "" {
+		delete(d.ids, old)
+	}
+	d.ring[d.next] = id
+	d.next = (d.next + 1) % len(d.ring)
+	d.ids[id] = true
+
+	return true
+}
+
+func (d *dedupe) remove(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.ids, id)
+	for i := range d.ring {
+		if d.ring[i] == id {
+			d.ring[i] = ""
+		}
+	}
+}
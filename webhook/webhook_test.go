@@ -0,0 +1,229 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skedida/go-notion"
+	"github.com/skedida/go-notion/webhook"
+)
+
+const verificationToken = "secret_tMrlL1qK5vuQAh1b6cZGhFChZTSYJlce98V0pYn7yBl"
+
+const pageCreatedEvent = `{
+	"id": "367cba44-b6f3-4c92-81e7-6a2e9659efd4",
+	"timestamp": "2024-12-05T23:55:34.285Z",
+	"workspace_id": "13950b26-c203-4f3b-b97d-93ec06319565",
+	"workspace_name": "Quantify Labs",
+	"subscription_id": "29d75c0d-5546-4414-8459-7b7a92f1fc4b",
+	"integration_id": "0ef2e755-4912-8096-91c1-00376a88a5ca",
+	"type": "page.created",
+	"authors": [{"id": "c7c11cca-1d73-471d-9b6e-bdef51470190", "type": "person"}],
+	"attempt_number": 1,
+	"entity": {"id": "153104cd-477e-809d-8dc4-ff2d96ae3090", "type": "page"},
+	"data": {"parent": {"id": "13950b26-c203-4f3b-b97d-93ec06319565", "type": "space"}}
+}`
+
+func signedRequest(t *testing.T, body string) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set(webhook.SignatureHeader, "sha256="+hex.EncodeToString(webhook.Sign(verificationToken, []byte(body))))
+
+	return req
+}
+
+func TestHandlerVerification(t *testing.T) {
+	t.Parallel()
+
+	var token string
+	h := webhook.NewHandler(webhook.Options{
+		OnVerification: func(tok string) { token = tok },
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"verification_token": "`+verificationToken+`"}`)))
+
+	if exp, got := http.StatusOK, rec.Code; exp != got {
+		t.Errorf("status code not equal (expected: %v, got: %v)", exp, got)
+	}
+	if exp, got := verificationToken, token; exp != got {
+		t.Errorf("verification token not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	// Once the token is set, verification requests must be signed too, and
+	// signed events are accepted.
+	h.SetVerificationToken(token)
+	token = ""
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"verification_token": "forged"}`)))
+
+	if exp, got := http.StatusUnauthorized, rec.Code; exp != got {
+		t.Errorf("status code not equal (expected: %v, got: %v)", exp, got)
+	}
+	if token != "" {
+		t.Errorf("unexpected verification token: %v", token)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, signedRequest(t, pageCreatedEvent))
+
+	if exp, got := http.StatusOK, rec.Code; exp != got {
+		t.Errorf("status code not equal (expected: %v, got: %v)", exp, got)
+	}
+}
+
+func TestHandlerSignature(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		signature string
+		expStatus int
+	}{
+		{
+			name:      "valid signature",
+			signature: "sha256=" + hex.EncodeToString(webhook.Sign(verificationToken, []byte(pageCreatedEvent))),
+			expStatus: http.StatusOK,
+		},
+		{
+			name:      "invalid signature",
+			signature: "sha256=" + hex.EncodeToString(webhook.Sign("other", []byte(pageCreatedEvent))),
+			expStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "missing prefix",
+			signature: hex.EncodeToString(webhook.Sign(verificationToken, []byte(pageCreatedEvent))),
+			expStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "missing signature",
+			expStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotErr error
+			h := webhook.NewHandler(webhook.Options{
+				VerificationToken: verificationToken,
+				OnError:           func(err error) { gotErr = err },
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(pageCreatedEvent))
+			if tt.signature != "" {
+				req.Header.Set(webhook.SignatureHeader, tt.signature)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if tt.expStatus != rec.Code {
+				t.Errorf("status code not equal (expected: %v, got: %v)", tt.expStatus, rec.Code)
+			}
+			if tt.expStatus == http.StatusUnauthorized && !errors.Is(gotErr, webhook.ErrInvalidSignature) {
+				t.Errorf("expected ErrInvalidSignature, got: %v", gotErr)
+			}
+		})
+	}
+}
+
+func TestHandlerDispatch(t *testing.T) {
+	t.Parallel()
+
+	client := &notion.MockClient{
+		FindPageByIDFunc: func(ctx context.Context, id string) (notion.Page, error) {
+			return notion.Page{ID: id}, nil
+		},
+	}
+
+	h := webhook.NewHandler(webhook.Options{
+		VerificationToken: verificationToken,
+		Client:            client,
+	})
+
+	var (
+		events     []webhook.EventType
+		pageEvents []webhook.PageEvent
+	)
+	h.Handle(func(ctx context.Context, e webhook.Event) error {
+		events = append(events, e.Type)
+		return nil
+	})
+	h.HandlePage(func(ctx context.Context, e webhook.PageEvent) error {
+		pageEvents = append(pageEvents, e)
+		return nil
+	}, webhook.EventTypePageCreated)
+	h.HandlePage(func(ctx context.Context, e webhook.PageEvent) error {
+		t.Error("unexpected call of page deleted handler")
+		return nil
+	}, webhook.EventTypePageDeleted)
+	h.HandleDatabase(func(ctx context.Context, e webhook.DatabaseEvent) error {
+		t.Error("unexpected call of database handler")
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, signedRequest(t, pageCreatedEvent))
+
+	if exp, got := http.StatusOK, rec.Code; exp != got {
+		t.Fatalf("status code not equal (expected: %v, got: %v)", exp, got)
+	}
+
+	if diff := cmp.Diff([]webhook.EventType{webhook.EventTypePageCreated}, events); diff != "" {
+		t.Errorf("events not equal (-exp, +got):\n%v", diff)
+	}
+
+	if len(pageEvents) != 1 {
+		t.Fatalf("expected 1 page event, got: %v", len(pageEvents))
+	}
+	e := pageEvents[0]
+	if exp, got := (webhook.Entity{ID: "13950b26-c203-4f3b-b97d-93ec06319565", Type: "space"}), e.Parent; exp != got {
+		t.Errorf("parent not equal (expected: %+v, got: %+v)", exp, got)
+	}
+	if e.Page == nil || e.Page.ID != "153104cd-477e-809d-8dc4-ff2d96ae3090" {
+		t.Errorf("unexpected page: %+v", e.Page)
+	}
+	if exp, got := 1, len(client.CallsTo("FindPageByID")); exp != got {
+		t.Errorf("FindPageByID calls not equal (expected: %v, got: %v)", exp, got)
+	}
+}
+
+func TestHandlerDedupe(t *testing.T) {
+	t.Parallel()
+
+	h := webhook.NewHandler(webhook.Options{VerificationToken: verificationToken})
+
+	calls := 0
+	h.Handle(func(ctx context.Context, e webhook.Event) error {
+		calls++
+		if calls == 1 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+
+	// A failed event is handled again when redelivered, a handled one isn't.
+	for i, expStatus := range []int{http.StatusInternalServerError, http.StatusOK, http.StatusOK} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, signedRequest(t, pageCreatedEvent))
+
+		if expStatus != rec.Code {
+			t.Errorf("delivery %v: status code not equal (expected: %v, got: %v)", i, expStatus, rec.Code)
+		}
+	}
+
+	if exp, got := 2, calls; exp != got {
+		t.Errorf("handler calls not equal (expected: %v, got: %v)", exp, got)
+	}
+}